@@ -0,0 +1,22 @@
+// Команда gallery перерисовывает картинки документации:
+//
+//	go generate ./cmd/gallery
+package main
+
+import (
+	"flag"
+	"log"
+
+	"electric-field/internal/app"
+)
+
+//go:generate go run . -out ../../docs/images
+
+func main() {
+	out := flag.String("out", "docs/images", "directory for the rendered gallery images")
+	flag.Parse()
+
+	if err := app.RunGallery(*out); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -14,4 +14,5 @@ require (
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
 )
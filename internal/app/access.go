@@ -0,0 +1,77 @@
+package app
+
+import (
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Доступность. Режим для дальтоников перекрашивает наложения палитрой
+// Окабе — Ито (заряды, пробный заряд, стрелки, линии) и различает знак
+// заряда ещё и формой: положительный — круг, отрицательный — квадрат.
+// Масштаб интерфейса увеличивает только интерфейс: шрифт пересоздаётся
+// в uiScale раз крупнее (Go Mono векторный и остаётся чётким, в отличие
+// от растрового 7×13), а размеры панелей и отступы подсказок, заданные
+// для масштаба 1, проходят через ui. Сцена, теплокарта и экспорт
+// по-прежнему рисуются в полном разрешении окна.
+
+var (
+	okabeOrange     = color.RGBA{230, 159, 0, 255}
+	okabeSkyBlue    = color.RGBA{86, 180, 233, 255}
+	okabeGreen      = color.RGBA{0, 158, 115, 255}
+	okabeYellow     = color.RGBA{240, 228, 66, 255}
+	okabeBlue       = color.RGBA{0, 114, 178, 255}
+	okabeVermillion = color.RGBA{213, 94, 0, 255}
+)
+
+// colorblindTheme — тема th с цветами наложений из палитры Окабе — Ито.
+// На светлом фоне жёлтый и голубой плохо видны, поэтому берутся более
+// тёмные оттенки.
+func colorblindTheme(th Theme) Theme {
+	th.Positive, th.Negative = okabeVermillion, okabeBlue
+	th.Test, th.Arrows = okabeYellow, withAlpha(okabeGreen, th.Arrows.A)
+	if th.InvertHeat {
+		th.Test = okabeOrange
+	} else {
+		th.Negative = okabeSkyBlue
+	}
+	return th
+}
+
+var uiScales = []float64{1, 1.5, 2, 3}
+
+// uiScale — во сколько раз интерфейс крупнее исходного; меняется только
+// через setUIScale.
+var uiScale = 1.0
+
+func setUIScale(s float64) {
+	uiScale = s
+	uiFace = newUIFace(uiFontSize * s)
+}
+
+func cycleUIScale() {
+	i := slices.Index(uiScales, uiScale)
+	setUIScale(uiScales[(i+1)%len(uiScales)])
+}
+
+// ui переводит размер элемента интерфейса при масштабе 1 в пиксели окна.
+func ui(v int) int {
+	return int(math.Round(float64(v) * uiScale))
+}
+
+// uif — то же для координат vector.
+func uif(v float32) float32 {
+	return v * float32(uiScale)
+}
+
+// drawNegativeGlyph — квадратный значок отрицательного заряда для режима
+// дальтоников; знак «−» рисует drawChargeGlyph.
+func drawNegativeGlyph(dst *ebiten.Image, x, y, r float32, col color.RGBA, a float32) {
+	s := r * 0.9 // на глаз квадрат такого размера равен кругу радиуса r
+	fill := color.RGBA{uint8(float32(col.R) * a), uint8(float32(col.G) * a), uint8(float32(col.B) * a), uint8(float32(col.A) * a)}
+	vector.DrawFilledRect(dst, x-s, y-s, 2*s, 2*s, fill, true)
+	vector.StrokeRect(dst, x-s, y-s, 2*s, 2*s, max(1, r/8), color.RGBA{0, 0, 0, uint8(120 * a)}, true)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/colormap"
+)
+
+// Стрелки сетки. Цвет: фиксированный зелёный или по модулю поля (синий —
+// слабое, красный — сильное). Длина: постоянная (только направление),
+// пропорциональная |E| или log|E|. Шаг сетки меняется на ходу.
+
+type ArrowStyle int
+
+const (
+	ArrowPlain ArrowStyle = iota
+	ArrowColored
+	ArrowHidden
+	arrowStyleCount
+)
+
+func (s ArrowStyle) String() string {
+	switch s {
+	case ArrowColored:
+		return tr("colored by |E|")
+	case ArrowHidden:
+		return tr("hidden")
+	default:
+		return tr("plain")
+	}
+}
+
+type ArrowScaling int
+
+const (
+	ArrowFixed ArrowScaling = iota
+	ArrowLinear
+	ArrowLog
+	arrowScalingCount
+)
+
+func (s ArrowScaling) String() string {
+	switch s {
+	case ArrowLinear:
+		return tr("length ~ |E|")
+	case ArrowLog:
+		return tr("length ~ log|E|")
+	default:
+		return tr("direction only")
+	}
+}
+
+var arrowGridSteps = []int{20, 30, 40, 60, 80} // пикс экрана
+
+const (
+	arrowLen    = 15.0
+	arrowMinLen = 5.0
+	arrowMaxLen = 0.7  // доля шага сетки, чтобы соседние стрелки не налезали
+	arrowLinE   = 2.0  // |E|, при котором линейная стрелка достигает arrowMaxLen
+	arrowEMin   = 0.05 // |E| нижнего края градиента
+	arrowEMax   = 50.0 // |E| верхнего края градиента
+
+	arrowHeadLen   = 6.0
+	arrowHeadAngle = 0.6
+	lineArrowGap   = 90.0 // расстояние между стрелками на силовой линии, пикс экрана
+)
+
+// arrowLevel — положение |E| на логарифмической шкале [arrowEMin, arrowEMax].
+func arrowLevel(E float64) float64 {
+	t := math.Log(E/arrowEMin) / math.Log(arrowEMax/arrowEMin)
+	return math.Max(0, math.Min(1, t))
+}
+
+func (g *Game) arrowStep() int { return arrowGridSteps[g.arrowStepIndex] }
+
+// arrowLook возвращает длину (пикс интерфейса) и цвет стрелки для поля модуля E.
+func (g *Game) arrowLook(E float64) (float64, color.RGBA) {
+	t := arrowLevel(E)
+	col := g.theme().Arrows
+	if g.arrowStyle == ArrowColored {
+		col = colormap.Coolwarm.At(t)
+		col.A = 220
+	}
+
+	maxLen := arrowMaxLen * float64(g.arrowStep())
+	switch g.arrowScaling {
+	case ArrowLinear:
+		return maxLen * math.Min(1, E/arrowLinE), col
+	case ArrowLog:
+		return arrowMinLen + (maxLen-arrowMinLen)*t, col
+	default:
+		return math.Min(arrowLen, maxLen), col
+	}
+}
+
+// drawArrowhead рисует наконечник с остриём в (x, y), направленный под углом angle.
+func (g *Game) drawArrowhead(screen *ebiten.Image, x, y float32, angle float64, col color.Color) {
+	for _, a := range [2]float64{angle + arrowHeadAngle, angle - arrowHeadAngle} {
+		hx := x - g.cam.px(float32(arrowHeadLen*math.Cos(a)))
+		hy := y - g.cam.px(float32(arrowHeadLen*math.Sin(a)))
+		vector.StrokeLine(screen, x, y, hx, hy, g.cam.px(g.lines.ArrowWidth()), col, g.lines.Antialias)
+	}
+}
+
+// drawLineArrowheads расставляет наконечники вдоль силовых линий через
+// равные промежутки дуги; первая стрелка — в полупромежутке от начала линии.
+func (g *Game) drawLineArrowheads(screen *ebiten.Image) {
+	col := withAlpha(g.theme().FieldLines, 200)
+	for _, line := range g.fieldLines {
+		gap := lineArrowGap * g.cam.Scale / g.cam.Zoom
+		next := gap / 2
+		s := 0.0
+		for i := 1; i < len(line); i++ {
+			a, b := line[i-1], line[i]
+			d := math.Hypot(b.X-a.X, b.Y-a.Y)
+			for next <= s+d && d > 0 {
+				t := (next - s) / d
+				x, y := g.cam.toScreen(a.X+t*(b.X-a.X), a.Y+t*(b.Y-a.Y))
+				g.drawArrowhead(screen, x, y, math.Atan2(b.Y-a.Y, b.X-a.X), col)
+				next += gap
+			}
+			s += d
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Автосохранение: раз в autosaveFrames тиков сцена пишется в каталог
+// настроек, если изменилась. При нормальном выходе файл удаляется, так
+// что найденный при запуске файл означает аварийное завершение — тогда
+// предлагается восстановить сцену. Восстановление идёт через
+// applySceneFile и отменяется Ctrl+Z, как загрузка сцены.
+
+const (
+	autosaveFile   = "autosave.json"
+	autosaveFrames = 30 * 60
+)
+
+type autosaver struct {
+	on     bool // только у левой сцены, правая в разделённом экране — черновик
+	frames int
+	last   []byte // последнее записанное содержимое
+
+	recovery *sceneFile // найденная при запуске сцена ждёт ответа
+	saved    time.Time
+}
+
+func autosavePath() (string, error) {
+	dir, err := prefsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, autosaveFile), nil
+}
+
+func (g *Game) updateAutosave() {
+	a := &g.autosave
+	if !a.on || a.recovery != nil {
+		return
+	}
+	if a.frames++; a.frames < autosaveFrames {
+		return
+	}
+	a.frames = 0
+	data, err := json.MarshalIndent(g.sceneFile(), "", "  ")
+	if err == nil && bytes.Equal(data, a.last) {
+		return
+	}
+	if err == nil {
+		err = writeAutosave(data)
+	}
+	if err != nil {
+		log.Printf("autosave: %v", err)
+		return
+	}
+	a.last = data
+}
+
+// writeAutosave пишет во временный файл и переименовывает его, чтобы
+// сбой посреди записи не испортил прошлое автосохранение.
+func writeAutosave(data []byte) error {
+	path, err := autosavePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// removeAutosave вызывается при нормальном выходе.
+func removeAutosave() {
+	path, err := autosavePath()
+	if err == nil {
+		err = os.Remove(path)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("remove autosave: %v", err)
+	}
+}
+
+// checkRecovery ищет автосохранение прошлого запуска.
+func (g *Game) checkRecovery() {
+	path, err := autosavePath()
+	if err != nil {
+		return
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	s, err := readSceneFile(path)
+	if err != nil {
+		log.Printf("autosave: %v", err)
+		return
+	}
+	g.autosave.recovery = &s
+	g.autosave.saved = st.ModTime()
+}
+
+// updateRecovery ждёт ответа на предложение восстановить сцену и
+// сообщает, что остальной ввод пока не обрабатывается.
+func (g *Game) updateRecovery() bool {
+	a := &g.autosave
+	if a.recovery == nil {
+		return false
+	}
+	switch {
+	case keyJustPressed(ebiten.KeyEnter):
+		g.applySceneFile(*a.recovery)
+		g.notify(fmt.Sprintf(tr("Restored %d charges, Ctrl+%s: undo"), len(g.charges), keyLabel(keyUndo)))
+		a.recovery = nil
+	case keyJustPressed(ebiten.KeyEscape):
+		a.recovery = nil
+	}
+	return true
+}
+
+func (g *Game) drawRecovery(screen *ebiten.Image) {
+	a := &g.autosave
+	if a.recovery == nil {
+		return
+	}
+	th := g.theme()
+	lines := []string{
+		fmt.Sprintf(tr("The previous session did not exit cleanly (autosave %s, %d charges)."),
+			a.saved.Format("2006-01-02 15:04"), len(a.recovery.Charges)),
+		tr("Enter: restore the scene, Esc: discard"),
+	}
+	w := 0
+	for _, l := range lines {
+		w = max(w, textWidth(l))
+	}
+	lh := lineHeight()
+	w += ui(20)
+	h := lh*len(lines) + ui(10)
+	x, y := (g.cam.W-w)/2, (g.cam.H-h)/2
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), withAlpha(th.Panel, 240), false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, th.Border, false)
+	for i, l := range lines {
+		text.Draw(screen, l, uiFace, x+ui(10), baseline(y+ui(5)+lh*i, lh), th.HUD)
+	}
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"math"
+)
+
+// Метод Барнса — Хата для динамики многих зарядов и плазмы: заряды
+// раскладываются по квадродереву, и далёкий узел (размер/расстояние < θ)
+// заменяется монополем и диполем относительно центра |q|. Центр |q|, а не
+// центр заряда: у нейтральных узлов суммарный заряд нулевой и его центр
+// не определён, а диполь как раз и описывает такой узел. θ = 0 — точная
+// сумма; больше θ — быстрее и грубее. Дерево строится заново на каждом
+// шаге: заряды всё равно сдвигаются.
+
+const (
+	bhLeafSize     = 4
+	bhMaxDepth     = 20
+	bhMinCharges   = 64 // меньше — прямая сумма дешевле дерева
+	bhThetaMax     = 1.5
+	defaultBHTheta = 0.5
+	bhThetaExact   = 0.01 // ниже — точная сумма
+)
+
+type bhNode struct {
+	minX, minY, size float64 // квадрат узла
+	cx, cy           float64 // центр |q|
+	q                float64
+	px, py           float64 // дипольный момент относительно (cx, cy)
+	charges          []Charge
+	kids             []*bhNode // nil у листа
+}
+
+func newBHTree(charges []Charge) *bhNode {
+	if len(charges) == 0 {
+		return nil
+	}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, c := range charges {
+		minX, maxX = math.Min(minX, c.X), math.Max(maxX, c.X)
+		minY, maxY = math.Min(minY, c.Y), math.Max(maxY, c.Y)
+	}
+	n := &bhNode{minX: minX, minY: minY, size: math.Max(maxX-minX, maxY-minY), charges: charges}
+	n.build(0)
+	return n
+}
+
+func (n *bhNode) build(depth int) {
+	var w float64
+	for _, c := range n.charges {
+		a := math.Abs(c.Q)
+		n.q += c.Q
+		n.cx += a * c.X
+		n.cy += a * c.Y
+		w += a
+	}
+	if w > 0 {
+		n.cx, n.cy = n.cx/w, n.cy/w
+	} else {
+		n.cx, n.cy = n.minX+n.size/2, n.minY+n.size/2
+	}
+	for _, c := range n.charges {
+		n.px += c.Q * (c.X - n.cx)
+		n.py += c.Q * (c.Y - n.cy)
+	}
+	if len(n.charges) <= bhLeafSize || depth >= bhMaxDepth {
+		return
+	}
+
+	half := n.size / 2
+	var parts [4][]Charge
+	for _, c := range n.charges {
+		q := 0
+		if c.X >= n.minX+half {
+			q++
+		}
+		if c.Y >= n.minY+half {
+			q += 2
+		}
+		parts[q] = append(parts[q], c)
+	}
+	for q, cs := range parts {
+		if len(cs) == 0 {
+			continue
+		}
+		k := &bhNode{minX: n.minX + float64(q%2)*half, minY: n.minY + float64(q/2)*half, size: half, charges: cs}
+		k.build(depth + 1)
+		n.kids = append(n.kids, k)
+	}
+	n.charges = nil
+}
+
+// contains — точка внутри квадрата узла; такой узел всегда раскрывается,
+// иначе заряд в точке действовал бы сам на себя.
+func (n *bhNode) contains(x, y float64) bool {
+	return x >= n.minX && x <= n.minX+n.size && y >= n.minY && y <= n.minY+n.size
+}
+
+// field — поле дерева в точке (x, y) с параметром раскрытия theta.
+func (n *bhNode) field(x, y, theta float64) (float64, float64) {
+	if n.kids == nil {
+		return directField(n.charges, x, y)
+	}
+	dx, dy := x-n.cx, y-n.cy
+	r2 := dx*dx + dy*dy
+	if !n.contains(x, y) && n.size*n.size < theta*theta*r2 {
+		r2 = math.Max(r2, minR2)
+		r := math.Sqrt(r2)
+		r3 := r2 * r
+		pd := 3 * (n.px*dx + n.py*dy) / r2
+		return kConst * (n.q*dx + pd*dx - n.px) / r3, kConst * (n.q*dy + pd*dy - n.py) / r3
+	}
+	var Ex, Ey float64
+	for _, k := range n.kids {
+		ex, ey := k.field(x, y, theta)
+		Ex += ex
+		Ey += ey
+	}
+	return Ex, Ey
+}
+
+func (g *Game) bhLabel() string {
+	if g.bhTheta < bhThetaExact {
+		return tr("Barnes-Hut: exact sum")
+	}
+	return fmt.Sprintf(tr("Barnes-Hut θ %.2f (from %d charges)"), g.bhTheta, bhMinCharges)
+}
+
+// bulkField — поле для шага, на котором его спрашивают во многих точках
+// (все заряды динамики, облако плазмы): дерево Барнса — Хата, если оно
+// включено и зарядов достаточно, иначе fieldAt. Мультиполи перестраиваются
+// по текущим зарядам: это O(N), а по разложениям прошлого кадра заряд
+// чувствовал бы собственное поле из старого положения.
+func (g *Game) bulkField() func(x, y float64) (float64, float64) {
+	if f, ok := g.solver.(*fmmSolver); ok {
+		f.Prepare(g.charges)
+		return f.Field
+	}
+	if g.solver != nil || g.bhTheta < bhThetaExact || len(g.charges) < bhMinCharges {
+		return g.fieldAt
+	}
+	tree, theta := newBHTree(g.charges), g.bhTheta
+	return func(x, y float64) (float64, float64) {
+		return tree.field(x, y, theta)
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"image/color"
+	"math"
+)
+
+// Карта бассейнов притяжения: каждый пиксель окрашен по заряду, к
+// которому приходит пробная частица, отпущенная из этой точки в покое.
+
+const (
+	basinCell     = 3    // сторона ячейки карты в пикселях
+	basinMaxSteps = 3000 // лимит шагов на частицу
+	basinEscapeR  = 1500 // радиус, за которым частица считается улетевшей
+	basinDamping  = 0.05 // минимальное трение, без него частицы колеблются вечно
+)
+
+var basinPalette = []color.RGBA{
+	{230, 25, 75, 255},
+	{60, 180, 75, 255},
+	{255, 225, 25, 255},
+	{0, 130, 200, 255},
+	{245, 130, 48, 255},
+	{145, 30, 180, 255},
+	{70, 240, 240, 255},
+	{240, 50, 230, 255},
+	{210, 245, 60, 255},
+	{250, 190, 212, 255},
+}
+
+// Карта бассейнов — тысячи траекторий до захвата, и пока идёт динамика,
+// пересчитывать её на каждом шаге слишком дорого. Сдвиг зарядов шагом
+// физики карту не трогает: она ждёт паузы, остановки или равновесия.
+// Правка сцены и движение камеры пересчитывают её сразу.
+
+func (g *Game) updateBasins() {
+	if g.bgMotion && g.bgImage != nil && g.cam == g.bgCam {
+		g.basinHeld = true
+		return
+	}
+	g.recomputeBasins()
+	g.basinHeld = false
+}
+
+// releaseBasins досчитывает отложенную карту, когда заряды замерли.
+func (g *Game) releaseBasins() {
+	if g.basinHeld && (g.pause.paused || !g.dynamics || g.equilibrium) {
+		g.basinHeld = false
+		g.dirty = g.dirty || g.bgMode == BackgroundBasins
+	}
+}
+
+func (g *Game) recomputeBasins() {
+	w, h := g.cam.W, g.cam.H
+	cols := (w + basinCell - 1) / basinCell
+	rows := (h + basinCell - 1) / basinCell
+
+	// отдельная копия без трения пользователя ниже basinDamping
+	sim := &Game{charges: g.charges, damping: math.Max(g.damping, basinDamping), relativistic: g.relativistic}
+
+	pix := g.bgPixels()
+	parallelRows(rows, func(row int) {
+		for col := 0; col < cols; col++ {
+			x, y := g.cam.toWorld(float64(col*basinCell+basinCell/2), float64(row*basinCell+basinCell/2))
+
+			o := sim.runToTermination(Particle{X: x, Y: y, Live: true}, seedRadius, basinEscapeR, basinMaxSteps)
+			fillCell(pix, w, h, col*basinCell, row*basinCell, basinColor(o))
+		}
+	})
+
+	g.writeBackground(pix)
+}
+
+// basinColor: цвет заряда-аттрактора, ярче при быстром захвате;
+// улетевшие частицы чёрные, незавершённые серые.
+func basinColor(o ensembleOutcome) color.RGBA {
+	switch {
+	case o.captured >= 0:
+		c := basinPalette[o.captured%len(basinPalette)]
+		k := 1 - 0.6*float64(o.steps)/basinMaxSteps
+		return color.RGBA{uint8(float64(c.R) * k), uint8(float64(c.G) * k), uint8(float64(c.B) * k), 255}
+	case o.escaped:
+		return color.RGBA{0, 0, 0, 255}
+	default:
+		return color.RGBA{90, 90, 90, 255}
+	}
+}
+
+func fillCell(pix []byte, w, h, x0, y0 int, c color.RGBA) {
+	for y := y0; y < min(y0+basinCell, h); y++ {
+		for x := x0; x < min(x0+basinCell, w); x++ {
+			i := 4 * (y*w + x)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+}
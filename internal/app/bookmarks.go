@@ -0,0 +1,90 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Закладки вида для лекций: Ctrl+Shift+1…9 запоминает положение и
+// масштаб камеры, Ctrl+1…9 плавно переводит к ним камеру. Закладки
+// сохраняются со сценой; имя можно поправить в файле сцены.
+
+const bookmarkFrames = 30 // длительность перелёта к закладке
+
+var bookmarkKeys = []ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9,
+}
+
+type Bookmark struct {
+	Slot int     `json:"slot"` // 1–9
+	Name string  `json:"name"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Zoom float64 `json:"zoom"`
+}
+
+type bookmarks struct {
+	items    []Bookmark
+	from, to camera
+	frame    int // кадр перелёта, 0 — камера стоит
+}
+
+func (g *Game) updateBookmarks() {
+	b := &g.bookmarks
+	if keyPressed(ebiten.KeyControl) {
+		for i, k := range bookmarkKeys {
+			if !keyJustPressed(k) {
+				continue
+			}
+			if keyPressed(ebiten.KeyShift) {
+				g.saveBookmark(i + 1)
+			} else {
+				g.gotoBookmark(i + 1)
+			}
+		}
+	}
+	if b.frame == 0 {
+		return
+	}
+	b.frame++
+	t := float64(b.frame) / bookmarkFrames
+	if t >= 1 {
+		t, b.frame = 1, 0
+	}
+	t = t * t * (3 - 2*t) // плавный старт и остановка
+	// масштаб интерполируется по логарифму, чтобы приближение шло равномерно
+	c := &g.cam
+	c.X = b.from.X + (b.to.X-b.from.X)*t
+	c.Y = b.from.Y + (b.to.Y-b.from.Y)*t
+	c.Zoom = math.Exp(math.Log(b.from.Zoom) + (math.Log(b.to.Zoom)-math.Log(b.from.Zoom))*t)
+}
+
+func (g *Game) saveBookmark(slot int) {
+	b := &g.bookmarks
+	bm := Bookmark{Slot: slot, Name: fmt.Sprintf(tr("View %d"), slot), X: g.cam.X, Y: g.cam.Y, Zoom: g.cam.Zoom}
+	if i := slices.IndexFunc(b.items, func(m Bookmark) bool { return m.Slot == slot }); i >= 0 {
+		bm.Name = b.items[i].Name
+		b.items[i] = bm
+	} else {
+		b.items = append(b.items, bm)
+	}
+	g.notify(fmt.Sprintf(tr("Saved view %d: %s"), slot, bm.Name))
+}
+
+func (g *Game) gotoBookmark(slot int) {
+	b := &g.bookmarks
+	i := slices.IndexFunc(b.items, func(m Bookmark) bool { return m.Slot == slot })
+	if i < 0 {
+		g.notify(fmt.Sprintf(tr("No view saved at %d (Ctrl+Shift+%d saves)"), slot, slot))
+		return
+	}
+	m := b.items[i]
+	b.from, b.to = g.cam, g.cam
+	b.to.X, b.to.Y, b.to.Zoom = m.X, m.Y, math.Max(camMinZoom, math.Min(camMaxZoom, m.Zoom))
+	b.frame = 1
+	g.notify(fmt.Sprintf(tr("View %d: %s"), slot, m.Name))
+}
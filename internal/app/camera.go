@@ -0,0 +1,151 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Камера: мировая точка (X, Y) в центре экрана и масштаб Zoom (пикселей
+// на единицу мира). Всё, что рисуется в мировых координатах, проходит
+// через toScreen, а экранные точки переводятся в мир через toWorld.
+// W и H — размер изображения в пикселях; при экспорте в высоком
+// разрешении они, как и Zoom, умножаются на Scale.
+
+const (
+	camMinZoom  = 0.1
+	camMaxZoom  = 10.0
+	camZoomStep = 1.1 // множитель масштаба за одно деление колеса
+	camPanSpeed = 8.0 // пикселей экрана за кадр для стрелок
+)
+
+type camera struct {
+	X, Y  float64
+	Zoom  float64
+	W, H  int
+	Scale float64 // пикселей изображения на пиксель интерфейса
+}
+
+func defaultCamera() camera {
+	return camera{Zoom: 1, W: screenWidth, H: screenHeight, Scale: 1}
+}
+
+func (c camera) toScreen(x, y float64) (float32, float32) {
+	return float32((x-c.X)*c.Zoom + float64(c.W)/2), float32((y-c.Y)*c.Zoom + float64(c.H)/2)
+}
+
+func (c camera) toWorld(sx, sy float64) (float64, float64) {
+	return (sx-float64(c.W)/2)/c.Zoom + c.X, (sy-float64(c.H)/2)/c.Zoom + c.Y
+}
+
+// inView сообщает, видна ли мировая точка с запасом margin пикселей.
+func (c camera) inView(x, y, margin float64) bool {
+	dx := math.Abs(x-c.X)*c.Zoom - margin*c.Scale
+	dy := math.Abs(y-c.Y)*c.Zoom - margin*c.Scale
+	return dx <= float64(c.W)/2 && dy <= float64(c.H)/2
+}
+
+// scaled — экспортная камера: тот же вид в factor раз большем разрешении.
+func (c camera) scaled(factor int) camera {
+	k := float64(factor)
+	c.Zoom *= k
+	c.W *= factor
+	c.H *= factor
+	c.Scale *= k
+	return c
+}
+
+// px переводит размер в пикселях интерфейса в пиксели изображения.
+func (c camera) px(v float32) float32 { return v * float32(c.Scale) }
+
+// zoomAt меняет масштаб, оставляя неподвижной экранную точку (sx, sy).
+func (c *camera) zoomAt(sx, sy, factor float64) {
+	wx, wy := c.toWorld(sx, sy)
+	c.Zoom = math.Max(camMinZoom, math.Min(camMaxZoom, c.Zoom*factor))
+	c.X = wx - (sx-float64(c.W)/2)/c.Zoom
+	c.Y = wy - (sy-float64(c.H)/2)/c.Zoom
+}
+
+// bgGeoM переводит картинку фона, посчитанную при камере from, в текущий
+// вид, чтобы во время панорамирования фон двигался без пересчёта.
+func (c camera) bgGeoM(from camera) ebiten.GeoM {
+	var m ebiten.GeoM
+	cx, cy := float64(c.W)/2, float64(c.H)/2
+	m.Translate(-cx, -cy)
+	m.Scale(c.Zoom/from.Zoom, c.Zoom/from.Zoom)
+	m.Translate((from.X-c.X)*c.Zoom+cx, (from.Y-c.Y)*c.Zoom+cy)
+	return m
+}
+
+// cursor — позиция мыши (или курсора геймпада) относительно области вида
+// этой сцены.
+func (g *Game) cursor() (int, int) {
+	if g.pad.active {
+		return int(g.pad.x), int(g.pad.y)
+	}
+	x, y := cursorPosition()
+	return x - g.viewX, y
+}
+
+func (g *Game) cursorWorld() (float64, float64) {
+	x, y := g.cursor()
+	return g.cam.toWorld(float64(x), float64(y))
+}
+
+// panning — зажат пробел: левая кнопка тащит вид, а не ставит заряд.
+func panning() bool {
+	return keyPressed(keyPan)
+}
+
+// updateCamera обрабатывает колесо, стрелки, перетаскивание с пробелом,
+// геймпад и сенсорные жесты.
+// Пока камера движется, фон и линии только перерисовываются; полный
+// пересчёт выполняется в первом кадре после остановки.
+func (g *Game) updateCamera() {
+	prev := g.cam
+	c := &g.cam
+
+	if _, wy := wheel(); wy != 0 && !g.selectionRotating() && !g.overSelection() {
+		x, y := g.cursor()
+		c.zoomAt(float64(x), float64(y), math.Pow(camZoomStep, wy))
+	}
+
+	step := camPanSpeed / c.Zoom
+	if keyPressed(ebiten.KeyArrowLeft) {
+		c.X -= step
+	}
+	if keyPressed(ebiten.KeyArrowRight) {
+		c.X += step
+	}
+	if keyPressed(ebiten.KeyArrowUp) {
+		c.Y -= step
+	}
+	if keyPressed(ebiten.KeyArrowDown) {
+		c.Y += step
+	}
+
+	x, y := g.cursor()
+	if panning() && mousePressed(ebiten.MouseButtonLeft) && g.dragging {
+		c.X -= float64(x-g.dragX) / c.Zoom
+		c.Y -= float64(y-g.dragY) / c.Zoom
+	}
+	g.dragging = panning() && mousePressed(ebiten.MouseButtonLeft)
+	g.dragX, g.dragY = x, y
+
+	if keyJustPressed(keyResetView) {
+		c.X, c.Y, c.Zoom = 0, 0, 1
+	}
+	g.updateGamepadCamera()
+	g.updateBookmarks()
+	g.updateMinimap()
+	g.updateTouch()
+	c.X, c.Y = clampToWorld(c.X, c.Y)
+
+	switch {
+	case *c != prev:
+		g.camMoving = true
+	case g.camMoving:
+		g.camMoving = false
+		g.dirty = true
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Очистка сцены: первое нажатие C только просит подтверждения, второе,
+// пока видна подсказка, убирает заряды, пробную частицу и плазму.
+// Снимок перед очисткой уходит в историю вместе с частицами, так что
+// Ctrl+Z возвращает всё.
+
+func (g *Game) updateClearAll() {
+	if g.clearArmed > 0 {
+		g.clearArmed--
+	}
+	if !keyJustPressed(keyClearAll) || keyPressed(ebiten.KeyControl) {
+		return
+	}
+	if g.clearArmed == 0 {
+		g.clearArmed = noticeFrames
+		g.notify(fmt.Sprintf(tr("Press %s again to clear all charges and particles"), keyLabel(keyClearAll)))
+		return
+	}
+	g.clearArmed = 0
+	g.clearAll()
+	g.notify(fmt.Sprintf(tr("Scene cleared, Ctrl+%s: undo"), keyLabel(keyUndo)))
+}
+
+func (g *Game) clearAll() {
+	s := g.snapshot()
+	s.particles = g.particleSnapshot()
+	g.pushUndo(s)
+
+	g.charges = nil
+	g.testParticle = Particle{}
+	p := &g.plasma
+	p.x, p.y, p.vx, p.vy, p.q = nil, nil, nil, nil, nil
+	g.trails = nil
+	g.menu.open = false
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+	g.conservation.resetTest()
+	g.strip.reset()
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Буфер обмена зарядов: Ctrl+C запоминает выделение относительно его
+// центра, Ctrl+V вставляет у курсора, Ctrl+D дублирует рядом с оригиналом.
+// Вставленные заряды становятся новым выделением, чтобы их сразу можно было
+// подвинуть или повернуть. Буфер живёт только внутри окна.
+
+// picked — выделенные заряды, а если выделения нет, заряд под курсором.
+func (g *Game) picked() []Charge {
+	var out []Charge
+	for _, c := range g.charges {
+		if c.Selected {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		if i := g.hovered(); i >= 0 {
+			out = append(out, g.charges[i])
+		}
+	}
+	return out
+}
+
+func (g *Game) copySelection() int {
+	picked := g.picked()
+	if len(picked) == 0 {
+		return 0
+	}
+	var cx, cy float64
+	for _, c := range picked {
+		cx, cy = cx+c.X, cy+c.Y
+	}
+	cx, cy = cx/float64(len(picked)), cy/float64(len(picked))
+	g.clipboard = g.clipboard[:0]
+	for _, c := range picked {
+		g.clipboard = append(g.clipboard, shiftedCharge(c, -cx, -cy))
+	}
+	return len(picked)
+}
+
+// place добавляет копии зарядов, сдвинутые на (dx, dy), новым выделением.
+func (g *Game) place(cs []Charge, dx, dy float64) {
+	if len(cs) == 0 {
+		return
+	}
+	g.checkpoint()
+	g.clearSelection()
+	for _, c := range cs {
+		g.charges = append(g.charges, shiftedCharge(c, dx, dy))
+	}
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+}
+
+// shiftedCharge — копия заряда, сдвинутая вместе с направляющей, без
+// скорости и выделенная.
+func shiftedCharge(c Charge, dx, dy float64) Charge {
+	c.X, c.Y = c.X+dx, c.Y+dy
+	c.VX, c.VY = 0, 0
+	c.Selected = true
+	if c.Track != nil {
+		t := *c.Track
+		t.X1, t.Y1 = t.X1+dx, t.Y1+dy
+		if t.Kind == TrackSegment {
+			t.X2, t.Y2 = t.X2+dx, t.Y2+dy
+		}
+		c.Track = &t
+	}
+	return c
+}
+
+func (g *Game) updateClipboard() {
+	if !keyPressed(ebiten.KeyControl) {
+		return
+	}
+	off := duplicateOffset / g.cam.Zoom
+	switch {
+	case keyJustPressed(keyCopy):
+		if n := g.copySelection(); n > 0 {
+			g.notify(fmt.Sprintf(tr("Copied %d charge(s)"), n))
+		}
+	case keyJustPressed(keyPaste):
+		x, y := g.snap.at(g.cursorWorld())
+		if g.snap.on {
+			off = 0 // центр вставки встаёт точно в узел
+		}
+		g.place(g.clipboard, x+off, y+off)
+	case keyJustPressed(keyDuplicate):
+		g.place(g.picked(), off, off) // буфер обмена не трогается
+	}
+}
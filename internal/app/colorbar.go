@@ -0,0 +1,138 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/colormap"
+)
+
+// Легенда теплокарты: полоса палитры у правого края с подписями |E|
+// (для фона потенциала — V, полоса симметрична относительно нуля).
+// Полоса линейна по t ∈ [0, 1], а подписи пересчитываются через обратную
+// передаточную функцию, так что легенда следует за шкалой и палитрой.
+
+const (
+	colorbarW      = 14 // при масштабе интерфейса 1, как и отступы
+	colorbarMargin = 46 // от нижнего края вида, над строкой состояния
+	colorbarRight  = 16 // от правого края вида
+)
+
+type colorbar struct {
+	img     *ebiten.Image
+	palette int
+	invert  bool
+	signed  bool
+}
+
+// inverse — |E|, которому передаточная функция ставит в соответствие t.
+func (h *heatScale) inverse(t float64) float64 {
+	switch h.transfer {
+	case TransferSqrt:
+		return t * t * h.max
+	case TransferLog:
+		return h.max * math.Pow(10, (t-1)*h.decades)
+	case TransferHistogram:
+		for i, c := range h.cdf {
+			if c >= t {
+				return math.Pow(10, h.lo+(float64(i)+0.5)/heatHistBins*(h.hi-h.lo))
+			}
+		}
+		return math.Pow(10, h.hi)
+	default:
+		return t * h.max
+	}
+}
+
+// colorbarTicks возвращает положения подписей по t и их значения |E|.
+func (h *heatScale) colorbarTicks() (ts, values []float64) {
+	if h.transfer == TransferLog {
+		top := math.Log10(h.max)
+		for k := math.Floor(top); k >= top-h.decades; k-- {
+			ts = append(ts, 1+(k-top)/h.decades)
+			values = append(values, math.Pow(10, k))
+		}
+		return ts, values
+	}
+	for i := 0; i <= 4; i++ {
+		t := float64(i) / 4
+		ts = append(ts, t)
+		values = append(values, h.inverse(t))
+	}
+	return ts, values
+}
+
+func (g *Game) drawColorbar(screen *ebiten.Image) {
+	if g.bgMode == BackgroundBasins || g.bgImage == nil && !g.gpuHeatActive() {
+		return
+	}
+	signed := g.bgMode == BackgroundPotential
+	scale, title, conv := &g.heat, "|E|"+g.units.fieldUnit(), g.units.field
+	if signed {
+		scale, title, conv = &g.potScale, "V"+g.units.potentialUnit(), g.units.potential
+	}
+	if scale.transfer == TransferHistogram && scale.cdf == nil {
+		return
+	}
+
+	th := g.theme()
+	top, bottom := g.hud.colorbarTop, g.cam.H-ui(colorbarMargin)
+	h := max(bottom-top, 2)
+	barW := uif(colorbarW)
+	colorbarX := float32(g.cam.W-ui(colorbarRight)) - barW
+	cb := &g.colorbar
+	if cb.img == nil || cb.img.Bounds().Dy() != h || cb.palette != g.colormap || cb.invert != th.InvertHeat || cb.signed != signed {
+		cb.img = ebiten.NewImage(1, h)
+		cb.palette, cb.invert, cb.signed = g.colormap, th.InvertHeat, signed
+		pix := make([]byte, 4*h)
+		for y := 0; y < h; y++ {
+			t := 1 - float64(y)/float64(h-1) // сильное поле сверху
+			var c color.RGBA
+			switch {
+			case signed:
+				c = colormap.Coolwarm.Signed(2*t - 1)
+			case th.InvertHeat:
+				c = g.palette().At(1 - t)
+			default:
+				c = g.palette().At(t)
+			}
+			pix[4*y], pix[4*y+1], pix[4*y+2], pix[4*y+3] = c.R, c.G, c.B, 255
+		}
+		cb.img.WritePixels(pix)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(barW), 1)
+	op.GeoM.Translate(float64(colorbarX), float64(top))
+	screen.DrawImage(cb.img, op)
+	vector.StrokeRect(screen, colorbarX, float32(top), barW, float32(h), 1, th.Border, false)
+
+	face := uiFace
+	text.Draw(screen, title, face, min(int(colorbarX)-ui(7), g.cam.W-textWidth(title)-ui(4)), top-ui(8), th.HUD)
+	ts, values := scale.colorbarTicks()
+	if signed {
+		// половины полосы зеркальны: V > 0 сверху, V < 0 снизу
+		n := len(ts)
+		for i := 0; i < n; i++ {
+			if ts[i] == 0 {
+				continue
+			}
+			ts = append(ts, -ts[i])
+			values = append(values, -values[i])
+		}
+		for i := range ts {
+			ts[i] = (1 + ts[i]) / 2
+		}
+	}
+	for i, t := range ts {
+		y := float32(bottom) - float32(t)*float32(h-1)
+		vector.StrokeLine(screen, colorbarX-uif(4), y, colorbarX, y, 1, th.HUD, false)
+		s := fmt.Sprintf("%.3g", conv(values[i]))
+		text.Draw(screen, s, face, int(colorbarX)-ui(6)-textWidth(s), baseline(int(y)-lineHeight()/2, lineHeight()), th.HUD)
+	}
+}
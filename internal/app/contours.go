@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/contour"
+)
+
+// Эквипотенциали поверх фона: потенциал считается на сетке один раз
+// за пересчёт сцены, изолинии извлекаются marching squares.
+
+const (
+	contourCell       = 6.0   // шаг сетки потенциала, пикс
+	contourStep       = 10.0  // интервал между изолиниями
+	contourMaxV       = 300.0 // у самих зарядов линии сливаются, дальше не строим
+	contourLabelEvery = 5     // подписывается каждая N-я изолиния
+	contourLabelGap   = 120.0 // минимальное расстояние между подписями
+)
+
+type contourLevel struct {
+	V    float64
+	Segs []contour.Segment
+}
+
+type contourLabel struct {
+	X, Y float64
+	Text string
+}
+
+func (g *Game) recomputeContours() {
+	g.contourLevels = g.contourLevels[:0]
+	g.contourLabels = g.contourLabels[:0]
+
+	if g.contourGrid == nil {
+		w := int(screenWidth/contourCell) + 1
+		h := int(screenHeight/contourCell) + 1
+		g.contourGrid = contour.New(w, h, 0, 0, 0)
+	}
+	// сетка покрывает текущий вид с шагом contourCell пикселей экрана
+	grid := g.contourGrid
+	grid.X0, grid.Y0 = g.cam.toWorld(0, 0)
+	grid.Step = contourCell * g.cam.Scale / g.cam.Zoom
+	grid.Fill(g.slicePotential)
+
+	lo, hi := grid.Range()
+	lo, hi = math.Max(lo, -contourMaxV), math.Min(hi, contourMaxV)
+	for k := int(math.Ceil(lo / contourStep)); k <= int(math.Floor(hi/contourStep)); k++ {
+		V := float64(k) * contourStep
+		segs := grid.Segments(V)
+		if len(segs) == 0 {
+			continue
+		}
+		g.contourLevels = append(g.contourLevels, contourLevel{V: V, Segs: segs})
+		if k%contourLabelEvery == 0 {
+			g.placeContourLabels(V, segs)
+		}
+	}
+}
+
+// placeContourLabels ставит подписи уровня, не ближе contourLabelGap к уже поставленным.
+func (g *Game) placeContourLabels(V float64, segs []contour.Segment) {
+	for _, s := range segs {
+		x, y := (s.A.X+s.B.X)/2, (s.A.Y+s.B.Y)/2
+		if !g.cam.inView(x, y, -30) {
+			continue
+		}
+		free := true
+		for _, l := range g.contourLabels {
+			if math.Hypot(l.X-x, l.Y-y)*g.cam.Zoom < contourLabelGap*g.cam.Scale {
+				free = false
+				break
+			}
+		}
+		if free {
+			g.contourLabels = append(g.contourLabels, contourLabel{X: x, Y: y, Text: fmt.Sprintf("%.0f", V)})
+		}
+	}
+}
+
+func (g *Game) drawContours(screen *ebiten.Image) {
+	if !g.contours {
+		return
+	}
+	for _, l := range g.contourLevels {
+		col := withAlpha(g.theme().FieldLines, 110)
+		if l.V == 0 {
+			col = color.RGBA{255, 255, 120, 200}
+		}
+		for _, s := range l.Segs {
+			x1, y1 := g.cam.toScreen(s.A.X, s.A.Y)
+			x2, y2 := g.cam.toScreen(s.B.X, s.B.Y)
+			vector.StrokeLine(screen, x1, y1, x2, y2, g.cam.px(1), col, true)
+		}
+	}
+
+	face, lh := uiFace, lineHeight()
+	for _, l := range g.contourLabels {
+		w := textWidth(l.Text)
+		x, y := g.cam.toScreen(l.X, l.Y)
+		tx, ty := int(x)-w/2, int(y)-lh/2
+		vector.DrawFilledRect(screen, float32(tx-ui(2)), float32(ty), float32(w+ui(4)), float32(lh), g.theme().Panel, false)
+		text.Draw(screen, l.Text, face, tx, baseline(ty, lh), g.theme().HUD)
+	}
+}
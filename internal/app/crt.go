@@ -0,0 +1,258 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// ЭЛТ: электронная пушка, ускоряющий промежуток, две пары отклоняющих
+// пластин и люминесцентный экран с послесвечением. Расчёт в СИ.
+
+const (
+	electronQM = 1.758820e11 // |e|/m электрона, Кл/кг
+
+	crtGunLen    = 0.01  // длина ускоряющего промежутка, м
+	crtPlateLen  = 0.02  // длина отклоняющих пластин, м
+	crtPlateGap  = 0.008 // зазор между пластинами, м
+	crtYPlatesZ  = 0.03  // начало пластин Y
+	crtXPlatesZ  = 0.06  // начало пластин X
+	crtScreenZ   = 0.20  // положение экрана
+	crtScreenR   = 0.04  // полуразмер экрана, м
+	crtFlightN   = 400   // шагов интегрирования на один пролёт
+	crtPerFrame  = 40    // электронов за кадр
+	crtPersist   = 0.35  // время послесвечения люминофора, с
+	crtFrameTime = 1.0 / 60
+)
+
+type crtHit struct {
+	X, Y       float64
+	Brightness float64
+}
+
+type crtScenario struct {
+	accelV     float64 // ускоряющее напряжение, В
+	ampX, ampY float64 // амплитуды напряжений на пластинах, В
+	freqX      float64 // частоты модуляции, Гц
+	freqY      float64
+	phase      float64 // сдвиг фаз X относительно Y, рад
+
+	t    float64
+	hits []crtHit
+
+	lastPath []Vec2 // z, y последнего электрона для вида сбоку
+}
+
+func newCRTScenario() *crtScenario {
+	return &crtScenario{
+		accelV: 1000,
+		ampX:   60,
+		ampY:   60,
+		freqX:  1,
+		freqY:  2,
+		phase:  math.Pi / 2,
+	}
+}
+
+func (s *crtScenario) Name() string { return tr("CRT: electron gun and phosphor screen") }
+
+func (s *crtScenario) plateVoltages(t float64) (float64, float64) {
+	vx := s.ampX * math.Sin(2*math.Pi*s.freqX*t+s.phase)
+	vy := s.ampY * math.Sin(2*math.Pi*s.freqY*t)
+	return vx, vy
+}
+
+// fly интегрирует движение электрона от катода до экрана.
+// Время пролёта ~1e-8 с, поэтому напряжения за пролёт постоянны.
+func (s *crtScenario) fly(vx, vy float64, keepPath bool) (float64, float64, bool) {
+	// ускоряющий промежуток: однородное поле вдоль z
+	az := electronQM * s.accelV / crtGunLen
+	vzExit := math.Sqrt(2 * az * crtGunLen)
+	tGap := vzExit / az
+
+	var x, y, z float64
+	var ux, uy, uz float64
+
+	if keepPath {
+		s.lastPath = s.lastPath[:0]
+	}
+
+	totalT := tGap + (crtScreenZ-crtGunLen)/vzExit*1.2
+	dt := totalT / crtFlightN
+
+	// поле пластин направлено от «+» к «−»; электрон тянется к «+»
+	ey := vy / crtPlateGap
+	ex := vx / crtPlateGap
+
+	for i := 0; i < crtFlightN*2 && z < crtScreenZ; i++ {
+		var ax, ay, azNow float64
+		switch {
+		case z < crtGunLen:
+			azNow = az
+		case z >= crtYPlatesZ && z < crtYPlatesZ+crtPlateLen:
+			ay = electronQM * ey
+		case z >= crtXPlatesZ && z < crtXPlatesZ+crtPlateLen:
+			ax = electronQM * ex
+		}
+
+		ux += ax * dt
+		uy += ay * dt
+		uz += azNow * dt
+		x += ux * dt
+		y += uy * dt
+		z += uz * dt
+
+		if z >= crtYPlatesZ && z < crtYPlatesZ+crtPlateLen && math.Abs(y) > crtPlateGap/2 {
+			return 0, 0, false
+		}
+		if z >= crtXPlatesZ && z < crtXPlatesZ+crtPlateLen && math.Abs(x) > crtPlateGap/2 {
+			return 0, 0, false
+		}
+
+		if keepPath {
+			s.lastPath = append(s.lastPath, Vec2{X: z, Y: y})
+		}
+	}
+
+	if math.Abs(x) > crtScreenR || math.Abs(y) > crtScreenR {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func (s *crtScenario) Update() {
+	shift := keyPressed(ebiten.KeyShift)
+	step := 5.0
+	if shift {
+		step = 1
+	}
+
+	if keyPressed(ebiten.KeyUp) {
+		s.ampY = math.Min(s.ampY+step, 400)
+	}
+	if keyPressed(ebiten.KeyDown) {
+		s.ampY = math.Max(s.ampY-step, 0)
+	}
+	if keyPressed(ebiten.KeyRight) {
+		s.ampX = math.Min(s.ampX+step, 400)
+	}
+	if keyPressed(ebiten.KeyLeft) {
+		s.ampX = math.Max(s.ampX-step, 0)
+	}
+	if keyJustPressed(ebiten.KeyQ) {
+		s.freqY++
+	}
+	if keyJustPressed(ebiten.KeyA) && s.freqY > 1 {
+		s.freqY--
+	}
+	if keyJustPressed(ebiten.KeyW) {
+		s.freqX++
+	}
+	if keyJustPressed(ebiten.KeyS) && s.freqX > 1 {
+		s.freqX--
+	}
+	if keyJustPressed(ebiten.KeyE) {
+		s.phase = math.Mod(s.phase+math.Pi/8, 2*math.Pi)
+	}
+	if keyJustPressed(ebiten.KeyEqual) {
+		s.accelV = math.Min(s.accelV+100, 5000)
+	}
+	if keyJustPressed(ebiten.KeyMinus) {
+		s.accelV = math.Max(s.accelV-100, 200)
+	}
+
+	decay := math.Exp(-crtFrameTime / crtPersist)
+	alive := s.hits[:0]
+	for _, h := range s.hits {
+		h.Brightness *= decay
+		if h.Brightness > 0.02 {
+			alive = append(alive, h)
+		}
+	}
+	s.hits = alive
+
+	for i := 0; i < crtPerFrame; i++ {
+		te := s.t + crtFrameTime*float64(i)/crtPerFrame
+		vx, vy := s.plateVoltages(te)
+		x, y, ok := s.fly(vx, vy, i == crtPerFrame-1)
+		if ok {
+			age := crtFrameTime * float64(crtPerFrame-1-i) / crtPerFrame
+			s.hits = append(s.hits, crtHit{X: x, Y: y, Brightness: math.Exp(-age / crtPersist)})
+		}
+	}
+	s.t += crtFrameTime
+}
+
+func (s *crtScenario) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 14, 255})
+
+	// вид сбоку: z по горизонтали, y по вертикали
+	const (
+		sideX0 = 30.0
+		sideY0 = 300.0
+		sideS  = 2400.0 // пикселей на метр
+	)
+	toSide := func(z, y float64) (float32, float32) {
+		return float32(sideX0 + z*sideS), float32(sideY0 - y*sideS*2)
+	}
+
+	frame := color.RGBA{120, 120, 140, 255}
+	cx0, _ := toSide(0, 0)
+	cx1, _ := toSide(crtGunLen, 0)
+	vector.StrokeLine(screen, cx0, sideY0-30, cx0, sideY0+30, 2, color.RGBA{255, 120, 60, 255}, false)
+	vector.StrokeLine(screen, cx1, sideY0-30, cx1, sideY0-4, 2, frame, false)
+	vector.StrokeLine(screen, cx1, sideY0+4, cx1, sideY0+30, 2, frame, false)
+
+	vx, vy := s.plateVoltages(s.t)
+	py0, pyTop := toSide(crtYPlatesZ, crtPlateGap/2)
+	py1, pyBot := toSide(crtYPlatesZ+crtPlateLen, -crtPlateGap/2)
+	vector.StrokeLine(screen, py0, pyTop, py1, pyTop, 3, plateColor(vy), false)
+	vector.StrokeLine(screen, py0, pyBot, py1, pyBot, 3, plateColor(-vy), false)
+
+	px0, pxTop := toSide(crtXPlatesZ, crtPlateGap/2)
+	px1, pxBot := toSide(crtXPlatesZ+crtPlateLen, -crtPlateGap/2)
+	vector.StrokeRect(screen, px0, pxTop, px1-px0, pxBot-pxTop, 1, frame, false)
+
+	sx, sTop := toSide(crtScreenZ, crtScreenR)
+	_, sBot := toSide(crtScreenZ, -crtScreenR)
+	vector.StrokeLine(screen, sx, sTop, sx, sBot, 3, color.RGBA{60, 200, 90, 255}, false)
+
+	for i := 0; i+1 < len(s.lastPath); i += 4 {
+		j := min(i+4, len(s.lastPath)-1)
+		x1, y1 := toSide(s.lastPath[i].X, s.lastPath[i].Y)
+		x2, y2 := toSide(s.lastPath[j].X, s.lastPath[j].Y)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{120, 180, 255, 200}, false)
+	}
+
+	// люминесцентный экран
+	const (
+		scrCX = 720.0
+		scrCY = 300.0
+		scrR  = 150.0
+	)
+	vector.DrawFilledRect(screen, scrCX-scrR, scrCY-scrR, 2*scrR, 2*scrR, color.RGBA{5, 20, 8, 255}, false)
+	vector.StrokeRect(screen, scrCX-scrR, scrCY-scrR, 2*scrR, 2*scrR, 1, frame, false)
+	for _, h := range s.hits {
+		hx := float32(scrCX + h.X/crtScreenR*scrR)
+		hy := float32(scrCY - h.Y/crtScreenR*scrR)
+		a := uint8(math.Min(1, h.Brightness) * 255)
+		vector.DrawFilledCircle(screen, hx, hy, 2, color.RGBA{60, 255, 90, a}, false)
+	}
+
+	face := uiFace
+	text.Draw(screen, s.Name(), face, ui(10), ui(20), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("Ua = %.0f V (+/-), Ux = %.0f sin(2pi %.0f t + %.2f) V (Left/Right, W/S, E), Uy = %.0f sin(2pi %.0f t) V (Up/Down, Q/A)"),
+		s.accelV, s.ampX, s.freqX, s.phase, s.ampY, s.freqY), face, ui(10), ui(40), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("Now: Ux = %+.1f V, Uy = %+.1f V    F2: next scene, Esc: back to editor"), vx, vy), face, ui(10), ui(60), color.White)
+}
+
+func plateColor(v float64) color.RGBA {
+	if v >= 0 {
+		return color.RGBA{255, 80, 80, 255}
+	}
+	return color.RGBA{80, 80, 255, 255}
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"image"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Значок инструмента рядом с курсором: показывает, что сделает щелчок
+// левой кнопкой при текущих модификаторах и том, что под курсором.
+// clickTool повторяет разбор нажатия в handleInput — при правке одного
+// надо поправить и другое.
+
+const (
+	cursorBadgeOffset = 14 // от острия системного курсора до центра значка, пикс
+	cursorBadgeR      = 6
+)
+
+type cursorTool int
+
+const (
+	toolNone cursorTool = iota
+	toolAddPositive
+	toolAddNegative
+	toolProbe  // Ctrl: закрепить заряд или щуп
+	toolDelete // Ctrl+Shift: удалить заряд
+	toolSeed
+	toolRuler
+	toolAngle
+	toolNote
+	toolDrag
+	toolSelect
+)
+
+func (g *Game) clickTool() cursorTool {
+	if g.menu.open || g.help || g.scenario != nil || g.cut.active || g.surfaceView || g.view3D.active {
+		return toolNone
+	}
+	if x, y := g.cursor(); g.settings.open && image.Pt(x, y).In(g.settings.rect()) {
+		return toolNone
+	}
+	s := &g.selection
+	ctrl, shift := keyPressed(ebiten.KeyControl), keyPressed(ebiten.KeyShift)
+	switch {
+	case s.band:
+		return toolSelect
+	case s.dragging || panning() || g.minimap.drag:
+		return toolDrag
+	case keyPressed(ebiten.KeyAlt):
+		return toolSelect
+	case g.seeding.tool:
+		return toolSeed
+	case g.ruler.tool:
+		return toolRuler
+	case g.protractor.tool:
+		return toolAngle
+	case g.notes.tool:
+		return toolNote
+	case ctrl && shift:
+		if g.hovered() < 0 {
+			return toolNone
+		}
+		return toolDelete
+	case ctrl:
+		return toolProbe
+	case shift:
+		return toolAddNegative
+	}
+	if g.hovered() >= 0 {
+		return toolDrag
+	}
+	return toolAddPositive
+}
+
+// deleteChargeAtMouse удаляет заряд под курсором.
+func (g *Game) deleteChargeAtMouse() {
+	i := g.hovered()
+	if i < 0 {
+		return
+	}
+	g.checkpoint()
+	g.charges = slices.Delete(g.charges, i, i+1)
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+}
+
+func (g *Game) drawCursorTool(screen *ebiten.Image) {
+	tool := g.clickTool()
+	if tool == toolNone || g.hideHUD {
+		return
+	}
+	th := g.theme()
+	cx, cy := g.cursor()
+	x, y := float32(cx+ui(cursorBadgeOffset)), float32(cy+ui(cursorBadgeOffset))
+	r := uif(cursorBadgeR)
+	line := func(x0, y0, x1, y1 float32, col color.RGBA) {
+		vector.StrokeLine(screen, x+x0, y+y0, x+x1, y+y1, 2, col, true)
+	}
+
+	vector.DrawFilledCircle(screen, x, y, r+3, th.Panel, true)
+	switch tool {
+	case toolAddPositive:
+		line(-r, 0, r, 0, th.Positive)
+		line(0, -r, 0, r, th.Positive)
+	case toolAddNegative:
+		line(-r, 0, r, 0, th.Negative)
+	case toolProbe:
+		vector.StrokeCircle(screen, x, y, r-2, 1.5, th.HUD, true)
+		line(-r-2, 0, -r+3, 0, th.HUD)
+		line(r-3, 0, r+2, 0, th.HUD)
+		line(0, -r-2, 0, -r+3, th.HUD)
+		line(0, r-3, 0, r+2, th.HUD)
+	case toolDelete:
+		line(-r+1, -r+1, r-1, r-1, th.HUD)
+		line(-r+1, r-1, r-1, -r+1, th.HUD)
+	case toolSeed:
+		vector.DrawFilledCircle(screen, x-r/2, y+r/2, 2.5, th.FieldLines, true)
+		line(-r/2, r/2, r, -r, th.FieldLines)
+	case toolRuler:
+		line(-r, r/2, r, -r/2, th.HUD)
+		line(-r-1, r/2-3, -r+1, r/2+3, th.HUD)
+		line(r-1, -r/2-3, r+1, -r/2+3, th.HUD)
+	case toolAngle:
+		line(-r, r/2, r, r/2, th.HUD)
+		line(-r, r/2, r/2, -r, th.HUD)
+		vector.StrokeCircle(screen, x-r, y+r/2, r, 1, withAlpha(th.HUD, 140), true)
+	case toolNote:
+		lh := lineHeight()
+		text.Draw(screen, "T", uiFace, int(x)-textWidth("T")/2, baseline(int(y)-lh/2, lh), th.HUD)
+	case toolDrag:
+		line(-r, 0, r, 0, th.HUD)
+		line(0, -r, 0, r, th.HUD)
+		for _, d := range [][2]float32{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			tx, ty := d[0]*r, d[1]*r
+			// наконечник: две чёрточки назад от конца луча
+			line(tx, ty, tx-d[0]*3+d[1]*3, ty-d[1]*3+d[0]*3, th.HUD)
+			line(tx, ty, tx-d[0]*3-d[1]*3, ty-d[1]*3-d[0]*3, th.HUD)
+		}
+	case toolSelect:
+		// уголки рамки
+		for _, c := range [][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			px, py := c[0]*r, c[1]*r
+			line(px, py, px-c[0]*r*0.7, py, th.HUD)
+			line(px, py, px, py-c[1]*r*0.7, th.HUD)
+		}
+	}
+}
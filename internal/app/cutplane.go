@@ -0,0 +1,161 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Секущая плоскость: 2D-конвейер отрисовки (фон, линии, стрелки) получает
+// поле через sliceField и заряды через sliceCharges, поэтому любой срез
+// трёхмерного поля рисуется тем же кодом. Плоскость проходит через ось x,
+// наклонена на tilt вокруг неё и сдвинута на offset вдоль нормали.
+// Выключенная плоскость совпадает с z = 0.
+
+const (
+	cutTiltStep   = math.Pi / 36
+	cutOffsetStep = 5.0
+	cutFadeDist   = 60.0 // на таком расстоянии от плоскости заряд почти прозрачен
+)
+
+type cutPlane struct {
+	active bool
+	tilt   float64
+	offset float64
+}
+
+// basis возвращает начало и орты плоскости u, v и нормаль n.
+func (p cutPlane) basis() (o, u, v, n [3]float64) {
+	s, c := math.Sincos(p.tilt)
+	u = [3]float64{1, 0, 0}
+	v = [3]float64{0, c, s}
+	n = [3]float64{0, -s, c}
+	o = [3]float64{0, p.offset * n[1], p.offset * n[2]}
+	return
+}
+
+func (p cutPlane) toWorld(a, b float64) (float64, float64, float64) {
+	o, u, v, _ := p.basis()
+	return o[0] + a*u[0] + b*v[0], o[1] + a*u[1] + b*v[1], o[2] + a*u[2] + b*v[2]
+}
+
+// project возвращает координаты точки в плоскости и расстояние до неё.
+func (p cutPlane) project(x, y, z float64) (float64, float64, float64) {
+	o, u, v, n := p.basis()
+	d := [3]float64{x - o[0], y - o[1], z - o[2]}
+	dot := func(a [3]float64) float64 { return d[0]*a[0] + d[1]*a[1] + d[2]*a[2] }
+	return dot(u), dot(v), dot(n)
+}
+
+func (p cutPlane) chargeAlpha(c Charge) uint8 {
+	if !p.active {
+		return 255
+	}
+	_, _, d := p.project(c.X, c.Y, 0)
+	return uint8(255 * math.Max(0.15, 1-math.Abs(d)/cutFadeDist))
+}
+
+func (g *Game) sliceField(a, b float64) (float64, float64) {
+	if !g.cut.active {
+		return g.fieldAt(a, b)
+	}
+	x, y, z := g.cut.toWorld(a, b)
+	Ex, Ey, Ez := g.fieldAt3(x, y, z)
+	_, u, v, _ := g.cut.basis()
+	return Ex*u[0] + Ey*u[1] + Ez*u[2], Ex*v[0] + Ey*v[1] + Ez*v[2]
+}
+
+func (g *Game) slicePotential(a, b float64) float64 {
+	if !g.cut.active {
+		return g.potentialAt(a, b)
+	}
+	x, y, z := g.cut.toWorld(a, b)
+	var V float64
+	for _, c := range g.charges {
+		dx, dy := x-c.X, y-c.Y
+		V += pointPotential(c.Q, dx*dx+dy*dy+z*z)
+	}
+	return V
+}
+
+func (g *Game) sliceCharges() []Charge {
+	if !g.cut.active {
+		return g.charges
+	}
+	out := make([]Charge, len(g.charges))
+	for i, c := range g.charges {
+		c.X, c.Y, _ = g.cut.project(c.X, c.Y, 0)
+		out[i] = c
+	}
+	return out
+}
+
+func (g *Game) updateCutPlane() {
+	p := &g.cut
+	changed := false
+
+	if keyJustPressed(keyCutPlane) {
+		p.active = !p.active
+		changed = true
+	}
+	if p.active {
+		switch {
+		case keyJustPressed(keyCutTiltUp):
+			p.tilt = math.Min(p.tilt+cutTiltStep, math.Pi/2)
+		case keyJustPressed(keyCutTiltDown):
+			p.tilt = math.Max(p.tilt-cutTiltStep, -math.Pi/2)
+		case keyPressed(keyCutForward):
+			p.offset += cutOffsetStep
+		case keyPressed(keyCutBack):
+			p.offset -= cutOffsetStep
+		default:
+			if !changed {
+				return
+			}
+		}
+		changed = true
+	}
+
+	if changed {
+		g.dirty = true
+	}
+}
+
+func (g *Game) cutPlaneHint() string {
+	if !g.cut.active {
+		return keyLabel(keyCutPlane) + tr(": 3D cut plane")
+	}
+	return fmt.Sprintf(tr("%s cut plane: tilt %.0f deg (%s/%s), offset %.0f (%s/%s), editing disabled"),
+		keyLabel(keyCutPlane), g.cut.tilt*180/math.Pi, keyLabel(keyCutTiltUp), keyLabel(keyCutTiltDown),
+		g.cut.offset, keyLabel(keyCutForward), keyLabel(keyCutBack))
+}
+
+// drawCutPlaneInset показывает положение плоскости в разрезе y–z.
+func (g *Game) drawCutPlaneInset(screen *ebiten.Image) {
+	if !g.cut.active {
+		return
+	}
+	face := uiFace
+
+	const (
+		inset = 70 // центр вставки от правого верхнего угла
+		r     = 50
+		scale = 0.1 // мировых единиц на пиксель вставки
+	)
+	rr, m, k := uif(r), uif(10), uif(1)
+	cx, cy := float32(g.cam.W-ui(inset)), float32(ui(inset))
+	vector.DrawFilledRect(screen, cx-rr-m, cy-rr-m, 2*(rr+m), 2*(rr+m), color.RGBA{20, 20, 30, 220}, false)
+	vector.StrokeLine(screen, cx-rr, cy, cx+rr, cy, 1, color.RGBA{150, 150, 160, 255}, false)
+
+	_, _, v, n := g.cut.basis()
+	ox := k * float32(g.cut.offset*n[1]*scale)
+	oy := k * float32(-g.cut.offset*n[2]*scale)
+	dx, dy := rr*float32(v[1]), -rr*float32(v[2])
+	vector.StrokeLine(screen, cx+ox-dx, cy+oy-dy, cx+ox+dx, cy+oy+dy, uif(2), color.RGBA{255, 200, 60, 255}, false)
+	text.Draw(screen, "y", face, int(cx+rr)-ui(6), int(cy)+ui(14), g.theme().HUD)
+	text.Draw(screen, "z", face, int(cx)-ui(4), int(cy-rr)+ui(4), g.theme().HUD)
+}
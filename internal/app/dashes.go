@@ -0,0 +1,61 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Бегущие штрихи на силовых линиях. Вдоль линии хранится «время пролёта»
+// τ = ∫ ds / v(|E|), узор штрихов задан по τ и сдвигается на фазу, так что
+// штрихи движутся по полю со скоростью v, большей там, где поле сильнее.
+
+const (
+	dashPeriod   = 16.0 // период узора в кадрах
+	dashDuty     = 0.5  // доля периода, занятая штрихом
+	dashMinSpeed = 0.3  // пикс/кадр
+	dashMaxSpeed = 4.0
+	dashRefE     = 2.0 // |E|, при котором скорость равна 1 пикс/кадр
+)
+
+func dashSpeed(E float64) float64 {
+	return math.Max(dashMinSpeed, math.Min(dashMaxSpeed, math.Sqrt(E/dashRefE)))
+}
+
+// recomputeDashes строит τ для каждой точки каждой силовой линии.
+func (g *Game) recomputeDashes() {
+	g.fieldLineTau = make([][]float64, len(g.fieldLines))
+	for k, line := range g.fieldLines {
+		tau := make([]float64, len(line))
+		for i := 1; i < len(line); i++ {
+			a, b := line[i-1], line[i]
+			Ex, Ey := g.sliceField((a.X+b.X)/2, (a.Y+b.Y)/2)
+			tau[i] = tau[i-1] + math.Hypot(b.X-a.X, b.Y-a.Y)/dashSpeed(math.Hypot(Ex, Ey))
+		}
+		g.fieldLineTau[k] = tau
+	}
+}
+
+func (g *Game) drawFieldLines(screen *ebiten.Image) {
+	col := g.theme().FieldLines
+	dashed := g.dashes && len(g.fieldLineTau) == len(g.fieldLines)
+
+	var s splinePath
+	for k, line := range g.fieldLines {
+		for i := 0; i < len(line)-1; i++ {
+			if dashed {
+				t := (g.fieldLineTau[k][i]+g.fieldLineTau[k][i+1])/2 - g.dashPhase
+				if t/dashPeriod-math.Floor(t/dashPeriod) > dashDuty {
+					s.end()
+					continue
+				}
+			}
+			if len(s.run) == 0 {
+				s.add(g.cam.toScreen(line[i].X, line[i].Y))
+			}
+			s.add(g.cam.toScreen(line[i+1].X, line[i+1].Y))
+		}
+		s.end()
+	}
+	s.stroke(screen, g.cam.px(g.lines.FieldWidth()), col, g.lines.Antialias)
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"electric-field/internal/numdiff"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Диагностика поля: численные ∇·E и ∇×E на грубой сетке. Для поля
+// точечных зарядов ротор должен быть нулевым везде, а дивергенция —
+// только внутри зарядов. Поле в плоскости — срез трёхмерного, поэтому в
+// дивергенцию входит ∂Ez/∂z, иначе она не равна нулю даже в пустоте.
+// Все производные берутся из одного источника: у решателя Пуассона поле
+// двумерное и ∂Ez/∂z не нужен, иначе все три компоненты даёт прямая
+// сумма fieldAt3 (у FMM нет Ez).
+
+const (
+	diagCellSize = 20   // сторона ячейки в пикселях
+	diagStep     = 1.0  // шаг численного дифференцирования
+	diagTol      = 1e-4 // допуск на относительную невязку
+)
+
+type diagCell struct {
+	X, Y       float64 // центр ячейки в мировых координатах
+	Div, Curl  float64 // относительные невязки |∇·E|·h/|E|, |∇×E|·h/|E|
+	HasCharge  bool
+	Suspicious bool
+}
+
+func (g *Game) recomputeDiagnostics() {
+	g.diagCells = g.diagCells[:0]
+	g.diagMaxCurl = 0
+	g.diagMaxSpurious = 0
+
+	_, planar := g.solver.(*poissonSolver)
+	field := func(x, y, z float64) (float64, float64, float64) {
+		if planar {
+			ex, ey := g.solver.Field(x, y)
+			return ex, ey, 0
+		}
+		return g.fieldAt3(x, y, z)
+	}
+	ex := func(x, y float64) float64 { e, _, _ := field(x, y, 0); return e }
+	ey := func(x, y float64) float64 { _, e, _ := field(x, y, 0); return e }
+
+	for py := diagCellSize / 2; py < screenHeight; py += diagCellSize {
+		for px := diagCellSize / 2; px < screenWidth; px += diagCellSize {
+			x, y := g.cam.toWorld(float64(px), float64(py))
+
+			E := math.Hypot(ex(x, y), ey(x, y))
+			if E < 1e-9 {
+				continue
+			}
+			norm := diagCellSize / E
+
+			div := numdiff.Divergence(ex, ey, x, y, diagStep)
+			if !planar {
+				div += numdiff.Richardson(func(z float64) float64 {
+					_, _, e := field(x, y, z)
+					return e
+				}, 0, diagStep, numdiff.DefaultLevels)
+			}
+			curl := numdiff.Curl(ex, ey, x, y, diagStep)
+
+			cell := diagCell{X: x, Y: y, Div: math.Abs(div) * norm, Curl: math.Abs(curl) * norm}
+			for _, c := range g.charges {
+				if math.Abs(c.X-x) <= diagCellSize/2+math.Sqrt(minR2) && math.Abs(c.Y-y) <= diagCellSize/2+math.Sqrt(minR2) {
+					cell.HasCharge = true
+					break
+				}
+			}
+
+			g.diagMaxCurl = math.Max(g.diagMaxCurl, cell.Curl)
+			if !cell.HasCharge {
+				g.diagMaxSpurious = math.Max(g.diagMaxSpurious, cell.Div)
+			}
+			cell.Suspicious = cell.Curl > diagTol || (!cell.HasCharge && cell.Div > diagTol)
+			g.diagCells = append(g.diagCells, cell)
+		}
+	}
+}
+
+func (g *Game) drawDiagnostics(screen *ebiten.Image) {
+	if !g.diagnostics {
+		return
+	}
+
+	const half = diagCellSize / 2
+	for _, c := range g.diagCells {
+		x, y := g.cam.toScreen(c.X, c.Y)
+		x, y = x-half, y-half
+		switch {
+		case c.Curl > diagTol:
+			vector.DrawFilledRect(screen, x, y, diagCellSize, diagCellSize, color.RGBA{255, 0, 0, 90}, false)
+		case c.Suspicious:
+			vector.DrawFilledRect(screen, x, y, diagCellSize, diagCellSize, color.RGBA{255, 140, 0, 90}, false)
+		case c.HasCharge && c.Div > diagTol:
+			vector.StrokeRect(screen, x, y, diagCellSize, diagCellSize, 1, color.RGBA{0, 255, 120, 160}, false)
+		}
+	}
+
+	text.Draw(screen, fmt.Sprintf(tr("%s diagnostics: max curl %.1e, max div away from charges %.1e (tolerance %.0e)"),
+		keyLabel(keyDiagnostics), g.diagMaxCurl, g.diagMaxSpurious, diagTol), uiFace, ui(hintX), hintRow(0), g.theme().HUD)
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Файлы сцен, брошенные на окно: первый заменяет сцену, как Ctrl+O,
+// остальные и все брошенные с зажатым Shift добавляются к ней. Добавленные
+// заряды выделяются, чтобы их сразу можно было подвинуть на место.
+
+func (g *Game) updateDrop() {
+	files := ebiten.DroppedFiles()
+	if files == nil {
+		return
+	}
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		g.notify(tr("Load failed: ") + err.Error())
+		return
+	}
+	merge := keyPressed(ebiten.KeyShift)
+	loaded, first := 0, ""
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(path.Ext(e.Name()), ".json") {
+			continue
+		}
+		data, err := fs.ReadFile(files, e.Name())
+		if err == nil {
+			var s sceneFile
+			if s, err = parseSceneFile(e.Name(), data); err == nil {
+				if merge || loaded > 0 {
+					g.mergeSceneFile(s)
+				} else {
+					g.applySceneFile(s)
+				}
+			}
+		}
+		if err != nil {
+			g.notify(tr("Load failed: ") + err.Error())
+			return
+		}
+		if loaded == 0 {
+			first = e.Name()
+		}
+		loaded++
+	}
+	switch {
+	case loaded == 0:
+		g.notify(tr("Drop a scene .json file to load it"))
+	case merge || loaded > 1:
+		g.notify(fmt.Sprintf(tr("Added %d scene(s), %d charges in total"), loaded, len(g.charges)))
+	default:
+		g.notify(fmt.Sprintf(tr("Loaded %d charges from %s"), len(g.charges), first))
+	}
+}
+
+// mergeSceneFile добавляет заряды, провода, посевы, измерения и заметки сцены к текущей;
+// камера и настройки отображения остаются прежними.
+func (g *Game) mergeSceneFile(s sceneFile) {
+	g.checkpoint()
+	g.clearSelection()
+	for _, c := range s.Charges {
+		c.Selected = true
+		g.charges = append(g.charges, c)
+	}
+	g.wires = append(g.wires, s.Wires...)
+	g.seeding.points = append(g.seeding.points, s.Seeds...)
+	g.seeding.segments = append(g.seeding.segments, s.Flux...)
+	g.ruler.marks = append(g.ruler.marks, s.Rulers...)
+	g.protractor.marks = append(g.protractor.marks, s.Angles...)
+	g.notes.items = append(g.notes.items, s.Notes...)
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+}
@@ -0,0 +1,308 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Динамика зарядов и пробной частицы: m·a = qE - γ·m·v
+
+func (g *Game) toggleDynamics() {
+	g.dynamics = !g.dynamics
+	g.equilibrium = false
+
+	for i := range g.charges {
+		g.charges[i].VX = 0
+		g.charges[i].VY = 0
+	}
+	g.testParticle.VX = 0
+	g.testParticle.VY = 0
+	g.testParticle.UX = 0
+	g.testParticle.UY = 0
+	g.testParticle.VZ = 0
+	g.testParticle.UZ = 0
+
+	g.conservation.resetSystem()
+	g.conservation.resetTest()
+}
+
+// toggleRelativistic переключает уравнение движения пробной частицы,
+// пересчитывая импульс из текущей скорости.
+func (g *Game) toggleRelativistic() {
+	g.relativistic = !g.relativistic
+	if g.relativistic {
+		g.testParticle.syncMomentum()
+	}
+}
+
+// syncMomentum задаёт u = γv по скорости (VX, VY); сверхсветовая скорость
+// ньютоновского режима ограничивается 0.99c.
+func (p *Particle) syncMomentum() {
+	v := math.Hypot(p.VX, p.VY)
+	if v >= lightSpeed {
+		k := 0.99 * lightSpeed / v
+		p.VX *= k
+		p.VY *= k
+		v = 0.99 * lightSpeed
+	}
+	gamma := 1 / math.Sqrt(1-v*v/(lightSpeed*lightSpeed))
+	p.UX = gamma * p.VX
+	p.UY = gamma * p.VY
+}
+
+func (g *Game) adjustDamping(delta float64) {
+	g.damping = math.Max(0, math.Min(maxDamping, g.damping+delta))
+}
+
+// integrate делает шаг полунеявного Эйлера с линейным трением
+// и сообщает, находится ли тело в покое.
+func (g *Game) integrate(x, y, vx, vy *float64, ax, ay float64) bool {
+	*vx += (ax - g.damping**vx) * dynDt
+	*vy += (ay - g.damping**vy) * dynDt
+
+	*x += *vx * dynDt
+	*y += *vy * dynDt
+
+	return math.Hypot(*vx, *vy) < equilibriumSpeed && math.Hypot(ax, ay) < equilibriumAccel
+}
+
+// integrateRelativistic обновляет u = γv по d(γv)/dt = qE/m - γ_d·u,
+// откуда скорость v = u / sqrt(1 + u²/c²) никогда не превышает c.
+func (g *Game) integrateRelativistic(p *Particle, ax, ay, az float64) bool {
+	p.UX += (ax - g.damping*p.UX) * dynDt
+	p.UY += (ay - g.damping*p.UY) * dynDt
+	p.UZ += (az - g.damping*p.UZ) * dynDt
+
+	u2 := p.UX*p.UX + p.UY*p.UY + p.UZ*p.UZ
+	gamma := math.Sqrt(1 + u2/(lightSpeed*lightSpeed))
+	p.VX = p.UX / gamma
+	p.VY = p.UY / gamma
+	p.VZ = p.UZ / gamma
+
+	p.X += p.VX * dynDt
+	p.Y += p.VY * dynDt
+
+	return math.Hypot(p.VX, p.VY) < equilibriumSpeed && math.Hypot(ax, ay) < equilibriumAccel
+}
+
+func (g *Game) stepDynamics() {
+	g.trackConservation()
+
+	acc := make([]Vec2, len(g.charges))
+	field := g.bulkField()
+	for i, c := range g.charges {
+		// собственный вклад заряда в поле равен нулю (dx = dy = 0)
+		Ex, Ey := field(c.X, c.Y)
+		acc[i] = Vec2{X: c.Q * Ex / c.mass(), Y: c.Q * Ey / c.mass()}
+	}
+
+	atRest := true
+	for i := range g.charges {
+		c := &g.charges[i]
+		if c.Pinned {
+			continue
+		}
+		var rest bool
+		if c.Track != nil {
+			rest = g.integrateOnTrack(c, acc[i].X, acc[i].Y)
+		} else {
+			rest = g.integrate(&c.X, &c.Y, &c.VX, &c.VY, acc[i].X, acc[i].Y)
+		}
+		if !rest {
+			atRest = false
+			g.dirty = true
+		}
+	}
+
+	if g.mergeCollisions() {
+		atRest = false
+		g.dirty = true
+		g.conservation.rebase()
+	}
+
+	if g.testParticle.Live {
+		p := &g.testParticle
+
+		Ex, Ey := g.fieldAt(p.X, p.Y)
+		ax, ay, az := g.lorentzAccel(p, testCharge*Ex/testMass, testCharge*Ey/testMass)
+
+		var rest bool
+		if g.relativistic {
+			rest = g.integrateRelativistic(p, ax, ay, az)
+		} else {
+			rest = g.integrate(&p.X, &p.Y, &p.VX, &p.VY, ax, ay)
+			p.VZ += (az - g.damping*p.VZ) * dynDt
+			rest = rest && math.Abs(p.VZ) < equilibriumSpeed
+		}
+		if !rest {
+			atRest = false
+		}
+
+		if !inWorld(p.X, p.Y) {
+			p.Live = false
+		}
+	}
+
+	g.equilibrium = atRest
+}
+
+// Столкновения: заряды ближе mergeDistance сливаются в один с суммарным Q
+// и массой, скорость берётся из закона сохранения импульса. Равные
+// и противоположные заряды аннигилируют; излучение не моделируется, поэтому
+// их масса и импульс уходят из системы и копятся в conservation.lost.
+// Соседей ищут по сетке с шагом mergeDistance (3×3 ячейки), так что
+// проход линейный: слитый заряд остаётся на месте первого и проверяется
+// снова, а поглощённые вычищаются из списка в конце.
+
+type flash struct {
+	X, Y float64
+	Left int
+}
+
+type mergeCell struct{ x, y int }
+
+func mergeCellOf(x, y float64) mergeCell {
+	return mergeCell{int(math.Floor(x / mergeDistance)), int(math.Floor(y / mergeDistance))}
+}
+
+// mergePartner — ближайший к заряду i живой заряд ближе mergeDistance или -1.
+// В ячейках могут остаться индексы зарядов, которые уже сдвинулись или
+// поглощены: расстояние считается по текущему положению, поглощённые
+// пропускаются.
+func (g *Game) mergePartner(cells map[mergeCell][]int, gone []bool, i int) int {
+	a := g.charges[i]
+	k := mergeCellOf(a.X, a.Y)
+	best, bestD := -1, mergeDistance
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			for _, j := range cells[mergeCell{k.x + dx, k.y + dy}] {
+				if j == i || gone[j] {
+					continue
+				}
+				if d := math.Hypot(a.X-g.charges[j].X, a.Y-g.charges[j].Y); d < bestD {
+					best, bestD = j, d
+				}
+			}
+		}
+	}
+	return best
+}
+
+func (g *Game) mergeCollisions() bool {
+	cells := make(map[mergeCell][]int)
+	for i, c := range g.charges {
+		k := mergeCellOf(c.X, c.Y)
+		cells[k] = append(cells[k], i)
+	}
+	gone := make([]bool, len(g.charges))
+	merged := false
+
+	for i := range g.charges {
+		for !gone[i] {
+			j := g.mergePartner(cells, gone, i)
+			if j < 0 {
+				break
+			}
+			a, b := g.charges[i], g.charges[j]
+
+			ma, mb := a.mass(), b.mass()
+			m := ma + mb
+			c := Charge{
+				X:  (a.X*ma + b.X*mb) / m,
+				Y:  (a.Y*ma + b.Y*mb) / m,
+				VX: (a.VX*ma + b.VX*mb) / m,
+				VY: (a.VY*ma + b.VY*mb) / m,
+				Q:  a.Q + b.Q,
+				M:  m,
+			}
+			// закреплённый заряд поглощает подвижный и остаётся на месте
+			if a.Pinned || b.Pinned {
+				pin := a
+				if !a.Pinned {
+					pin = b
+				}
+				c.X, c.Y = pin.X, pin.Y
+				c.VX, c.VY = 0, 0
+				c.Pinned = true
+			}
+			// бусина передаёт направляющую результату слияния
+			if t := cmp.Or(a.Track, b.Track); t != nil && !c.Pinned {
+				c.Track = t
+				c.X, c.Y = t.project(c.X, c.Y)
+				tx, ty := t.tangent(c.X, c.Y)
+				vt := c.VX*tx + c.VY*ty
+				c.VX, c.VY = vt*tx, vt*ty
+			}
+
+			gone[j] = true
+			if math.Abs(c.Q) < 1e-9 {
+				gone[i] = true
+				g.conservation.annihilate(a, b)
+				g.flashes = append(g.flashes, flash{X: c.X, Y: c.Y, Left: flashFrames})
+			} else {
+				g.charges[i] = c
+				k := mergeCellOf(c.X, c.Y)
+				cells[k] = append(cells[k], i)
+			}
+			merged = true
+		}
+	}
+
+	if merged {
+		n := 0
+		for i, c := range g.charges {
+			if !gone[i] {
+				g.charges[n] = c
+				n++
+			}
+		}
+		clear(g.charges[n:])
+		g.charges = g.charges[:n]
+	}
+	return merged
+}
+
+func (g *Game) updateFlashes() {
+	alive := g.flashes[:0]
+	for _, f := range g.flashes {
+		f.Left--
+		if f.Left > 0 {
+			alive = append(alive, f)
+		}
+	}
+	g.flashes = alive
+}
+
+func (g *Game) drawFlashes(screen *ebiten.Image) {
+	for _, f := range g.flashes {
+		t := 1 - float64(f.Left)/flashFrames
+		r := float32(6 + 30*t)
+		a := uint8(255 * (1 - t))
+		x, y := g.cam.toScreen(f.X, f.Y)
+		vector.StrokeCircle(screen, x, y, r, 2, color.RGBA{255, 255, 255, a}, false)
+	}
+}
+
+func (g *Game) speedReadout() hintLine {
+	mode := tr("Newtonian")
+	if g.relativistic {
+		mode = tr("relativistic")
+	}
+	line := fmt.Sprintf(tr("%s: test particle %s"), keyLabel(keyRelativistic), mode)
+
+	col := color.Color(g.theme().HUD)
+	if g.dynamics && g.testParticle.Live {
+		beta := math.Hypot(g.testParticle.VX, g.testParticle.VY) / lightSpeed
+		line += fmt.Sprintf(", v/c = %.3f", beta)
+		if beta >= 1 {
+			line += tr(" (faster than light!)")
+			col = color.RGBA{255, 80, 80, 255}
+		}
+	}
+	return hintLine{line, col}
+}
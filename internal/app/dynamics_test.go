@@ -0,0 +1,34 @@
+package app
+
+import (
+	"math"
+	"testing"
+)
+
+// Слитый заряд проверяется снова: цепочка из трёх зарядов сливается в один,
+// хотя крайние сначала дальше mergeDistance.
+func TestMergeCollisionsChain(t *testing.T) {
+	g := &Game{charges: []Charge{{X: 0, Q: 1}, {X: 4, Q: 1}, {X: 7, Q: 1}}}
+	if !g.mergeCollisions() {
+		t.Fatal("слияния не было")
+	}
+	if len(g.charges) != 1 || g.charges[0].Q != 3 || g.charges[0].M != 3*chargeMass {
+		t.Fatalf("после слияния %+v, ожидался один заряд 3q массой %v", g.charges, 3*chargeMass)
+	}
+}
+
+// Аннигиляция убирает пару и записывает её массу и импульс в учёт.
+func TestMergeCollisionsAnnihilation(t *testing.T) {
+	g := &Game{charges: []Charge{{X: 0, Q: 1, VX: 1}, {X: 3, Q: -1, VX: 1}, {X: 100, Q: 1}}}
+	g.mergeCollisions()
+	if len(g.charges) != 1 || g.charges[0].X != 100 {
+		t.Fatalf("после аннигиляции %+v, ожидался только дальний заряд", g.charges)
+	}
+	l := g.conservation.lost
+	if l.pairs != 1 || math.Abs(l.mass-2*chargeMass) > 1e-12 || math.Abs(l.p.X-2*chargeMass) > 1e-12 || l.p.Y != 0 {
+		t.Fatalf("учёт аннигиляции %+v", l)
+	}
+	if len(g.flashes) != 1 {
+		t.Fatalf("вспышек %d, ожидалась одна", len(g.flashes))
+	}
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Точный ввод заряда: двойной щелчок по заряду открывает окно с полями
+// X, Y и Q. Tab переходит между полями, Enter применяет, Esc закрывает.
+// Пока окно открыто, клавиатура уходит в поля, как при наборе заметки.
+// Неразобранное число подсвечивается, и правка не применяется.
+
+const (
+	editorW          = 300
+	editorPad        = 8
+	editorRows       = 7 // заголовок, три поля, две кнопки, подсказка
+	editorMaxLen     = 24
+	doubleClickTicks = 20
+)
+
+type chargeEditor struct {
+	open   bool
+	index  int
+	fields [3][]rune // X, Y, Q
+	focus  int
+	bad    [3]bool
+	blink  int
+	ui     widgetState
+
+	lastTick   int64 // прошлый щелчок по заряду, для двойного
+	lastCharge int
+}
+
+// clickCharge отмечает щелчок по заряду i и открывает окно на втором
+// щелчке подряд.
+func (g *Game) clickCharge(i int) bool {
+	e := &g.editor
+	double := e.lastTick > 0 && i == e.lastCharge && input.tick-e.lastTick <= doubleClickTicks
+	e.lastTick, e.lastCharge = input.tick, i
+	if double {
+		g.openChargeEditor(i)
+		e.lastCharge = -1
+	}
+	return double
+}
+
+func (g *Game) openChargeEditor(i int) {
+	c := g.charges[i]
+	e := &g.editor
+	e.open, e.index, e.focus, e.bad, e.blink = true, i, 0, [3]bool{}, 0
+	for k, v := range []float64{c.X, c.Y, c.Q} {
+		e.fields[k] = []rune(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+}
+
+func (g *Game) editorRect() image.Rectangle {
+	w, h := ui(editorW), editorRows*ui(widgetRowH)+2*ui(editorPad)
+	x, y := (g.cam.W-w)/2, (g.cam.H-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func (g *Game) editorWidgets(dst *ebiten.Image) *widgets {
+	r := g.editorRect()
+	return &widgets{
+		dst: dst, th: g.theme(), st: &g.editor.ui,
+		x: r.Min.X + ui(editorPad), y: r.Min.Y + ui(editorPad), w: r.Dx() - 2*ui(editorPad),
+	}
+}
+
+// editorLayout описывает окно; сообщает о нажатых кнопках.
+func (g *Game) editorLayout(u *widgets) (apply, cancel bool) {
+	e := &g.editor
+	u.label(fmt.Sprintf(tr("Charge #%d"), e.index+1))
+	for k, name := range []string{"X", "Y", "Q"} {
+		s := string(e.fields[k])
+		if k == e.focus && e.blink/30%2 == 0 {
+			s += "|"
+		}
+		if u.field(name, s, k == e.focus, e.bad[k]) {
+			e.focus = k
+		}
+	}
+	apply = u.button(tr("Apply"))
+	cancel = u.button(tr("Cancel"))
+	u.label(tr("Tab: next field, Enter: apply, Esc: close"))
+	return apply, cancel
+}
+
+// updateChargeEditor забирает ввод, пока окно открыто.
+func (g *Game) updateChargeEditor() {
+	e := &g.editor
+	if e.index >= len(g.charges) {
+		e.open = false
+		return
+	}
+	e.blink++
+
+	u := g.editorWidgets(nil)
+	u.mx, u.my = g.cursor()
+	u.click = mouseJustPressed(ebiten.MouseButtonLeft)
+	apply, cancel := g.editorLayout(u)
+
+	switch {
+	case cancel || keyJustPressed(ebiten.KeyEscape):
+		e.open = false
+	case apply || keyJustPressed(ebiten.KeyEnter) || keyJustPressed(ebiten.KeyNumpadEnter):
+		g.applyChargeEditor()
+	case keyJustPressed(ebiten.KeyTab):
+		step := 1
+		if keyPressed(ebiten.KeyShift) {
+			step = len(e.fields) - 1
+		}
+		e.focus = (e.focus + step) % len(e.fields)
+	}
+	if !e.open {
+		g.lastLeft = mousePressed(ebiten.MouseButtonLeft)
+		return
+	}
+
+	f := &e.fields[e.focus]
+	for _, c := range inputChars() {
+		if len(*f) < editorMaxLen && (c >= '0' && c <= '9' || c == '.' || c == '-' || c == '+' || c == 'e' || c == 'E') {
+			*f = append(*f, c)
+			e.bad[e.focus] = false
+		}
+	}
+	if keyRepeated(ebiten.KeyBackspace) && len(*f) > 0 {
+		*f = (*f)[:len(*f)-1]
+		e.bad[e.focus] = false
+	}
+	if len(inputChars()) > 0 || keyPressed(ebiten.KeyBackspace) {
+		e.blink = 0
+	}
+}
+
+func (g *Game) applyChargeEditor() {
+	e := &g.editor
+	var v [3]float64
+	ok := true
+	for k := range e.fields {
+		x, err := strconv.ParseFloat(string(e.fields[k]), 64)
+		e.bad[k] = err != nil
+		if k == 2 && x == 0 {
+			e.bad[k] = true // нулевой заряд не нужен
+		}
+		ok = ok && !e.bad[k]
+		v[k] = x
+	}
+	if !ok {
+		return
+	}
+	g.checkpoint()
+	c := &g.charges[e.index]
+	if dq := v[2] - c.Q; dq != 0 {
+		g.recordEdit(EditAdjustQ, v[0], v[1], dq)
+	}
+	c.X, c.Y, c.Q = v[0], v[1], v[2]
+	c.VX, c.VY = 0, 0
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+	e.open = false
+}
+
+func (g *Game) drawChargeEditor(screen *ebiten.Image) {
+	if !g.editor.open {
+		return
+	}
+	th := g.theme()
+	r := g.editorRect()
+	vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), withAlpha(th.Panel, 240), false)
+	vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, th.Border, false)
+	g.editorLayout(g.editorWidgets(screen))
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Учёт энергии и импульса для проверки интеграторов. Опорные значения
+// запоминаются при первом шаге после правки сцены; дрейф сверх
+// energyDriftWarn подсвечивается красным. Слияние зарядов тоже обновляет
+// опору (удар неупругий), а масса и импульс аннигилировавших пар
+// показываются отдельно до следующей правки.
+
+const energyDriftWarn = 0.01
+
+type conservation struct {
+	systemSet bool
+	systemE0  float64
+	systemP0  Vec2
+
+	testSet bool
+	testE0  float64
+
+	lost annihilation
+}
+
+// annihilation — что унесли аннигилировавшие пары с последней правки.
+type annihilation struct {
+	pairs int
+	mass  float64
+	p     Vec2
+}
+
+func (c *conservation) resetSystem() { c.systemSet, c.lost = false, annihilation{} }
+func (c *conservation) resetTest()   { c.testSet = false }
+
+// rebase берёт новую опору после слияния, не сбрасывая учёт аннигиляций.
+func (c *conservation) rebase() { c.systemSet = false }
+
+func (c *conservation) annihilate(a, b Charge) {
+	c.lost.pairs++
+	for _, q := range []Charge{a, b} {
+		c.lost.mass += q.mass()
+		if !q.Pinned {
+			c.lost.p.X += q.mass() * q.VX
+			c.lost.p.Y += q.mass() * q.VY
+		}
+	}
+}
+
+func (g *Game) kineticEnergy() float64 {
+	var ke float64
+	for _, c := range g.charges {
+		if !c.Pinned {
+			ke += c.mass() * (c.VX*c.VX + c.VY*c.VY) / 2
+		}
+	}
+	return ke
+}
+
+func (g *Game) potentialEnergy() float64 {
+	var pe float64
+	for i := range g.charges {
+		for j := i + 1; j < len(g.charges); j++ {
+			a, b := g.charges[i], g.charges[j]
+			dx, dy := a.X-b.X, a.Y-b.Y
+			pe += a.Q * pointPotential(b.Q, dx*dx+dy*dy)
+		}
+	}
+	return pe
+}
+
+func (g *Game) momentum() Vec2 {
+	var p Vec2
+	for _, c := range g.charges {
+		if !c.Pinned {
+			p.X += c.mass() * c.VX
+			p.Y += c.mass() * c.VY
+		}
+	}
+	return p
+}
+
+// testParticleKinetic — кинетическая энергия пробной частицы;
+// в релятивистском режиме (γ-1)mc².
+func (g *Game) testParticleKinetic() float64 {
+	p := g.testParticle
+	if g.relativistic {
+		u2 := p.UX*p.UX + p.UY*p.UY
+		return (math.Sqrt(1+u2/(lightSpeed*lightSpeed)) - 1) * testMass * lightSpeed * lightSpeed
+	}
+	return testMass * (p.VX*p.VX + p.VY*p.VY) / 2
+}
+
+// testParticleEnergy — энергия пробной частицы во внешнем поле зарядов.
+func (g *Game) testParticleEnergy() float64 {
+	p := g.testParticle
+	return g.testParticleKinetic() + testCharge*g.potentialAt(p.X, p.Y)
+}
+
+// trackConservation запоминает опорные значения после правки сцены.
+func (g *Game) trackConservation() {
+	cs := &g.conservation
+	if !cs.systemSet {
+		cs.systemSet = true
+		cs.systemE0 = g.kineticEnergy() + g.potentialEnergy()
+		cs.systemP0 = g.momentum()
+	}
+	if !cs.testSet && g.testParticle.Live {
+		cs.testSet = true
+		cs.testE0 = g.testParticleEnergy()
+	}
+}
+
+func relDrift(v, ref float64) float64 {
+	if math.Abs(ref) < 1e-9 {
+		return math.Abs(v - ref)
+	}
+	return math.Abs(v-ref) / math.Abs(ref)
+}
+
+func (g *Game) drawConservation(screen *ebiten.Image, x, y int) {
+	if !g.dynamics {
+		return
+	}
+
+	face := uiFace
+	red := color.RGBA{255, 80, 80, 255}
+	cs := &g.conservation
+
+	ke, pe := g.kineticEnergy(), g.potentialEnergy()
+	e := ke + pe
+	p := g.momentum()
+
+	pinned := false
+	for _, c := range g.charges {
+		pinned = pinned || c.Pinned
+	}
+
+	dE := relDrift(e, cs.systemE0)
+	col := color.Color(g.theme().HUD)
+	if dE > energyDriftWarn && g.damping == 0 {
+		col = red
+	}
+	line := fmt.Sprintf(tr("KE = %.2f, PE = %.2f, E = %.2f (drift %.2f%%)"), ke, pe, e, 100*dE)
+	text.Draw(screen, line, face, x, y, col)
+
+	dP := math.Hypot(p.X-cs.systemP0.X, p.Y-cs.systemP0.Y)
+	col = g.theme().HUD
+	if dP > energyDriftWarn && !pinned {
+		col = red
+	}
+	note := ""
+	if pinned {
+		note = tr(" (pinned charges absorb momentum)")
+	}
+	if l := cs.lost; l.pairs > 0 {
+		note += fmt.Sprintf(tr(", annihilated %d pairs: mass %.2f, P = (%.3f, %.3f)"), l.pairs, l.mass, l.p.X, l.p.Y)
+	}
+	text.Draw(screen, fmt.Sprintf(tr("P = (%.3f, %.3f), |dP| = %.3f%s"), p.X, p.Y, dP, note), face, x, y+hintLineH(), col)
+
+	if g.testParticle.Live {
+		te := g.testParticleEnergy()
+		dT := relDrift(te, cs.testE0)
+		col = g.theme().HUD
+		if dT > energyDriftWarn && g.damping == 0 && allPinned(g.charges) {
+			col = red
+		}
+		text.Draw(screen, fmt.Sprintf(tr("Test particle E = %.2f (drift %.2f%%)"), te, 100*dT), face, x, y+2*hintLineH(), col)
+	}
+
+	if g.damping > 0 {
+		text.Draw(screen, tr("damping > 0: energy is dissipated"), face, x+textWidth(line)+ui(20), y, color.RGBA{180, 180, 180, 255})
+	}
+}
+
+// allPinned: энергия пробной частицы сохраняется только в статическом поле.
+func allPinned(charges []Charge) bool {
+	for _, c := range charges {
+		if !c.Pinned {
+			return false
+		}
+	}
+	return true
+}
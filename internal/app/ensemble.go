@@ -0,0 +1,225 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// Пакетный безголовый режим: ансамбль пробных частиц из заданного
+// распределения начальных условий, статистика захвата и углов вылета.
+
+type EnsembleDistribution struct {
+	Kind   string  `json:"kind"` // uniform_box, gaussian, ring
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	Sigma  float64 `json:"sigma,omitempty"`
+	Radius float64 `json:"radius,omitempty"`
+}
+
+type EnsembleConfig struct {
+	Charges      []Charge             `json:"charges"`
+	Count        int                  `json:"count"`
+	Start        EnsembleDistribution `json:"start"`
+	Speed        float64              `json:"speed"`        // начальная скорость
+	SpeedSpread  float64              `json:"speed_spread"` // равномерный разброс скорости
+	Damping      float64              `json:"damping"`
+	MaxSteps     int                  `json:"max_steps"`
+	CaptureR     float64              `json:"capture_radius"`
+	EscapeR      float64              `json:"escape_radius"`
+	AngleBins    int                  `json:"angle_bins"`
+	Seed         uint64               `json:"seed"`
+	Workers      int                  `json:"workers"`
+	Relativistic bool                 `json:"relativistic"`
+}
+
+type EnsembleResult struct {
+	Count          int       `json:"count"`
+	CaptureCounts  []int     `json:"capture_counts"`
+	CaptureFrac    []float64 `json:"capture_fractions"`
+	Escaped        int       `json:"escaped"`
+	EscapedFrac    float64   `json:"escaped_fraction"`
+	Bounded        int       `json:"bounded"` // не завершились за max_steps
+	BoundedFrac    float64   `json:"bounded_fraction"`
+	ExitAngleBins  []float64 `json:"exit_angle_bin_edges_deg"`
+	ExitAngleHisto []int     `json:"exit_angle_histogram"`
+	MeanSteps      float64   `json:"mean_steps"`
+}
+
+func defaultEnsembleConfig() EnsembleConfig {
+	return EnsembleConfig{
+		Charges: []Charge{
+			{X: -150, Y: 0, Q: +1},
+			{X: +150, Y: 0, Q: -1},
+		},
+		Count:     1000,
+		Start:     EnsembleDistribution{Kind: "uniform_box", Width: 2 * halfW, Height: 2 * halfH},
+		Damping:   defaultDamping,
+		MaxSteps:  20000,
+		CaptureR:  seedRadius,
+		EscapeR:   2000,
+		AngleBins: 36,
+		Seed:      1,
+	}
+}
+
+func loadEnsembleConfig(path string) (EnsembleConfig, error) {
+	cfg := defaultEnsembleConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Count <= 0 || cfg.AngleBins <= 0 || cfg.MaxSteps <= 0 {
+		return cfg, fmt.Errorf("%s: count, angle_bins and max_steps must be positive", path)
+	}
+	return cfg, nil
+}
+
+func (d EnsembleDistribution) sample(r *rand.Rand) (float64, float64) {
+	switch d.Kind {
+	case "gaussian":
+		return d.X + r.NormFloat64()*d.Sigma, d.Y + r.NormFloat64()*d.Sigma
+	case "ring":
+		a := 2 * math.Pi * r.Float64()
+		return d.X + d.Radius*math.Cos(a), d.Y + d.Radius*math.Sin(a)
+	default:
+		return d.X + (r.Float64()-0.5)*d.Width, d.Y + (r.Float64()-0.5)*d.Height
+	}
+}
+
+type ensembleOutcome struct {
+	captured int // индекс заряда или -1
+	escaped  bool
+	angle    float64
+	steps    int
+}
+
+func (g *Game) runEnsembleParticle(cfg *EnsembleConfig, r *rand.Rand) ensembleOutcome {
+	return g.runToTermination(cfg.sampleParticle(r), cfg.CaptureR, cfg.EscapeR, cfg.MaxSteps)
+}
+
+// sampleParticle разыгрывает начальное состояние частицы. Импульс γv
+// нужен только релятивистскому интегратору; ньютоновская частица
+// сохраняет разыгранную скорость, даже сверхсветовую.
+func (cfg *EnsembleConfig) sampleParticle(r *rand.Rand) Particle {
+	x, y := cfg.Start.sample(r)
+	speed := cfg.Speed + (r.Float64()-0.5)*cfg.SpeedSpread
+	dir := 2 * math.Pi * r.Float64()
+	p := Particle{X: x, Y: y, VX: speed * math.Cos(dir), VY: speed * math.Sin(dir), Live: true}
+	if cfg.Relativistic {
+		p.syncMomentum()
+	}
+	return p
+}
+
+// runToTermination ведёт частицу, пока она не захвачена зарядом,
+// не улетела дальше escapeR или не исчерпан лимит шагов.
+func (g *Game) runToTermination(p Particle, captureR, escapeR float64, maxSteps int) ensembleOutcome {
+	tree := newQuadTree(g.charges)
+	for step := 0; step < maxSteps; step++ {
+		if i := tree.nearest(p.X, p.Y, captureR); i >= 0 {
+			return ensembleOutcome{captured: i, steps: step}
+		}
+		if math.Hypot(p.X, p.Y) > escapeR {
+			return ensembleOutcome{captured: -1, escaped: true, angle: math.Atan2(p.Y, p.X), steps: step}
+		}
+
+		Ex, Ey := g.fieldAt(p.X, p.Y)
+		ax, ay := testCharge*Ex/testMass, testCharge*Ey/testMass
+		if g.relativistic {
+			g.integrateRelativistic(&p, ax, ay, 0)
+		} else {
+			g.integrate(&p.X, &p.Y, &p.VX, &p.VY, ax, ay)
+		}
+	}
+	return ensembleOutcome{captured: -1, steps: maxSteps}
+}
+
+// RunEnsemble интегрирует частицы параллельно; у каждой частицы свой
+// генератор, так что результат не зависит от числа потоков.
+func RunEnsemble(cfg EnsembleConfig) EnsembleResult {
+	g := &Game{charges: cfg.Charges, damping: cfg.Damping, relativistic: cfg.Relativistic}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	outcomes := make([]ensembleOutcome, cfg.Count)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := rand.New(rand.NewPCG(cfg.Seed, uint64(i)))
+				outcomes[i] = g.runEnsembleParticle(&cfg, r)
+			}
+		}()
+	}
+	for i := range outcomes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	res := EnsembleResult{
+		Count:          cfg.Count,
+		CaptureCounts:  make([]int, len(cfg.Charges)),
+		CaptureFrac:    make([]float64, len(cfg.Charges)),
+		ExitAngleBins:  make([]float64, cfg.AngleBins+1),
+		ExitAngleHisto: make([]int, cfg.AngleBins),
+	}
+	for i := range res.ExitAngleBins {
+		res.ExitAngleBins[i] = -180 + 360*float64(i)/float64(cfg.AngleBins)
+	}
+
+	totalSteps := 0
+	for _, o := range outcomes {
+		totalSteps += o.steps
+		switch {
+		case o.captured >= 0:
+			res.CaptureCounts[o.captured]++
+		case o.escaped:
+			res.Escaped++
+			bin := int((o.angle + math.Pi) / (2 * math.Pi) * float64(cfg.AngleBins))
+			res.ExitAngleHisto[min(bin, cfg.AngleBins-1)]++
+		default:
+			res.Bounded++
+		}
+	}
+
+	n := float64(cfg.Count)
+	for i, c := range res.CaptureCounts {
+		res.CaptureFrac[i] = float64(c) / n
+	}
+	res.EscapedFrac = float64(res.Escaped) / n
+	res.BoundedFrac = float64(res.Bounded) / n
+	res.MeanSteps = float64(totalSteps) / n
+	return res
+}
+
+func RunEnsembleCommand(configPath string, out io.Writer) error {
+	cfg := defaultEnsembleConfig()
+	if configPath != "-" {
+		var err error
+		if cfg, err = loadEnsembleConfig(configPath); err != nil {
+			return err
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(RunEnsemble(cfg))
+}
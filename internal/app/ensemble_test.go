@@ -0,0 +1,34 @@
+package app
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// Ньютоновская частица стартует с разыгранной скоростью, даже если она
+// больше c; релятивистская — с импульсом γv и скоростью не выше 0.99c.
+func TestSampleParticleSpeed(t *testing.T) {
+	cfg := defaultEnsembleConfig()
+	cfg.Speed = 2 * lightSpeed
+	cfg.SpeedSpread = 0
+
+	for _, rel := range []bool{false, true} {
+		cfg.Relativistic = rel
+		r := rand.New(rand.NewPCG(cfg.Seed, 0))
+		for range 100 {
+			p := cfg.sampleParticle(r)
+			v := math.Hypot(p.VX, p.VY)
+			if !rel {
+				if math.Abs(v-cfg.Speed) > 1e-9 || p.UX != 0 || p.UY != 0 {
+					t.Fatalf("ньютоновская частица: v = %v, u = (%v, %v), разыграно %v", v, p.UX, p.UY, cfg.Speed)
+				}
+				continue
+			}
+			gamma := 1 / math.Sqrt(1-v*v/(lightSpeed*lightSpeed))
+			if v >= lightSpeed || math.Abs(p.UX-gamma*p.VX) > 1e-9 || math.Abs(p.UY-gamma*p.VY) > 1e-9 {
+				t.Fatalf("релятивистская частица: v = %v, u = (%v, %v), γv = (%v, %v)", v, p.UX, p.UY, gamma*p.VX, gamma*p.VY)
+			}
+		}
+	}
+}
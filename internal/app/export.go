@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Экспорт картинки для слайдов: сцена заново считается и рисуется вне
+// экрана в exportScale раз большем разрешении (фон, линии, стрелки),
+// без подсказок интерфейса. Живой вид при этом не меняется.
+
+var exportScales = []int{2, 3, 4}
+
+func (g *Game) cycleExportScale() {
+	g.exportScale = (g.exportScale + 1) % len(exportScales)
+}
+
+func (g *Game) exportImage() {
+	k := exportScales[g.exportScale]
+	live := g.cam
+
+	g.cam = live.scaled(k)
+	g.bgImage = nil
+	g.recomputeAll()
+	img := ebiten.NewImage(g.cam.W, g.cam.H)
+	g.drawBackground(img)
+	g.drawGlow(img)
+	g.drawScene(img)
+
+	rgba := image.NewRGBA(image.Rect(0, 0, g.cam.W, g.cam.H))
+	img.ReadPixels(rgba.Pix)
+	img.Deallocate()
+
+	// живой вид пересчитается в следующем кадре
+	g.cam = live
+	g.bgImage = nil
+	g.dirty = true
+
+	path := fmt.Sprintf("field-%dx-%s.png", k, time.Now().Format("20060102-150405"))
+	if err := writePNG(path, rgba); err != nil {
+		g.notify(tr("Image export failed: ") + err.Error())
+		return
+	}
+	g.notify(fmt.Sprintf(tr("Exported %dx%d image to %s"), rgba.Bounds().Dx(), rgba.Bounds().Dy(), path))
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// Экспорт трёхмерных силовых линий и маркеров зарядов в OBJ и glTF 2.0.
+// Экранная ось y направлена вниз, в файлах используется правая система
+// с осью y вверх; единицы — метры при exportScale пикселей на метр.
+
+const (
+	exportScale      = 100.0 // пикселей на единицу длины в файле
+	exportMarkerSize = 8.0   // радиус октаэдра-маркера заряда в пикселях
+)
+
+func exportPoint(p Vec3) [3]float32 {
+	return [3]float32{float32(p.X / exportScale), float32(-p.Y / exportScale), float32(p.Z / exportScale)}
+}
+
+// octahedron возвращает вершины и треугольники маркера заряда.
+func octahedron(c Charge) ([]Vec3, [][3]int) {
+	r := exportMarkerSize
+	v := []Vec3{
+		{c.X + r, c.Y, 0}, {c.X - r, c.Y, 0},
+		{c.X, c.Y + r, 0}, {c.X, c.Y - r, 0},
+		{c.X, c.Y, r}, {c.X, c.Y, -r},
+	}
+	f := [][3]int{
+		{0, 2, 4}, {2, 1, 4}, {1, 3, 4}, {3, 0, 4},
+		{2, 0, 5}, {1, 2, 5}, {3, 1, 5}, {0, 3, 5},
+	}
+	return v, f
+}
+
+func writeOBJ(path string, lines [][]Vec3, charges []Charge) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "# electric field lines")
+	next := 1
+
+	fmt.Fprintln(w, "o field_lines")
+	for _, line := range lines {
+		for _, p := range line {
+			e := exportPoint(p)
+			fmt.Fprintf(w, "v %g %g %g\n", e[0], e[1], e[2])
+		}
+		fmt.Fprint(w, "l")
+		for i := range line {
+			fmt.Fprintf(w, " %d", next+i)
+		}
+		fmt.Fprintln(w)
+		next += len(line)
+	}
+
+	for i, c := range charges {
+		sign := "pos"
+		if c.Q < 0 {
+			sign = "neg"
+		}
+		fmt.Fprintf(w, "o charge_%d_%s\n", i+1, sign)
+		verts, faces := octahedron(c)
+		for _, p := range verts {
+			e := exportPoint(p)
+			fmt.Fprintf(w, "v %g %g %g\n", e[0], e[1], e[2])
+		}
+		for _, t := range faces {
+			fmt.Fprintf(w, "f %d %d %d\n", next+t[0], next+t[1], next+t[2])
+		}
+		next += len(verts)
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// glTF: один буфер, примитив LINES для линий и TRIANGLES для маркеров
+// положительных и отрицательных зарядов с отдельными материалами.
+
+type gltfDoc struct {
+	Asset       map[string]string `json:"asset"`
+	Scene       int               `json:"scene"`
+	Scenes      []map[string]any  `json:"scenes"`
+	Nodes       []map[string]any  `json:"nodes"`
+	Meshes      []map[string]any  `json:"meshes"`
+	Materials   []map[string]any  `json:"materials"`
+	Buffers     []map[string]any  `json:"buffers"`
+	BufferViews []map[string]any  `json:"bufferViews"`
+	Accessors   []map[string]any  `json:"accessors"`
+}
+
+type gltfBuilder struct {
+	doc gltfDoc
+	buf bytes.Buffer
+}
+
+func (b *gltfBuilder) addView(data any, target int) int {
+	for b.buf.Len()%4 != 0 {
+		b.buf.WriteByte(0)
+	}
+	off := b.buf.Len()
+	binary.Write(&b.buf, binary.LittleEndian, data)
+	b.doc.BufferViews = append(b.doc.BufferViews, map[string]any{
+		"buffer": 0, "byteOffset": off, "byteLength": b.buf.Len() - off, "target": target,
+	})
+	return len(b.doc.BufferViews) - 1
+}
+
+func (b *gltfBuilder) addPositions(pts [][3]float32) int {
+	lo := [3]float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	hi := [3]float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	for _, p := range pts {
+		for k := 0; k < 3; k++ {
+			lo[k] = min(lo[k], p[k])
+			hi[k] = max(hi[k], p[k])
+		}
+	}
+	view := b.addView(pts, 34962)
+	b.doc.Accessors = append(b.doc.Accessors, map[string]any{
+		"bufferView": view, "componentType": 5126, "count": len(pts), "type": "VEC3",
+		"min": lo[:], "max": hi[:],
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func (b *gltfBuilder) addIndices(idx []uint32) int {
+	view := b.addView(idx, 34963)
+	b.doc.Accessors = append(b.doc.Accessors, map[string]any{
+		"bufferView": view, "componentType": 5125, "count": len(idx), "type": "SCALAR",
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func (b *gltfBuilder) addMaterial(r, g, bl float64) int {
+	b.doc.Materials = append(b.doc.Materials, map[string]any{
+		"pbrMetallicRoughness": map[string]any{"baseColorFactor": []float64{r, g, bl, 1}, "metallicFactor": 0},
+	})
+	return len(b.doc.Materials) - 1
+}
+
+func (b *gltfBuilder) addMesh(name string, pts [][3]float32, idx []uint32, mode, material int) {
+	if len(idx) == 0 {
+		return
+	}
+	pos := b.addPositions(pts)
+	ind := b.addIndices(idx)
+	b.doc.Meshes = append(b.doc.Meshes, map[string]any{
+		"name": name,
+		"primitives": []map[string]any{{
+			"attributes": map[string]int{"POSITION": pos}, "indices": ind, "mode": mode, "material": material,
+		}},
+	})
+	b.doc.Nodes = append(b.doc.Nodes, map[string]any{"name": name, "mesh": len(b.doc.Meshes) - 1})
+}
+
+func writeGLTF(path string, lines [][]Vec3, charges []Charge) error {
+	b := &gltfBuilder{}
+	b.doc.Asset = map[string]string{"version": "2.0", "generator": "electric-field"}
+
+	var pts [][3]float32
+	var idx []uint32
+	for _, line := range lines {
+		base := uint32(len(pts))
+		for i, p := range line {
+			pts = append(pts, exportPoint(p))
+			if i > 0 {
+				idx = append(idx, base+uint32(i-1), base+uint32(i))
+			}
+		}
+	}
+	b.addMesh("field_lines", pts, idx, 1, b.addMaterial(1, 1, 1))
+
+	for _, positive := range []bool{true, false} {
+		var tp [][3]float32
+		var ti []uint32
+		for _, c := range charges {
+			if (c.Q >= 0) != positive {
+				continue
+			}
+			verts, faces := octahedron(c)
+			base := uint32(len(tp))
+			for _, v := range verts {
+				tp = append(tp, exportPoint(v))
+			}
+			for _, f := range faces {
+				ti = append(ti, base+uint32(f[0]), base+uint32(f[1]), base+uint32(f[2]))
+			}
+		}
+		if positive {
+			b.addMesh("positive_charges", tp, ti, 4, b.addMaterial(1, 0.3, 0.3))
+		} else {
+			b.addMesh("negative_charges", tp, ti, 4, b.addMaterial(0.3, 0.3, 1))
+		}
+	}
+
+	nodes := make([]int, len(b.doc.Nodes))
+	for i := range nodes {
+		nodes[i] = i
+	}
+	b.doc.Scenes = []map[string]any{{"nodes": nodes}}
+	b.doc.Buffers = []map[string]any{{
+		"byteLength": b.buf.Len(),
+		"uri":        "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(b.buf.Bytes()),
+	}}
+
+	data, err := json.MarshalIndent(b.doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (g *Game) export3D() {
+	lines := g.fieldLines3D()
+	base := "fieldlines-" + time.Now().Format("20060102-150405")
+
+	if err := writeOBJ(base+".obj", lines, g.charges); err != nil {
+		g.notify(tr("OBJ export failed: ") + err.Error())
+		return
+	}
+	if err := writeGLTF(base+".gltf", lines, g.charges); err != nil {
+		g.notify(tr("glTF export failed: ") + err.Error())
+		return
+	}
+	g.notify(fmt.Sprintf(tr("Exported %d 3D field lines to %s.obj and %s.gltf"), len(lines), base, base))
+}
@@ -0,0 +1,80 @@
+package app
+
+import "math"
+
+// Трёхмерные силовые линии зарядов плоскости z = 0. Затравки равномерно
+// распределены по сфере вокруг заряда (спираль Фибоначчи).
+
+const (
+	seeds3DPerUnitQ = 24  // линий на единицу заряда в 3D
+	field3DBound    = 600 // полуразмер области трассировки по z
+)
+
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+func fibonacciSphere(n int) []Vec3 {
+	pts := make([]Vec3, n)
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := range pts {
+		z := 1 - 2*(float64(i)+0.5)/float64(n)
+		r := math.Sqrt(1 - z*z)
+		s, c := math.Sincos(golden * float64(i))
+		pts[i] = Vec3{X: r * c, Y: r * s, Z: z}
+	}
+	return pts
+}
+
+func (g *Game) traceFieldLine3D(tree *quadTree, start Vec3, dir float64) []Vec3 {
+	p := start
+	points := make([]Vec3, 0, 256)
+	points = append(points, p)
+
+	for i := 0; i < fieldLineMaxLen; i++ {
+		Ex, Ey, Ez := g.fieldAt3(p.X, p.Y, p.Z)
+		E := math.Sqrt(Ex*Ex + Ey*Ey + Ez*Ez)
+		if E < 1e-6 {
+			break
+		}
+
+		k := dir * fieldLineStep / E
+		p = Vec3{X: p.X + Ex*k, Y: p.Y + Ey*k, Z: p.Z + Ez*k}
+
+		if !inWorld(p.X, p.Y) || math.Abs(p.Z) > field3DBound {
+			break
+		}
+
+		// заряды лежат в z = 0: шар seedRadius режет плоскость по кругу
+		if z2 := p.Z * p.Z; z2 < seedRadius*seedRadius && tree.near(p.X, p.Y, math.Sqrt(seedRadius*seedRadius-z2)) {
+			break
+		}
+
+		points = append(points, p)
+	}
+
+	return points
+}
+
+func (g *Game) fieldLines3D() [][]Vec3 {
+	var lines [][]Vec3
+	tree := newQuadTree(g.charges)
+	for _, c := range g.charges {
+		n := 0
+		if c.Q != 0 {
+			n = max(1, int(math.Round(seeds3DPerUnitQ*math.Abs(c.Q))))
+		}
+
+		dir := 1.0
+		if c.Q < 0 {
+			dir = -1.0
+		}
+		for _, s := range fibonacciSphere(n) {
+			start := Vec3{X: c.X + seedRadius*s.X, Y: c.Y + seedRadius*s.Y, Z: seedRadius * s.Z}
+			if line := g.traceFieldLine3D(tree, start, dir); len(line) > 1 {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,84 @@
+package app
+
+import "slices"
+
+// Поле под теплокартой хранится попиксельно (Ex, Ey), а не только яркостью.
+// По принципу суперпозиции добавление заряда прибавляет к нему поле одного
+// этого заряда, удаление — вычитает, так что правка одного заряда стоит
+// одного слагаемого на пиксель вместо суммы по всем зарядам. Если заряды
+// сдвинулись (перетаскивание, динамика), сменились вид, k, решатель или
+// включена секущая плоскость, поле считается заново целиком.
+
+type fieldCache struct {
+	ex, ey  []float64
+	charges []Charge // заряды, по которым посчитано поле; nil — кэш пуст
+	cam     camera
+	k       float64
+}
+
+// sameCharge сравнивает то, от чего зависит поле.
+func sameCharge(a, b Charge) bool {
+	return a.X == b.X && a.Y == b.Y && a.Q == b.Q
+}
+
+// chargeDelta находит заряды, которые надо прибавить к старому полю, чтобы
+// получить новое: дописанные в конец или один удалённый (с обратным
+// знаком). ok == false, если так разницу не выразить.
+func chargeDelta(old, cur []Charge) (delta []Charge, ok bool) {
+	if len(cur) >= len(old) && slices.EqualFunc(old, cur[:len(old)], sameCharge) {
+		return cur[len(old):], true
+	}
+	if len(cur) != len(old)-1 {
+		return nil, false
+	}
+	i := 0
+	for i < len(cur) && sameCharge(old[i], cur[i]) {
+		i++
+	}
+	if !slices.EqualFunc(old[i+1:], cur[i:], sameCharge) {
+		return nil, false
+	}
+	gone := old[i]
+	gone.Q = -gone.Q
+	return []Charge{gone}, true
+}
+
+// updateFieldCache приводит кэш поля к текущим зарядам и виду.
+func (g *Game) updateFieldCache() {
+	fc := &g.fieldCache
+	w, h := g.cam.W, g.cam.H
+	exact := g.solver == nil && !g.cut.active // суперпозиция верна только для прямой суммы
+	if len(fc.ex) != w*h {
+		fc.ex, fc.ey, fc.charges = make([]float64, w*h), make([]float64, w*h), nil
+	}
+
+	var delta []Charge
+	ok := exact && fc.charges != nil && fc.cam == g.cam && fc.k == kConst
+	if ok {
+		delta, ok = chargeDelta(fc.charges, g.charges)
+	}
+	switch {
+	case ok && len(delta) < len(g.charges):
+		if len(delta) > 0 {
+			parallelRows(h, func(py int) {
+				for px := 0; px < w; px++ {
+					x, y := g.cam.toWorld(float64(px), float64(py))
+					Ex, Ey := directField(delta, x, y)
+					fc.ex[py*w+px] += Ex
+					fc.ey[py*w+px] += Ey
+				}
+			})
+		}
+	default:
+		parallelRows(h, func(py int) {
+			for px := 0; px < w; px++ {
+				fc.ex[py*w+px], fc.ey[py*w+px] = g.sliceField(g.cam.toWorld(float64(px), float64(py)))
+			}
+		})
+	}
+
+	fc.charges, fc.cam, fc.k = nil, g.cam, kConst
+	if exact {
+		fc.charges = slices.Clone(g.charges)
+	}
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"electric-field/internal/colormap"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Галерея для документации: набор сцен и режимов, каждая снимается
+// одним кадром без подсказок интерфейса и сохраняется в PNG.
+
+type galleryShot struct {
+	File   string
+	Setup  func(g *Game)
+	Frames int // кадров Update перед снимком (для сценариев с движением)
+}
+
+func presetByName(name string) Preset {
+	for _, p := range presets {
+		if p.Name == name {
+			return p
+		}
+	}
+	panic("gallery: unknown preset " + name)
+}
+
+func withPreset(name string) func(g *Game) {
+	return func(g *Game) { g.applyPreset(presetByName(name)) }
+}
+
+func scenarioByID(id string) int {
+	for i, f := range scenarioFactories {
+		if f.id == id {
+			return i
+		}
+	}
+	panic("gallery: unknown scenario " + id)
+}
+
+func withScenario(id string) func(g *Game) {
+	return func(g *Game) {
+		g.scenarioIndex = scenarioByID(id)
+		g.scenario = scenarioFactories[g.scenarioIndex].new()
+	}
+}
+
+func solverByID(id string) int {
+	for i, f := range solverFactories {
+		if f.id == id {
+			return i
+		}
+	}
+	panic("gallery: unknown solver " + id)
+}
+
+func colormapIndex(m *colormap.Map) int {
+	i := slices.Index(colormap.All, m)
+	if i < 0 {
+		panic("gallery: colormap " + m.Name + " is not in colormap.All")
+	}
+	return i
+}
+
+var galleryShots = []galleryShot{
+	{File: "dipole.png", Setup: func(g *Game) {}},
+	{File: "linear-quadrupole.png", Setup: withPreset("Linear quadrupole")},
+	{File: "square-quadrupole.png", Setup: withPreset("Square quadrupole")},
+	{File: "alternating-line.png", Setup: withPreset("Line of alternating charges")},
+	{File: "viridis.png", Setup: func(g *Game) {
+		g.applyPreset(presetByName("Linear quadrupole"))
+		g.colormap = colormapIndex(colormap.Viridis)
+	}},
+	{File: "equipotentials.png", Setup: func(g *Game) {
+		g.applyPreset(presetByName("Square quadrupole"))
+		g.contours = true
+	}},
+	{File: "potential-surface.png", Setup: func(g *Game) { g.surfaceView = true }},
+	{File: "lic.png", Setup: func(g *Game) {
+		g.applyPreset(presetByName("Square quadrupole"))
+		g.bgMode = BackgroundLIC
+		g.colormap = colormapIndex(colormap.Inferno)
+	}},
+	{File: "basins.png", Setup: func(g *Game) {
+		g.applyPreset(presetByName("Square quadrupole"))
+		g.bgMode = BackgroundBasins
+	}},
+	{File: "cut-plane.png", Setup: func(g *Game) {
+		g.cut.active = true
+		g.cut.tilt = 0.6
+	}},
+	{File: "div-curl.png", Setup: func(g *Game) { g.diagnostics = true }},
+	{File: "poisson-solver.png", Setup: func(g *Game) {
+		g.solverIndex = solverByID("poisson")
+		g.solver = solverFactories[g.solverIndex].new()
+	}},
+	{File: "wires.png", Setup: func(g *Game) {
+		g.wires = []Wire{{X: 0, Y: -120, I: +1}, {X: 0, Y: 120, I: -1}}
+	}},
+	{File: "bead-on-ring.png", Setup: withPreset("Bead on a ring near a fixed charge")},
+	{File: "crt.png", Setup: withScenario("crt"), Frames: 180},
+	{File: "paul-trap.png", Setup: withScenario("paul-trap"), Frames: 180},
+	{File: "einzel-lens.png", Setup: withScenario("einzel-lens"), Frames: 60},
+	{File: "pendulum.png", Setup: withScenario("pendulum"), Frames: 180},
+}
+
+type galleryRunner struct {
+	dir   string
+	next  int
+	game  *Game
+	frame int
+	err   error
+}
+
+func (r *galleryRunner) Update() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.game == nil {
+		if r.next == len(galleryShots) {
+			return ebiten.Termination
+		}
+		r.game = NewGame()
+		r.game.hideHUD = true
+		galleryShots[r.next].Setup(r.game)
+		r.frame = 0
+	}
+
+	if r.game.dirty {
+		r.game.recomputeAll()
+	}
+	if r.game.scenario != nil {
+		r.game.scenario.Update()
+	}
+	r.frame++
+	return nil
+}
+
+func (r *galleryRunner) Draw(screen *ebiten.Image) {
+	if r.game == nil || r.game.dirty {
+		return
+	}
+	r.game.Draw(screen)
+
+	shot := galleryShots[r.next]
+	if r.frame < max(shot.Frames, 1) {
+		return
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, screenWidth, screenHeight))
+	screen.ReadPixels(img.Pix)
+	r.err = writePNG(filepath.Join(r.dir, shot.File), img)
+
+	r.game = nil
+	r.next++
+}
+
+func (r *galleryRunner) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// RunGallery перерисовывает все кадры галереи в каталог dir.
+func RunGallery(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle(tr("Gallery"))
+	ebiten.SetVsyncEnabled(false)
+	return ebiten.RunGame(&galleryRunner{dir: dir})
+}
@@ -0,0 +1,1428 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/contour"
+)
+
+// kConst — кулоновская константа; переменная, чтобы её можно было задать
+// флагом или ползунком в настройках (в пределах kConstMin..kConstMax).
+var kConst = 2000.0
+
+const (
+	kConstMin = 200.0
+	kConstMax = 10000.0
+)
+
+const (
+	defaultWidth  = 900
+	defaultHeight = 600
+	minWidth      = 640 // меньше подсказки и панели не помещаются
+	minHeight     = 480
+
+	minR2 = 16.0 // r^2
+
+	fieldLineStep   = 3.0  // шаг интегрирования линий поля
+	fieldLineMaxLen = 1500 // максимальное кол-во шагов линии
+	seedRadius      = 8.0  // стартовая дистанция точки линии от заряда
+	seedsPerUnitQ   = 20   // сколько линий на единицу заряда (плотность потока)
+	arrowGridStep   = 40   // шаг сетки стрелок по умолчанию
+	testStep        = 2.0  // шаг пробного заряда вдоль поля
+	bgScale         = 0.03 // масштаб для яркости фона по модулю поля
+
+	dynDt            = 1.0  // шаг интегрирования динамики
+	chargeMass       = 1.0  // масса подвижного заряда
+	testCharge       = 1.0  // заряд пробной частицы
+	testMass         = 1.0  // масса пробной частицы
+	defaultDamping   = 0.05 // коэффициент линейного трения γ
+	dampingStep      = 0.01 // шаг изменения γ с клавиатуры
+	maxDamping       = 1.0
+	equilibriumSpeed = 1e-3 // порог скорости для статического равновесия
+	equilibriumAccel = 1e-3 // порог ускорения для статического равновесия
+	lightSpeed       = 10.0 // скорость света в единицах симуляции (пикс/шаг)
+	mergeDistance    = 6.0  // расстояние слияния зарядов при столкновении
+	flashFrames      = 30   // длительность вспышки аннигиляции
+	chargeRadius     = 7.0  // радиус отрисовки заряда
+	pickRadius       = 10.0 // радиус попадания курсором в заряд
+)
+
+// Размер окна и половины мира, который он показывает при единичном
+// масштабе. Меняются вместе с окном, см. resizeScreen.
+var (
+	screenWidth  = defaultWidth
+	screenHeight = defaultHeight
+
+	halfW = float64(screenWidth) / 2
+	halfH = float64(screenHeight) / 2
+)
+
+// resizeScreen подстраивает размеры под окно и сообщает, изменились ли они.
+func resizeScreen(w, h int) bool {
+	w, h = max(w, minWidth), max(h, minHeight)
+	if w == screenWidth && h == screenHeight {
+		return false
+	}
+	screenWidth, screenHeight = w, h
+	halfW, halfH = float64(w)/2, float64(h)/2
+	return true
+}
+
+// resize меняет область вида сцены: кэши размером с экран строятся заново.
+func (g *Game) resize(w, h int) {
+	g.cam.W, g.cam.H = w, h
+	g.layoutHUD()
+	g.bgImage = nil
+	g.contourGrid = nil
+	if g.solver != nil {
+		g.solver = solverFactories[g.solverIndex].new() // сетка Пуассона покрывает экран
+	}
+	g.dirty = true
+}
+
+type Charge struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Q      float64 `json:"q"`
+	VX     float64 `json:"vx,omitempty"`
+	VY     float64 `json:"vy,omitempty"`
+	M      float64 `json:"m,omitempty"`      // масса в динамике, 0 означает chargeMass
+	Pinned bool    `json:"pinned,omitempty"` // закреплён в режиме динамики (электрод)
+	Track  *Track  `json:"track,omitempty"`  // направляющая, по которой движется заряд
+
+	Selected bool `json:"-"` // выделен для групповой правки
+}
+
+func (c Charge) mass() float64 {
+	if c.M > 0 {
+		return c.M
+	}
+	return chargeMass
+}
+
+type Vec2 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Particle struct {
+	X, Y   float64
+	VX, VY float64
+	VZ     float64 // скорость вдоль оси провода, появляется от силы Лоренца
+	UX, UY float64 // γv, импульс на единицу массы для релятивистского режима
+	UZ     float64
+	Live   bool
+}
+
+type BackgroundMode int
+
+const (
+	BackgroundFieldMagnitude BackgroundMode = iota
+	BackgroundBasins
+	BackgroundLIC
+	BackgroundPotential
+	backgroundModeCount
+)
+
+func (m BackgroundMode) String() string {
+	switch m {
+	case BackgroundBasins:
+		return tr("basins of attraction")
+	case BackgroundLIC:
+		return tr("line integral convolution")
+	case BackgroundPotential:
+		return tr("signed potential V")
+	default:
+		return "|E|"
+	}
+}
+
+type Game struct {
+	charges []Charge
+
+	fieldLines [][]Vec2
+
+	cam        camera
+	grid       bool // координатная сетка и оси
+	probe      bool // показания E и V у курсора
+	labels     bool // подписи величины зарядов
+	trails     [][]Vec2
+	lines      lineStyle
+	themes     []Theme // тёмная, светлая и, если есть theme.json, пользовательская
+	themeIndex int
+	colorblind bool
+	cbTheme    Theme // тема с поправкой для дальтоников, см. theme
+	colorbar   colorbar
+	minimap    minimap
+
+	exportScale int // индекс в exportScales
+	viewX       int // левый край области вида на экране (разделённый экран)
+
+	pins   []PinnedProbe
+	pinSeq int
+
+	view3D view3D
+
+	glow      bool
+	glowImage *ebiten.Image // размытая карта свечения, в glowDown раз мельче кадра
+	glowHalo  *ebiten.Image
+	glyphBall *ebiten.Image // заготовка значка заряда
+	bgCam     camera        // камера, при которой посчитан фон
+	bgMotion  bool          // фон устарел только от шага динамики
+	basinHeld bool          // карта бассейнов отложена до паузы или остановки
+	camMoving bool
+	dragging  bool
+	dragX     int
+	dragY     int
+
+	dashes       bool        // линии рисуются бегущими штрихами
+	dashPhase    float64     // сдвиг узора штрихов, кадры
+	fieldLineTau [][]float64 // время пролёта вдоль каждой линии
+
+	seeding    seeding // ручной посев и густота силовых линий
+	magnifier  magnifier
+	section    section
+	strip      stripChart
+	history    history
+	pause      pauseState
+	menu       popupMenu // контекстное меню заряда
+	selection  selection // рамка и групповое перетаскивание
+	clipboard  []Charge  // скопированные заряды относительно их центра
+	clearArmed int       // кадров, пока повторное C очистит сцену
+	snap       snapGrid  // привязка новых зарядов к узлам сетки
+	touch      touchInput
+	pad        gamepadInput // виртуальный курсор геймпада
+	settings   settingsPanel
+	tutorial   tutorial
+	ruler      rulerTool
+	protractor protractor
+	notes      noteTool
+	bookmarks  bookmarks
+	editor     chargeEditor
+	units      UnitMode
+	autosave   autosaver
+	stats      statsHUD
+	scenePath  string // файл для Ctrl+S / Ctrl+O
+
+	bgImage    *ebiten.Image
+	fieldCache fieldCache
+	gpuHeat    gpuHeat
+	hud        hudLayout // нижние панели, см. layoutHUD
+	bgMode     BackgroundMode
+	colormap   int // индекс палитры в colormap.All
+	heat       heatScale
+	potScale   heatScale // шкала |V| фона потенциала
+
+	arrowStyle     ArrowStyle
+	arrowScaling   ArrowScaling
+	arrowStepIndex int       // индекс в arrowGridSteps
+	bgMag          []float64 // |E| по пикселям последнего пересчёта фона
+	bgPix          []byte    // RGBA-буфер фона, см. bgPixels
+	licNoise       []float32
+
+	contours      bool
+	contourGrid   *contour.Grid
+	contourLevels []contourLevel
+	contourLabels []contourLabel
+
+	surfaceView bool // Tab: вместо поля рисуется поверхность потенциала
+	surface     potentialSurface
+	dirty       bool
+	linesStale  bool // изменились только линии: фон подождёт полного пересчёта
+	qEdit       qEdit
+
+	lastLeft  bool
+	lastRight bool
+
+	testParticle Particle
+
+	dynamics    bool
+	damping     float64
+	equilibrium bool
+	bhTheta     float64 // параметр Барнса — Хата, 0 — точная сумма
+
+	relativistic bool
+
+	flashes []flash
+
+	random randomConfig
+
+	conservation conservation
+
+	cut cutPlane
+
+	plasma plasmaCloud
+
+	wires        []Wire
+	magneticLine [][]Vec2
+
+	notice     string
+	noticeLeft int
+
+	solver      FieldSolver // nil — прямое суммирование по закону Кулона
+	solverIndex int
+
+	diagnostics     bool
+	diagCells       []diagCell
+	diagMaxCurl     float64
+	diagMaxSpurious float64
+
+	presetMenu bool
+	presetSel  int // подсвеченный пункт меню пресетов
+	help       bool
+
+	macro macroRecorder
+
+	scenario      Scenario
+	scenarioIndex int
+
+	hideHUD bool // подсказки и показания не рисуются (галерея, половины разделённого экрана)
+}
+
+func NewGame() *Game {
+	g := &Game{}
+
+	g.charges = []Charge{
+		{X: -150, Y: 0, Q: +1},
+		{X: +150, Y: 0, Q: -1},
+	}
+
+	g.damping = defaultDamping
+	g.bhTheta = defaultBHTheta
+	g.scenarioIndex = -1
+	g.random = defaultRandomConfig()
+	g.heat = defaultHeatScale()
+	g.cam = defaultCamera()
+	g.layoutHUD()
+	g.probe = true
+	g.minimap.on = true
+	g.arrowStepIndex = slices.Index(arrowGridSteps, arrowGridStep)
+	g.seeding = defaultSeeding()
+	g.snap = defaultSnap()
+	g.settings = defaultSettings()
+	g.pause.scale = slices.Index(timeScales, 1)
+	g.scenePath = defaultScenePath
+	g.themes = []Theme{darkTheme, lightTheme}
+	g.gpuHeat.on = true
+
+	macros, err := loadMacros()
+	if err != nil {
+		log.Printf("load macros: %v", err)
+	}
+	g.macro.macros = macros
+
+	if t, ok, err := loadUserTheme(); err != nil {
+		log.Printf("load theme: %v", err)
+	} else if ok {
+		g.themes = append(g.themes, t)
+		g.themeIndex = len(g.themes) - 1
+	}
+
+	g.dirty = true
+	return g
+}
+
+// Математика поля
+
+func (g *Game) fieldAt(x, y float64) (float64, float64) {
+	if g.solver != nil {
+		return g.solver.Field(x, y)
+	}
+	return directField(g.charges, x, y)
+}
+
+func directField(charges []Charge, x, y float64) (float64, float64) {
+	var Ex, Ey float64
+	for _, c := range charges {
+		dx := x - c.X
+		dy := y - c.Y
+
+		r2 := dx*dx + dy*dy
+		if r2 < minR2 {
+			r2 = minR2
+		}
+		r := math.Sqrt(r2)
+
+		factor := kConst * c.Q / (r2 * r) // k*q/r^3
+
+		Ex += factor * dx
+		Ey += factor * dy
+	}
+	return Ex, Ey
+}
+
+// fieldAt3 — поле зарядов плоскости z = 0 в точке пространства.
+func (g *Game) fieldAt3(x, y, z float64) (float64, float64, float64) {
+	var Ex, Ey, Ez float64
+	for _, c := range g.charges {
+		dx := x - c.X
+		dy := y - c.Y
+
+		r2 := dx*dx + dy*dy + z*z
+		if r2 < minR2 {
+			r2 = minR2
+		}
+		r := math.Sqrt(r2)
+
+		factor := kConst * c.Q / (r2 * r)
+
+		Ex += factor * dx
+		Ey += factor * dy
+		Ez += factor * z
+	}
+	return Ex, Ey, Ez
+}
+
+// pointPotential — потенциал заряда, согласованный со сглаживанием поля:
+// внутри r² < minR2 поле растёт линейно, как у однородного шара.
+func pointPotential(q, r2 float64) float64 {
+	if r2 >= minR2 {
+		return kConst * q / math.Sqrt(r2)
+	}
+	r0 := math.Sqrt(minR2)
+	return kConst * q / (2 * r0) * (3 - r2/minR2)
+}
+
+func (g *Game) potentialAt(x, y float64) float64 {
+	if g.solver != nil {
+		return g.solver.Potential(x, y)
+	}
+	return directPotential(g.charges, x, y)
+}
+
+func directPotential(charges []Charge, x, y float64) float64 {
+	var V float64
+	for _, c := range charges {
+		dx := x - c.X
+		dy := y - c.Y
+		V += pointPotential(c.Q, dx*dx+dy*dy)
+	}
+	return V
+}
+
+func (g *Game) traceFieldLine(tree *quadTree, startX, startY float64, dir float64) []Vec2 {
+	x := startX
+	y := startY
+
+	points := make([]Vec2, 0, 256)
+
+	for i := 0; i < fieldLineMaxLen; i++ {
+		Ex, Ey := g.sliceField(x, y)
+		E := math.Hypot(Ex, Ey)
+		if E < 1e-6 {
+			break
+		}
+
+		vx := Ex / E * dir
+		vy := Ey / E * dir
+
+		x += vx * fieldLineStep
+		y += vy * fieldLineStep
+
+		if !inWorld(x, y) {
+			break
+		}
+
+		if tree.near(x, y, seedRadius) {
+			break
+		}
+
+		points = append(points, Vec2{X: x, Y: y})
+	}
+
+	return points
+}
+
+func (g *Game) recomputeFieldLines() {
+	g.fieldLines = nil
+
+	if len(g.charges) == 0 {
+		return
+	}
+
+	charges := g.sliceCharges()
+	tree := newQuadTree(charges)
+	for _, c := range charges {
+		seeds := g.seeding.seedCount(c.Q)
+		for i := 0; i < seeds; i++ {
+			angle := 2 * math.Pi * float64(i) / float64(seeds)
+
+			sx := c.X + g.seeding.radius*math.Cos(angle)
+			sy := c.Y + g.seeding.radius*math.Sin(angle)
+
+			dir := 1.0
+			if c.Q < 0 {
+				dir = -1.0
+			}
+
+			line := g.traceFieldLine(tree, sx, sy, dir)
+			// линии храним по направлению поля, от + к −
+			if dir < 0 {
+				slices.Reverse(line)
+			}
+			if len(line) > 1 {
+				g.fieldLines = append(g.fieldLines, line)
+			}
+		}
+	}
+	for _, p := range g.seeding.seedPoints() {
+		if line := g.traceSeedLine(tree, p); len(line) > 1 {
+			g.fieldLines = append(g.fieldLines, line)
+		}
+	}
+}
+
+// parallelRows раздаёт строки 0…h-1 пулу из GOMAXPROCS горутин. row
+// вызывается одновременно для разных строк и пишет только в свою.
+func parallelRows(h int, row func(py int)) {
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for py := range rows {
+				row(py)
+			}
+		}()
+	}
+	for py := 0; py < h; py++ {
+		rows <- py
+	}
+	close(rows)
+	wg.Wait()
+}
+
+func (g *Game) recomputeBackground() {
+	w, h := g.cam.W, g.cam.H
+	if len(g.bgMag) != w*h {
+		g.bgMag = make([]float64, w*h)
+	}
+	g.updateFieldCache()
+	fc := &g.fieldCache
+	parallelRows(h, func(py int) {
+		for k := py * w; k < (py+1)*w; k++ {
+			g.bgMag[k] = math.Hypot(fc.ex[k], fc.ey[k])
+		}
+	})
+	g.heat.prepare(g.bgMag)
+
+	// тему и палитру берём заранее: theme() не для параллельных вызовов
+	invert, pal := g.theme().InvertHeat, g.palette()
+	pix := g.bgPixels()
+	parallelRows(h, func(py int) {
+		for k := py * w; k < (py+1)*w; k++ {
+			t := g.heat.at(g.bgMag[k])
+			if invert {
+				t = 1 - t
+			}
+			c := pal.At(t)
+			pix[4*k], pix[4*k+1], pix[4*k+2], pix[4*k+3] = c.R, c.G, c.B, c.A
+		}
+	})
+	g.writeBackground(pix)
+}
+
+// bgPixels — RGBA-буфер кадра фона размером с вид. Он общий для всех
+// режимов фона и переживает пересчёты, чтобы не выделять 2 МБ каждый раз.
+func (g *Game) bgPixels() []byte {
+	if n := 4 * g.cam.W * g.cam.H; len(g.bgPix) != n {
+		g.bgPix = make([]byte, n)
+	}
+	return g.bgPix
+}
+
+// writeBackground загружает кадр в bgImage одним WritePixels: попиксельный
+// Image.Set на полмиллиона точек в разы медленнее.
+func (g *Game) writeBackground(pix []byte) {
+	w, h := g.cam.W, g.cam.H
+	if g.bgImage == nil || g.bgImage.Bounds().Dx() != w || g.bgImage.Bounds().Dy() != h {
+		g.bgImage = ebiten.NewImage(w, h)
+	}
+	g.bgImage.WritePixels(pix)
+}
+
+func (g *Game) recomputeAll() {
+	start := time.Now()
+	g.recomputeLines()
+	g.stats.last.lines = time.Since(start)
+	if g.diagnostics {
+		g.recomputeDiagnostics()
+	}
+	if g.contours {
+		g.recomputeContours()
+	}
+	if g.surfaceView {
+		g.recomputeSurface()
+	}
+	if g.view3D.active {
+		g.view3D.lines = g.fieldLines3D()
+	}
+	bgStart := time.Now()
+	switch g.bgMode {
+	case BackgroundBasins:
+		g.updateBasins()
+	case BackgroundLIC:
+		g.recomputeLIC()
+	case BackgroundPotential:
+		g.recomputePotentialBackground()
+	default:
+		if g.gpuHeatActive() {
+			g.bgImage = nil // рисуется шейдером в drawBackground
+		} else {
+			g.recomputeBackground()
+		}
+	}
+	if g.glow {
+		g.recomputeGlow()
+	}
+	g.stats.last.background = time.Since(bgStart)
+	g.stats.last.total = time.Since(start)
+	g.bgCam = g.cam
+	g.bgMotion = false
+	g.magnifier.stale = true
+	g.dirty = false
+}
+
+// recomputeLines пересчитывает только линии — быстрый путь для
+// предпросмотра, пока фон и контуры остаются от прошлого полного пересчёта.
+func (g *Game) recomputeLines() {
+	if g.solver != nil {
+		g.solver.Prepare(g.charges)
+	}
+	g.recomputeFieldLines()
+	g.recomputeMagneticLines()
+	if g.dashes {
+		g.recomputeDashes()
+	}
+	g.linesStale = false
+}
+
+// Логика
+func (g *Game) addCharge(x, y, q float64) {
+	g.charges = append(g.charges, Charge{X: x, Y: y, Q: q})
+	g.dirty = true
+	g.conservation.resetSystem()
+	g.recordEdit(EditAddCharge, x, y, q)
+	if q > 0 {
+		g.tutorialDid(tutPositive, x, y)
+	} else {
+		g.tutorialDid(tutNegative, x, y)
+	}
+}
+
+// chargeAt возвращает индекс ближайшего к точке заряда в пределах
+// pickRadius или -1.
+func (g *Game) chargeAt(x, y float64) int {
+	best, bestD := -1, pickRadius/g.cam.Zoom
+	for i, c := range g.charges {
+		if d := math.Hypot(c.X-x, c.Y-y); d <= bestD {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
+
+// togglePinAtMouse закрепляет или отпускает заряд под курсором, а на
+// пустом месте ставит закреплённый щуп.
+func (g *Game) togglePinAtMouse() {
+	if i := g.hovered(); i < 0 {
+		g.pinProbeAtMouse()
+	} else {
+		g.checkpoint()
+		c := &g.charges[i]
+		c.Pinned = !c.Pinned
+		c.VX, c.VY = 0, 0
+		g.equilibrium = false
+		g.conservation.resetSystem()
+	}
+}
+
+func (g *Game) addChargeFromMouse(q float64) {
+	x, y := clampToWorld(g.snap.at(g.cursorWorld()))
+	g.checkpoint()
+	g.addCharge(x, y, q)
+}
+
+func (g *Game) spawnTestParticleAtMouse() {
+	wx, wy := g.cursorWorld()
+
+	g.testParticle = Particle{
+		X:    wx,
+		Y:    wy,
+		Live: true,
+	}
+	g.equilibrium = false
+	g.conservation.resetTest()
+	g.strip.reset()
+	g.startTrail()
+	g.tutorialDid(tutTestParticle, wx, wy)
+}
+
+func (g *Game) updateTestParticle() {
+	if !g.testParticle.Live {
+		return
+	}
+
+	p := &g.testParticle
+
+	Ex, Ey := g.fieldAt(p.X, p.Y)
+	E := math.Hypot(Ex, Ey)
+	if E < 1e-4 {
+		return
+	}
+
+	vx := Ex / E
+	vy := Ey / E
+
+	p.X += vx * testStep
+	p.Y += vy * testStep
+
+	if !inWorld(p.X, p.Y) {
+		p.Live = false
+	}
+}
+
+// Интерфейс
+
+func (g *Game) Update() error {
+	g.update(true)
+	return nil
+}
+
+// update продвигает сцену на кадр; ввод обрабатывается только при focused
+// (в разделённом экране — у половины под курсором).
+func (g *Game) update(focused bool) {
+	g.updateFlashes() // гаснут по кадрам, в паузе и без динамики тоже
+	if g.hud.scale != uiScale {
+		g.layoutHUD() // масштаб интерфейса сменили клавишей или в настройках
+	}
+	if focused && g.notes.editing {
+		g.updateNoteEntry()
+		g.step()
+		return
+	}
+	if focused && g.editor.open {
+		g.updateChargeEditor()
+		g.step()
+		return
+	}
+	if focused && g.macro.naming {
+		g.updateMacroName()
+		g.step()
+		return
+	}
+	if focused {
+		g.updateScenarioSelection()
+	}
+	if g.scenario != nil {
+		if focused {
+			g.scenario.Update()
+		}
+		return
+	}
+
+	if focused && g.updatePresetMenu() {
+		if g.dirty {
+			g.recomputeAll()
+		}
+		return
+	}
+
+	if focused {
+		g.handleInput()
+	}
+	g.step()
+}
+
+func (g *Game) handleInput() {
+	if g.updateRecovery() {
+		return
+	}
+	g.updateGamepad()
+	if g.view3D.active {
+		g.updateView3D()
+	} else {
+		g.updateCamera()
+	}
+
+	g.magnifier.on = keyPressed(keyMagnifier) && !g.cut.active && !g.surfaceView && !g.view3D.active
+
+	leftNow := mousePressed(ebiten.MouseButtonLeft)
+	rightNow := mousePressed(ebiten.MouseButtonRight)
+
+	// в срезе и на поверхности клик не соответствует точке плоскости зарядов,
+	// с зажатым пробелом или на миникарте левая кнопка двигает вид
+	if g.cut.active || g.surfaceView || g.view3D.active || panning() || g.minimap.drag {
+		leftNow, rightNow = false, false
+	}
+
+	switch {
+	case g.menu.open:
+		x, y := g.cursor()
+		g.menu.update(x, y, leftNow && !g.lastLeft || rightNow && !g.lastRight)
+	case g.updateSettings(leftNow):
+		// мышь над панелью настроек
+	case g.updateSelection(leftNow):
+		// рамка или перетаскивание группы забрали левую кнопку
+	case g.seeding.tool:
+		g.updateSeedTool(leftNow, rightNow)
+	case g.ruler.tool:
+		g.updateRuler(leftNow, rightNow)
+	case g.protractor.tool:
+		g.updateProtractor(leftNow, rightNow)
+	case g.notes.tool:
+		g.updateNotes(leftNow, rightNow)
+	case leftNow && !g.lastLeft:
+		switch {
+		case keyPressed(ebiten.KeyControl) && keyPressed(ebiten.KeyShift):
+			g.deleteChargeAtMouse()
+		case keyPressed(ebiten.KeyControl):
+			g.togglePinAtMouse()
+		case keyPressed(ebiten.KeyShift):
+			g.addChargeFromMouse(-1)
+		default:
+			g.addChargeFromMouse(+1)
+		}
+	case rightNow && !g.lastRight:
+		g.openChargeMenu()
+	}
+	if _, wy := wheel(); wy != 0 && g.selectionRotating() {
+		g.rotateSelection(wy * selectRotateStep)
+	}
+	g.updateQEdit()
+	if keyJustPressed(keyDeleteSelection) {
+		g.deleteSelection()
+	}
+	if keyJustPressed(ebiten.KeyEscape) && !g.menu.open && !g.help {
+		g.clearSelection()
+	}
+	if keyJustPressed(keyTrack) && !g.cut.active && !g.surfaceView {
+		g.cycleTrackAtMouse()
+	}
+	if keyJustPressed(keySection) && !g.cut.active && !g.surfaceView && !g.view3D.active {
+		g.sectionKey()
+	}
+
+	g.lastLeft = leftNow
+	g.lastRight = rightNow
+
+	g.updatePause()
+
+	switch {
+	case keyJustPressed(keyHelp) && keyPressed(ebiten.KeyShift):
+		g.help = false
+		g.startTutorial()
+	case keyJustPressed(keyHelp) || g.help && keyJustPressed(ebiten.KeyEscape):
+		g.help = !g.help
+	}
+	g.updateTutorial()
+
+	if keyJustPressed(keyTestParticle) {
+		switch {
+		case keyPressed(ebiten.KeyControl):
+			g.plasma.clear()
+		case keyPressed(ebiten.KeyShift):
+			x, y := g.cursorWorld()
+			g.plasma.spawn(x, y, plasmaSpawnCount)
+		case keyPressed(ebiten.KeyAlt):
+			g.strip.on = !g.strip.on
+		default:
+			g.spawnTestParticleAtMouse()
+		}
+	}
+
+	if keyJustPressed(keyRandomScene) {
+		switch {
+		case keyPressed(ebiten.KeyShift):
+			g.random.cycleCount()
+		case keyPressed(ebiten.KeyAlt):
+			g.random.Neutral = !g.random.Neutral
+		default:
+			g.generateRandomScene()
+		}
+	}
+
+	if keyJustPressed(keyWire) {
+		switch {
+		case keyPressed(ebiten.KeyControl):
+			g.checkpoint()
+			g.wires = nil
+			g.dirty = true
+		case keyPressed(ebiten.KeyShift):
+			g.addWireAtMouse(-1)
+		default:
+			g.addWireAtMouse(+1)
+		}
+	}
+
+	if keyJustPressed(keyBackground) {
+		g.bgMode = (g.bgMode + 1) % backgroundModeCount
+		g.dirty = true
+	}
+	if keyJustPressed(keyColormap) && !keyPressed(ebiten.KeyControl) {
+		g.cycleColormap()
+	}
+	if keyJustPressed(keyTheme) {
+		g.cycleTheme()
+	}
+	if keyJustPressed(keyLineStyle) {
+		switch {
+		case keyPressed(ebiten.KeyControl):
+			g.lines.Antialias = !g.lines.Antialias
+		case keyPressed(ebiten.KeyShift):
+			g.lines.cycleArrow()
+		default:
+			g.lines.cycleField()
+		}
+	}
+	if keyJustPressed(keyClearTrails) && !keyPressed(ebiten.KeyControl) {
+		g.clearTrails()
+	}
+	if keyJustPressed(keyLabels) {
+		g.labels = !g.labels
+	}
+	if keyJustPressed(keyProbe) {
+		switch {
+		case keyPressed(ebiten.KeyControl):
+			g.removePinAtMouse()
+		case keyPressed(ebiten.KeyShift):
+			g.pinProbeAtMouse()
+		default:
+			g.probe = !g.probe
+		}
+	}
+	if keyJustPressed(keyLoadScene) && keyPressed(ebiten.KeyControl) {
+		g.loadSceneKey()
+	} else if keyJustPressed(keyView3D) {
+		if keyPressed(ebiten.KeyShift) {
+			g.view3D.stereo = g.view3D.stereo.Next()
+		} else {
+			g.toggleView3D()
+		}
+	}
+	if keyJustPressed(keyUndo) && keyPressed(ebiten.KeyControl) {
+		if keyPressed(ebiten.KeyShift) {
+			g.redo()
+		} else {
+			g.undo()
+		}
+	}
+	if keyJustPressed(keyRedo) && keyPressed(ebiten.KeyControl) {
+		g.redo()
+	}
+	g.updateClipboard()
+	g.updateClearAll()
+	g.updateDrop()
+	if keyJustPressed(keyGlow) && !keyPressed(ebiten.KeyControl) {
+		g.glow = !g.glow
+		g.dirty = true
+	}
+	if keyJustPressed(keyStats) {
+		g.stats.on = !g.stats.on
+	}
+	if keyJustPressed(keyMinimap) {
+		g.minimap.on = !g.minimap.on
+	}
+	if keyJustPressed(keyGrid) {
+		switch {
+		case keyPressed(ebiten.KeyShift):
+			g.snap.on = !g.snap.on
+		case keyPressed(ebiten.KeyAlt):
+			g.snap.step = (g.snap.step + 1) % len(snapSteps)
+			g.snap.on = true
+		default:
+			g.grid = !g.grid
+		}
+	}
+	if keyJustPressed(keyDashes) {
+		g.dashes = !g.dashes
+		g.dirty = true
+	}
+	if keyJustPressed(keyArrows) {
+		switch {
+		case keyPressed(ebiten.KeyControl):
+			g.arrowStepIndex = (g.arrowStepIndex + 1) % len(arrowGridSteps)
+		case keyPressed(ebiten.KeyShift):
+			g.arrowScaling = (g.arrowScaling + 1) % arrowScalingCount
+		default:
+			g.arrowStyle = (g.arrowStyle + 1) % arrowStyleCount
+		}
+	}
+	if keyJustPressed(keyTransfer) {
+		g.cycleTransfer()
+	}
+	if keyJustPressed(keySaveScene) && keyPressed(ebiten.KeyControl) {
+		g.saveSceneKey()
+	} else if keyJustPressed(keySeedTool) {
+		if keyPressed(ebiten.KeyShift) {
+			g.clearSeeds()
+		} else {
+			on := !g.seeding.tool
+			g.toolsOff()
+			g.seeding.tool = on
+		}
+	}
+	if keyJustPressed(keyRuler) && !keyPressed(ebiten.KeyControl) {
+		if keyPressed(ebiten.KeyShift) {
+			g.ruler.marks = nil
+		} else {
+			g.toggleRulerTool()
+		}
+	}
+	if keyJustPressed(keyProtractor) && !keyPressed(ebiten.KeyControl) {
+		if keyPressed(ebiten.KeyShift) {
+			g.protractor.marks = nil
+		} else {
+			g.toggleProtractor()
+		}
+	}
+	if keyJustPressed(keyUnits) && !keyPressed(ebiten.KeyControl) {
+		g.cycleUnits()
+	}
+	if keyJustPressed(keyNote) && !keyPressed(ebiten.KeyControl) {
+		if keyPressed(ebiten.KeyShift) {
+			g.notes.items = nil
+		} else {
+			g.toggleNoteTool()
+		}
+	}
+	// с инструментом посева -/= меняют густоту линий, а не шкалу фона
+	if keyJustPressed(keyRangeUp) {
+		if g.seeding.tool {
+			g.adjustSeeding(+1)
+		} else {
+			g.heat.adjustRange(+1)
+			g.dirty = true
+		}
+	}
+	if keyJustPressed(keyRangeDown) {
+		if g.seeding.tool {
+			g.adjustSeeding(-1)
+		} else {
+			g.heat.adjustRange(-1)
+			g.dirty = true
+		}
+	}
+	if keyJustPressed(keySurface) {
+		g.surfaceView = !g.surfaceView
+		g.dirty = true
+	}
+	if keyJustPressed(keyContours) {
+		g.contours = !g.contours
+		g.dirty = true
+	}
+
+	g.updateCutPlane()
+
+	if keyJustPressed(keyExport3D) && keyPressed(ebiten.KeyControl) {
+		g.export3D()
+	}
+	if keyJustPressed(keyExportImage) && !keyPressed(ebiten.KeyControl) {
+		if keyPressed(ebiten.KeyShift) {
+			g.cycleExportScale()
+		} else {
+			g.exportImage()
+		}
+	}
+
+	if keyJustPressed(keySolver) {
+		if keyPressed(ebiten.KeyShift) {
+			g.toggleSolverBoundary()
+		} else {
+			g.cycleSolver()
+		}
+	}
+
+	if keyJustPressed(keyDiagnostics) {
+		g.diagnostics = !g.diagnostics
+		g.dirty = true
+	}
+
+	if keyJustPressed(keyDynamics) && !keyPressed(ebiten.KeyControl) {
+		g.toggleDynamics()
+	}
+	if keyJustPressed(keyRelativistic) {
+		g.toggleRelativistic()
+	}
+	if keyJustPressed(keyDamping) {
+		if keyPressed(ebiten.KeyShift) {
+			g.adjustDamping(-dampingStep)
+		} else {
+			g.adjustDamping(+dampingStep)
+		}
+	}
+
+	if keyJustPressed(keyMacroRecord) {
+		g.toggleMacroRecording()
+	}
+	if keyJustPressed(keyMacroReplay) {
+		if keyPressed(ebiten.KeyShift) {
+			g.cycleMacro()
+		} else {
+			g.replayMacroAtMouse()
+		}
+	}
+}
+
+func (g *Game) step() {
+	g.updateNotice()
+	g.updateAutosave()
+	if g.dashes {
+		g.dashPhase = math.Mod(g.dashPhase+1, dashPeriod)
+	}
+	g.releaseBasins()
+	edited := g.dirty
+	for n := g.pause.steps(); n > 0; n-- {
+		g.physicsStep()
+	}
+	g.bgMotion = g.dirty && !edited
+
+	switch {
+	case g.dirty:
+		g.recomputeAll()
+	case g.linesStale:
+		g.recomputeLines()
+	}
+	g.updateMagnifier()
+	g.updateSection()
+}
+
+func (g *Game) drawBackground(screen *ebiten.Image) {
+	screen.Fill(g.theme().Background)
+	if g.gpuHeatActive() {
+		if !g.drawGPUHeat(screen) {
+			g.dirty = true // шейдер не собрался: фон посчитает процессор
+		}
+		return
+	}
+	if g.bgImage != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM = g.cam.bgGeoM(g.bgCam)
+		screen.DrawImage(g.bgImage, op)
+	}
+}
+
+// drawScene рисует поле и заряды без элементов интерфейса. Размеры
+// в пикселях проходят через cam.px, чтобы экспорт в высоком разрешении
+// выглядел так же, как экран.
+func (g *Game) drawScene(screen *ebiten.Image) {
+	g.drawFieldLines(screen)
+
+	step := int(g.cam.px(float32(g.arrowStep())))
+	for py := step / 2; py < g.cam.H && g.arrowStyle != ArrowHidden; py += step {
+		for px := step / 2; px < g.cam.W; px += step {
+			x, y := g.cam.toWorld(float64(px), float64(py))
+
+			Ex, Ey := g.sliceField(x, y)
+			E := math.Hypot(Ex, Ey)
+			if E < 1e-3 {
+				continue
+			}
+
+			length, col := g.arrowLook(E)
+			scale := float64(g.cam.px(float32(length))) / E
+			dx := Ex * scale
+			dy := Ey * scale
+
+			x1 := float32(px)
+			y1 := float32(py)
+			x2 := float32(float64(px) + dx)
+			y2 := float32(float64(py) + dy)
+
+			vector.StrokeLine(screen, x1, y1, x2, y2, g.cam.px(g.lines.ArrowWidth()), col, g.lines.Antialias)
+			g.drawArrowhead(screen, x2, y2, math.Atan2(float64(y2-y1), float64(x2-x1)), col)
+		}
+	}
+	g.drawLineArrowheads(screen)
+
+	g.drawContours(screen)
+	g.drawTracks(screen)
+	th := g.theme()
+	for i, c := range g.sliceCharges() {
+		px, py := g.cam.toScreen(c.X, c.Y)
+		r := g.cam.px(float32(chargeGlyphRadius(c.Q)))
+		g.drawChargeGlyph(screen, px, py, r, c.Q, g.cut.chargeAlpha(g.charges[i]))
+		if c.Pinned {
+			d := r + g.cam.px(3)
+			vector.StrokeRect(screen, px-d, py-d, 2*d, 2*d, g.cam.px(2), th.HUD, false)
+		}
+	}
+	g.drawMagnetic(screen)
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	if g.scenario != nil {
+		g.scenario.Draw(screen)
+		return
+	}
+	if g.surfaceView {
+		g.drawSurface(screen)
+		return
+	}
+	if g.view3D.active {
+		g.drawView3D(screen)
+		return
+	}
+	defer g.drawHelp(screen) // поверх всего, включая подсказки
+
+	th := g.theme()
+	g.drawBackground(screen)
+	g.drawGlow(screen)
+	g.drawGrid(screen)
+	g.drawWorldBounds(screen)
+	g.drawSnapGrid(screen)
+	g.drawScene(screen)
+	g.drawChargeLabels(screen)
+	g.drawDiagnostics(screen)
+	g.drawFlashes(screen)
+	g.drawPlasma(screen)
+
+	g.drawTrails(screen)
+	g.drawPins(screen)
+	g.drawRulers(screen)
+	g.drawAngles(screen)
+	g.drawNotes(screen)
+	g.drawSelection(screen)
+	g.drawSeeds(screen)
+	g.drawSection(screen)
+	g.drawStrip(screen)
+	if g.testParticle.Live {
+		px, py := g.cam.toScreen(g.testParticle.X, g.testParticle.Y)
+		vector.DrawFilledCircle(screen, px, py, 4, th.Test, g.lines.Antialias)
+	}
+
+	g.drawMagnifier(screen)
+	g.drawStats(screen)
+	g.drawSettings(screen)
+	g.menu.draw(screen, th)
+	g.drawGamepadCursor(screen)
+	g.drawCursorTool(screen)
+	g.drawPresetMenu(screen)
+	g.drawTutorial(screen)
+	g.drawChargeEditor(screen)
+	g.drawMacroName(screen)
+	g.drawRecovery(screen)
+	if g.hideHUD || g.settings.open || g.tutorial.active {
+		return
+	}
+
+	g.drawStatusBar(screen)
+	hud := func(format string, args ...any) hintLine {
+		return hintLine{fmt.Sprintf(format, args...), th.HUD}
+	}
+
+	dyn := tr("off")
+	if g.dynamics {
+		dyn = tr("on")
+	}
+	d := keyLabel(keyDamping)
+	lines := []hintLine{hud(tr("%s: dynamics %s, %s/Shift+%s: damping = %.2f"), keyLabel(keyDynamics), dyn, d, d, g.damping)}
+	if s := g.macro.status(); s != "" {
+		lines = append(lines, hintLine{s, th.HUD})
+	}
+	switch {
+	case g.pause.paused:
+		lines = append(lines, hud(tr("Paused: tap %s to resume, %s: single step"), keyLabel(keyPan), keyLabel(keyStep)))
+	case g.pause.timeScale() != 1:
+		lines = append(lines, hud(tr("Time x%g (%s/%s)"), g.pause.timeScale(), keyLabel(keySlower), keyLabel(keyFaster)))
+	case g.dynamics && g.equilibrium:
+		lines = append(lines, hintLine{tr("Static equilibrium"), color.RGBA{0, 255, 0, 255}})
+	}
+	lines = append(lines, g.speedReadout())
+
+	r := keyLabel(keyRandomScene)
+	x := keyLabel(keyGrid)
+	t := keyLabel(keyTestParticle)
+	w := keyLabel(keyWire)
+	lines = append(lines,
+		hud(tr("%s: random scene (%s), Shift+%s: count, Alt+%s: neutral; Shift+%s: %s, Alt+%s: snap step"),
+			r, g.random, r, r, x, g.snap, x),
+		hud(tr("%s: background (%s), %s: colormap (%s), %s: equipotentials every %.0f V, Ctrl+%s: 3D export"),
+			keyLabel(keyBackground), g.bgMode, keyLabel(keyColormap), tr(g.palette().Name), keyLabel(keyContours), contourStep,
+			keyLabel(keyExport3D)),
+		hud(tr("%s: units (%s); 1 m = %d world units (%.0f px on screen), unit charge = 1 nC"),
+			keyLabel(keyUnits), g.units, pxPerMeter, pxPerMeter*g.cam.Zoom),
+		hintLine{g.cutPlaneHint(), th.HUD},
+		hud(tr("%s: field solver: %s, %s: div/curl check, %s twice: plot |E| and V along a segment"),
+			keyLabel(keySolver), g.solverStatus(), keyLabel(keyDiagnostics), keyLabel(keySection)),
+		hud(tr("Shift+%s: plasma cloud (%d particles), Ctrl+%s: clear cloud"), t, g.plasma.len(), t),
+		hud(tr("%s/Shift+%s: wire with current out of/into the screen, Ctrl+%s: remove wires"), w, w, w),
+	)
+
+	s := keyLabel(keySeedTool)
+	if !g.seeding.tool && !g.leftTool() {
+		lines = append(lines, hud(tr("%s: put charge under cursor on a ring / rod / free it, %s: seed tool for field lines"), keyLabel(keyTrack), s))
+	}
+	a := keyLabel(keyArrows)
+	l := keyLabel(keyLineStyle)
+	e := keyLabel(keyExportImage)
+	lines = append(lines,
+		hud(tr("%s: heatmap scale (%s), %s/%s: dynamic range"),
+			keyLabel(keyTransfer), g.heat, keyLabel(keyRangeDown), keyLabel(keyRangeUp)),
+		hud(tr("%s: arrows (%s), Shift+%s: %s, Ctrl+%s: every %d px, %s: flowing dashes on field lines"),
+			a, g.arrowStyle, a, g.arrowScaling, a, g.arrowStep(), keyLabel(keyDashes)),
+		hud(tr("Wheel: zoom (x%.2f), arrows/%s+drag: pan, tap: pause, %s: reset view, %s: grid, %s: probe (Shift: pin, Ctrl: unpin), %s: labels"),
+			g.cam.Zoom, keyLabel(keyPan), keyLabel(keyResetView), keyLabel(keyGrid), keyLabel(keyProbe), keyLabel(keyLabels)),
+		hud(tr("%s/Shift+%s/Ctrl+%s: %s, %s: theme (%s), %s: minimap, hold %s: magnifier"),
+			l, l, l, g.lines, keyLabel(keyTheme), tr(th.Name), keyLabel(keyMinimap), keyLabel(keyMagnifier)),
+		hud(tr("%s: export PNG at x%d resolution, Shift+%s: change, %s: split screen, %s: glow, %s: 3D view, %s: stats"),
+			e, exportScales[g.exportScale], e, keyLabel(keySplit), keyLabel(keyGlow), keyLabel(keyView3D), keyLabel(keyStats)),
+	)
+	g.drawHints(screen, lines)
+
+	g.drawConservation(screen, ui(hintX), hintRow(3))
+	switch {
+	case g.seeding.tool:
+		text.Draw(screen, fmt.Sprintf(tr("%s, %s: done, Shift+%s: clear seeds"), &g.seeding, s, s), uiFace, ui(hintX), hintRow(5), th.HUD)
+	case !g.leftTool():
+		if n := g.selectedCount(); n > 0 {
+			text.Draw(screen, fmt.Sprintf(tr("%d selected: drag moves, wheel over charge: Q, Alt+wheel: rotate, %s: delete, Ctrl+%s/%s/%s: copy/paste/duplicate"),
+				n, keyLabel(keyDeleteSelection), keyLabel(keyCopy), keyLabel(keyPaste), keyLabel(keyDuplicate)), uiFace, ui(hintX), hintRow(5), th.HUD)
+		}
+	}
+	g.drawCutPlaneInset(screen)
+	g.drawColorbar(screen)
+	g.drawPinList(screen)
+	g.drawMinimap(screen)
+	g.drawNotice(screen)
+	g.drawProbe(screen)
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+// Run открывает окно редактора и возвращает управление после его закрытия.
+// Options — настройки запуска из командной строки; нулевые значения
+// оставляют умолчания.
+type Options struct {
+	Width, Height int
+	Fullscreen    bool
+	Scene         string  // файл сцены, он же цель Ctrl+S
+	Preset        string  // имя пресета или его часть, например "dipole"
+	KConst        float64 // кулоновская константа
+	Seeds         int     // силовых линий на единицу заряда
+	Lang          string  // язык интерфейса: en или ru
+	Record        string  // файл, в который пишется ввод сеанса
+	Replay        string  // файл сеанса, ввод которого воспроизводится
+}
+
+func Run(opts Options) error {
+	fmt.Println("EBITEN STARTED")
+
+	if err := loadKeymap(); err != nil {
+		log.Printf("load keys: %v", err)
+	}
+	// запись и повтор идут в окне постоянного размера, иначе координаты
+	// мыши в записи не совпадут с раскладкой при повторе
+	session := opts.Record != "" || opts.Replay != ""
+	var prefs Prefs
+	havePrefs := false
+	if !session {
+		var err error
+		if prefs, havePrefs, err = loadPrefs(); err != nil {
+			log.Printf("load prefs: %v", err)
+		}
+	}
+	if havePrefs && prefs.Lang != "" && opts.Lang == "" {
+		if err := SetLanguage(prefs.Lang); err != nil {
+			log.Printf("%s: %v", prefsFile, err)
+		}
+	}
+	if opts.Lang != "" {
+		if err := SetLanguage(opts.Lang); err != nil {
+			return err
+		}
+	}
+	if opts.KConst > 0 {
+		kConst = opts.KConst
+	}
+
+	s := newSplitScreen()
+	g := s.left
+	if havePrefs {
+		g.applyPrefs(prefs)
+	}
+	if opts.Seeds > 0 {
+		g.seeding.perUnitQ = opts.Seeds
+	}
+	if opts.Preset != "" {
+		p, err := findPreset(opts.Preset)
+		if err != nil {
+			return err
+		}
+		g.applyPreset(p)
+	}
+	if opts.Scene != "" {
+		g.scenePath = opts.Scene
+		if err := g.loadScene(opts.Scene); err != nil {
+			return err
+		}
+	}
+	g.history = history{} // стартовая сцена — начало истории правок
+
+	w, h := defaultWidth, defaultHeight
+	if havePrefs && prefs.Width > 0 && prefs.Height > 0 {
+		w, h = prefs.Width, prefs.Height
+	}
+	if opts.Width > 0 && opts.Height > 0 {
+		w, h = opts.Width, opts.Height
+	}
+	switch {
+	case opts.Replay != "":
+		hdr, p, err := loadSession(opts.Replay)
+		if err != nil {
+			return err
+		}
+		seedRandom(hdr.Seed)
+		w, h = hdr.Width, hdr.Height
+		input.play = p
+	case opts.Record != "":
+		seed := rand.Uint64()
+		seedRandom(seed)
+		if err := startRecording(opts.Record, SessionHeader{Version: sessionVersion, Seed: seed, Width: w, Height: h}); err != nil {
+			return err
+		}
+		defer stopRecording()
+	default:
+		g.autosave.on = true
+		g.checkRecovery()
+		if !tutorialSeen() {
+			g.startTutorial()
+		}
+	}
+
+	ebiten.SetWindowSize(w, h)
+	ebiten.SetWindowSizeLimits(minWidth, minHeight, -1, -1)
+	if !session {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+	ebiten.SetFullscreen(opts.Fullscreen && !session)
+	ebiten.SetWindowTitle(tr("Point charge field"))
+
+	if err := ebiten.RunGame(s); err != nil {
+		return err
+	}
+	if g.autosave.on {
+		removeAutosave()
+	}
+	if !session {
+		if err := savePrefs(g.prefs()); err != nil {
+			log.Printf("save prefs: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Геймпад со стандартной раскладкой: левый стик водит виртуальный курсор,
+// A/B ставят положительный и отрицательный заряд, X запускает пробную
+// частицу, Y включает динамику, Start — пауза. Правый стик двигает вид,
+// курки масштабируют. Пока геймпад активен, g.cursor() возвращает его
+// курсор, поэтому все действия «под курсором» работают без изменений;
+// движение мыши возвращает управление ей.
+
+const (
+	padDeadZone    = 0.15
+	padCursorSpeed = 8.0  // пикс за кадр при полном отклонении стика
+	padPanSpeed    = 10.0 // пикс экрана за кадр
+	padZoomSpeed   = 0.03 // доля масштаба за кадр при полностью нажатом курке
+)
+
+type gamepadInput struct {
+	ids    []ebiten.GamepadID
+	id     ebiten.GamepadID
+	ok     bool // есть геймпад со стандартной раскладкой
+	active bool // курсор ведёт геймпад
+	x, y   float64
+	mx, my int // позиция мыши, при которой геймпад перехватил курсор
+}
+
+// axis — значение оси с мёртвой зоной.
+func (p *gamepadInput) axis(a ebiten.StandardGamepadAxis) float64 {
+	v := ebiten.StandardGamepadAxisValue(p.id, a)
+	if math.Abs(v) < padDeadZone {
+		return 0
+	}
+	return v
+}
+
+func (p *gamepadInput) pressed(b ebiten.StandardGamepadButton) bool {
+	return p.ok && inpututil.IsStandardGamepadButtonJustPressed(p.id, b)
+}
+
+// updateGamepad двигает виртуальный курсор и выполняет нажатия кнопок.
+func (g *Game) updateGamepad() {
+	p := &g.pad
+	p.ids = ebiten.AppendGamepadIDs(p.ids[:0])
+	p.ok = false
+	for _, id := range p.ids {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			p.id, p.ok = id, true
+			break
+		}
+	}
+	if !p.ok {
+		p.active = false
+		return
+	}
+
+	mx, my := cursorPosition()
+	if p.active && (mx != p.mx || my != p.my) {
+		p.active = false
+	}
+	dx := p.axis(ebiten.StandardGamepadAxisLeftStickHorizontal)
+	dy := p.axis(ebiten.StandardGamepadAxisLeftStickVertical)
+	if !p.active && (dx != 0 || dy != 0 || inpututil.AppendJustPressedGamepadButtons(p.id, nil) != nil) {
+		p.active, p.mx, p.my = true, mx, my
+		x, y := g.cursor()
+		p.x, p.y = float64(x), float64(y)
+	}
+	if !p.active {
+		return
+	}
+	p.x = math.Max(0, math.Min(float64(g.cam.W-1), p.x+dx*padCursorSpeed))
+	p.y = math.Max(0, math.Min(float64(g.cam.H-1), p.y+dy*padCursorSpeed))
+
+	if g.cut.active || g.surfaceView || g.view3D.active || g.menu.open {
+		return
+	}
+	switch {
+	case p.pressed(ebiten.StandardGamepadButtonRightBottom):
+		g.addChargeFromMouse(+1)
+	case p.pressed(ebiten.StandardGamepadButtonRightRight):
+		g.addChargeFromMouse(-1)
+	case p.pressed(ebiten.StandardGamepadButtonRightLeft):
+		g.spawnTestParticleAtMouse()
+	case p.pressed(ebiten.StandardGamepadButtonRightTop):
+		g.dynamics = !g.dynamics
+		g.equilibrium = false
+	case p.pressed(ebiten.StandardGamepadButtonCenterRight):
+		g.pause.paused = !g.pause.paused
+	}
+}
+
+// updateGamepadCamera — правый стик и курки; вызывается из updateCamera.
+func (g *Game) updateGamepadCamera() {
+	p := &g.pad
+	if !p.ok {
+		return
+	}
+	c := &g.cam
+	c.X += p.axis(ebiten.StandardGamepadAxisRightStickHorizontal) * padPanSpeed / c.Zoom
+	c.Y += p.axis(ebiten.StandardGamepadAxisRightStickVertical) * padPanSpeed / c.Zoom
+	in := ebiten.StandardGamepadButtonValue(p.id, ebiten.StandardGamepadButtonFrontBottomRight)
+	out := ebiten.StandardGamepadButtonValue(p.id, ebiten.StandardGamepadButtonFrontBottomLeft)
+	if z := in - out; math.Abs(z) > padDeadZone {
+		x, y := g.cursor()
+		c.zoomAt(float64(x), float64(y), 1+z*padZoomSpeed)
+	}
+}
+
+// drawGamepadCursor рисует виртуальный курсор, пока им управляет геймпад.
+func (g *Game) drawGamepadCursor(screen *ebiten.Image) {
+	if !g.pad.active || g.hideHUD {
+		return
+	}
+	col := g.theme().HUD
+	x, y := float32(g.pad.x), float32(g.pad.y)
+	r := g.cam.px(8)
+	vector.StrokeCircle(screen, x, y, r, 2, col, true)
+	vector.StrokeLine(screen, x-2*r, y, x-r, y, 2, col, true)
+	vector.StrokeLine(screen, x+r, y, x+2*r, y, 2, col, true)
+	vector.StrokeLine(screen, x, y-2*r, x, y-r, 2, col, true)
+	vector.StrokeLine(screen, x, y+r, x, y+2*r, 2, col, true)
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Свечение для скриншотов и демонстраций: области сильного поля и ореолы
+// вокруг зарядов складываются с картинкой (аддитивное смешивание). Меняет
+// видимую яркость фона, поэтому по умолчанию выключено.
+
+const (
+	glowDown      = 4    // во сколько раз карта свечения мельче кадра
+	glowBlur      = 3    // радиус размытия в пикселях карты свечения
+	glowFrom      = 0.6  // доля шкалы теплокарты, с которой поле начинает светиться
+	glowStrength  = 0.8  // яркость свечения поля
+	glowHaloR     = 40.0 // радиус ореола заряда, пикс интерфейса
+	glowHaloAlpha = 0.5
+	glowSpriteR   = 32 // радиус заготовки ореола в пикселях текстуры
+)
+
+// recomputeGlow строит размытую уменьшенную карту свечения по bgMag.
+func (g *Game) recomputeGlow() {
+	w, h := g.cam.W, g.cam.H
+	if g.bgMode == BackgroundBasins || g.bgMode == BackgroundPotential || len(g.bgMag) != w*h {
+		g.glowImage = nil
+		return
+	}
+	sw, sh := w/glowDown, h/glowDown
+
+	rgb := make([]float64, 3*sw*sh)
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			E := g.bgMag[(y*glowDown+glowDown/2)*w+x*glowDown+glowDown/2]
+			t := g.heat.at(E)
+			k := glowStrength * smoothstep(glowFrom, 1, t)
+			if k == 0 {
+				continue
+			}
+			c := g.palette().At(t)
+			i := 3 * (y*sw + x)
+			rgb[i], rgb[i+1], rgb[i+2] = k*float64(c.R), k*float64(c.G), k*float64(c.B)
+		}
+	}
+	boxBlur(rgb, sw, sh, glowBlur)
+
+	pix := make([]byte, 4*sw*sh)
+	for i := 0; i < sw*sh; i++ {
+		for ch := 0; ch < 3; ch++ {
+			pix[4*i+ch] = byte(math.Min(255, rgb[3*i+ch]))
+		}
+		pix[4*i+3] = 255
+	}
+	if g.glowImage == nil || g.glowImage.Bounds().Dx() != sw {
+		g.glowImage = ebiten.NewImage(sw, sh)
+	}
+	g.glowImage.WritePixels(pix)
+}
+
+func smoothstep(a, b, x float64) float64 {
+	t := math.Max(0, math.Min(1, (x-a)/(b-a)))
+	return t * t * (3 - 2*t)
+}
+
+// boxBlur — два прохода скользящего среднего (по строкам и по столбцам).
+func boxBlur(rgb []float64, w, h, r int) {
+	tmp := make([]float64, len(rgb))
+	pass := func(src, dst []float64, n, m, stride, step int) {
+		for j := 0; j < m; j++ {
+			for i := 0; i < n; i++ {
+				var sum [3]float64
+				cnt := 0
+				for d := max(0, i-r); d <= min(n-1, i+r); d++ {
+					k := 3 * (j*stride + d*step)
+					sum[0], sum[1], sum[2] = sum[0]+src[k], sum[1]+src[k+1], sum[2]+src[k+2]
+					cnt++
+				}
+				k := 3 * (j*stride + i*step)
+				for ch := 0; ch < 3; ch++ {
+					dst[k+ch] = sum[ch] / float64(cnt)
+				}
+			}
+		}
+	}
+	pass(rgb, tmp, w, h, w, 1)
+	pass(tmp, rgb, h, w, 1, w)
+}
+
+// glowSprite — белое пятно с гауссовым спадом для ореолов зарядов.
+func (g *Game) glowSprite() *ebiten.Image {
+	if g.glowHalo != nil {
+		return g.glowHalo
+	}
+	const n = 2 * glowSpriteR
+	pix := make([]byte, 4*n*n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			d := math.Hypot(float64(x)+0.5-glowSpriteR, float64(y)+0.5-glowSpriteR) / glowSpriteR
+			a := byte(255 * math.Exp(-4*d*d))
+			i := 4 * (y*n + x)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = a, a, a, a
+		}
+	}
+	g.glowHalo = ebiten.NewImage(n, n)
+	g.glowHalo.WritePixels(pix)
+	return g.glowHalo
+}
+
+func (g *Game) drawGlow(screen *ebiten.Image) {
+	if !g.glow {
+		return
+	}
+	if g.glowImage != nil {
+		op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter, Filter: ebiten.FilterLinear}
+		op.GeoM.Scale(glowDown, glowDown)
+		op.GeoM.Concat(g.cam.bgGeoM(g.bgCam))
+		screen.DrawImage(g.glowImage, op)
+	}
+
+	th := g.theme()
+	sprite := g.glowSprite()
+	s := float64(g.cam.px(glowHaloR)) / glowSpriteR
+	for _, c := range g.sliceCharges() {
+		x, y := g.cam.toScreen(c.X, c.Y)
+		col := th.Positive
+		if c.Q < 0 {
+			col = th.Negative
+		}
+		op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter, Filter: ebiten.FilterLinear}
+		op.GeoM.Translate(-glowSpriteR, -glowSpriteR)
+		op.GeoM.Scale(s, s)
+		op.GeoM.Translate(float64(x), float64(y))
+		op.ColorScale.ScaleWithColor(col)
+		op.ColorScale.Scale(glowHaloAlpha, glowHaloAlpha, glowHaloAlpha, glowHaloAlpha)
+		screen.DrawImage(sprite, op)
+	}
+}
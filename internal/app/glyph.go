@@ -0,0 +1,86 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Значки зарядов: объёмный шар со знаком «+» или «−» поверх. Знак
+// читается без цвета (дальтонизм, чёрно-белая печать), а площадь шара
+// пропорциональна |Q|. В режиме для дальтоников отрицательный заряд —
+// квадрат (access.go).
+
+const (
+	glyphSpriteR = 32   // радиус заготовки шара в пикселях текстуры
+	glyphMinK    = 0.6  // пределы множителя радиуса, чтобы малые заряды
+	glyphMaxK    = 2.2  // не исчезали, а большие не закрывали сцену
+	glyphStroke  = 0.22 // толщина штриха знака в долях радиуса
+)
+
+// chargeGlyphRadius — радиус значка заряда q в мировых единицах.
+func chargeGlyphRadius(q float64) float64 {
+	return chargeRadius * math.Max(glyphMinK, math.Min(glyphMaxK, math.Sqrt(math.Abs(q))))
+}
+
+// glyphSprite — белый шар с бликом сверху слева; цвет задаётся при отрисовке.
+func (g *Game) glyphSprite() *ebiten.Image {
+	if g.glyphBall != nil {
+		return g.glyphBall
+	}
+	const n = 2 * glyphSpriteR
+	pix := make([]byte, 4*n*n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			dx := (float64(x) + 0.5 - glyphSpriteR) / glyphSpriteR
+			dy := (float64(y) + 0.5 - glyphSpriteR) / glyphSpriteR
+			d := math.Hypot(dx, dy)
+			a := math.Max(0, math.Min(1, (1-d)*glyphSpriteR)) // сглаженный край
+			if a == 0 {
+				continue
+			}
+			l := 1 - 0.5*math.Min(1, math.Hypot(dx+0.35, dy+0.35)/1.2)
+			i := 4 * (y*n + x)
+			v := byte(255 * l * a)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = v, v, v, byte(255*a)
+		}
+	}
+	g.glyphBall = ebiten.NewImage(n, n)
+	g.glyphBall.WritePixels(pix)
+	return g.glyphBall
+}
+
+// drawChargeGlyph рисует значок радиуса r (пикс) с центром в (x, y).
+func (g *Game) drawChargeGlyph(dst *ebiten.Image, x, y, r float32, q float64, alpha uint8) {
+	th := g.theme()
+	col := th.Positive
+	if q < 0 {
+		col = th.Negative
+	}
+	a := float32(alpha) / 255
+
+	if q < 0 && g.colorblind {
+		drawNegativeGlyph(dst, x, y, r, col, a)
+	} else {
+		op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+		op.GeoM.Translate(-glyphSpriteR, -glyphSpriteR)
+		op.GeoM.Scale(float64(r)/glyphSpriteR, float64(r)/glyphSpriteR)
+		op.GeoM.Translate(float64(x), float64(y))
+		op.ColorScale.ScaleWithColor(col)
+		op.ColorScale.ScaleAlpha(a)
+		dst.DrawImage(g.glyphSprite(), op)
+		vector.StrokeCircle(dst, x, y, r, max(1, r/8), color.RGBA{0, 0, 0, uint8(120 * a)}, true)
+	}
+
+	if r < 4 {
+		return // знак на таком размере не читается
+	}
+	ink := color.RGBA{255, 255, 255, alpha}
+	w, h := glyphStroke*r, 0.55*r
+	vector.DrawFilledRect(dst, x-h, y-w/2, 2*h, w, ink, true)
+	if q > 0 {
+		vector.DrawFilledRect(dst, x-w/2, y-h, w, 2*h, ink, true)
+	}
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Теплокарта |E| на видеокарте: шейдер Kage получает заряды униформами
+// и считает поле в каждом пикселе каждый кадр, поэтому фон следует за
+// перетаскиванием без пересчёта и без задержки на dirty. Передаточная
+// функция та же, что у heatScale.at, палитра передаётся картинкой 256×1.
+// Гистограммной шкале нужен проход по всему кадру, а решателю Пуассона,
+// секущей плоскости и свечению — поле на процессоре, поэтому в этих
+// случаях, как и при числе зарядов больше gpuMaxCharges, фон считается
+// по-старому.
+
+const (
+	gpuMaxCharges = 128 // размер массива в шейдере
+	gpuPaletteN   = 256
+)
+
+const gpuHeatSrc = `//kage:unit pixels
+
+package main
+
+var Charges [128]vec4 // x, y, q, 0
+var Count int
+var Cam vec2
+var Center vec2
+var Zoom float
+var K float
+var MinR2 float
+var Transfer int
+var Max float
+var Decades float
+var Invert int
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	p := (dstPos.xy-Center)/Zoom + Cam
+	e := vec2(0)
+	for i := 0; i < 128; i++ {
+		if i >= Count {
+			break
+		}
+		c := Charges[i]
+		d := p - c.xy
+		r2 := max(dot(d, d), MinR2)
+		e += K * c.z / (r2 * sqrt(r2)) * d
+	}
+	E := length(e)
+
+	t := min(E/Max, 1)
+	if Transfer == 1 {
+		t = sqrt(t)
+	} else if Transfer == 2 {
+		t = clamp(1+log2(E/Max+1e-30)*0.30103/Decades, 0, 1)
+	}
+	if Invert != 0 {
+		t = 1 - t
+	}
+	return imageSrc0At(imageSrc0Origin() + vec2(t*255+0.5, 0.5))
+}
+`
+
+type gpuHeat struct {
+	on      bool
+	shader  *ebiten.Shader
+	failed  bool // шейдер не собрался, остаётся процессорный путь
+	palette *ebiten.Image
+	palIdx  int // палитра, из которой собрана palette
+	buf     []float32
+}
+
+// gpuHeatActive сообщает, рисуется ли фон шейдером.
+func (g *Game) gpuHeatActive() bool {
+	h := &g.gpuHeat
+	return h.on && !h.failed && g.bgMode == BackgroundFieldMagnitude && g.solver == nil && !g.cut.active &&
+		!g.glow && g.heat.transfer != TransferHistogram && len(g.charges) <= gpuMaxCharges
+}
+
+func (g *Game) gpuHeatShader() *ebiten.Shader {
+	h := &g.gpuHeat
+	if h.shader == nil && !h.failed {
+		s, err := ebiten.NewShader([]byte(gpuHeatSrc))
+		if err != nil {
+			log.Printf("heatmap shader: %v", err)
+			h.failed = true
+			return nil
+		}
+		h.shader = s
+	}
+	return h.shader
+}
+
+func (g *Game) gpuPalette() *ebiten.Image {
+	h := &g.gpuHeat
+	if h.palette != nil && h.palIdx == g.colormap {
+		return h.palette
+	}
+	pix := make([]byte, 4*gpuPaletteN)
+	for i := range gpuPaletteN {
+		c := g.palette().At(float64(i) / (gpuPaletteN - 1))
+		pix[4*i], pix[4*i+1], pix[4*i+2], pix[4*i+3] = c.R, c.G, c.B, 255
+	}
+	if h.palette == nil {
+		h.palette = ebiten.NewImage(gpuPaletteN, 1)
+	}
+	h.palette.WritePixels(pix)
+	h.palIdx = g.colormap
+	return h.palette
+}
+
+// drawGPUHeat рисует теплокарту шейдером; false — шейдера нет.
+func (g *Game) drawGPUHeat(screen *ebiten.Image) bool {
+	s := g.gpuHeatShader()
+	if s == nil {
+		return false
+	}
+	h := &g.gpuHeat
+	h.buf = h.buf[:0]
+	for _, c := range g.charges {
+		h.buf = append(h.buf, float32(c.X), float32(c.Y), float32(c.Q), 0)
+	}
+	h.buf = append(h.buf, make([]float32, 4*gpuMaxCharges-len(h.buf))...)
+
+	invert := 0
+	if g.theme().InvertHeat {
+		invert = 1
+	}
+	w, ht := float32(g.cam.W), float32(g.cam.H)
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, ColorA: 1},
+		{DstX: w, DstY: 0, ColorA: 1},
+		{DstX: 0, DstY: ht, ColorA: 1},
+		{DstX: w, DstY: ht, ColorA: 1},
+	}
+	op := &ebiten.DrawTrianglesShaderOptions{
+		Uniforms: map[string]any{
+			"Charges":  h.buf,
+			"Count":    len(g.charges),
+			"Cam":      []float32{float32(g.cam.X), float32(g.cam.Y)},
+			"Center":   []float32{w / 2, ht / 2},
+			"Zoom":     float32(g.cam.Zoom),
+			"K":        float32(kConst),
+			"MinR2":    float32(minR2),
+			"Transfer": int(g.heat.transfer),
+			"Max":      float32(g.heat.max),
+			"Decades":  float32(g.heat.decades),
+			"Invert":   invert,
+		},
+	}
+	op.Images[0] = g.gpuPalette()
+	screen.DrawTrianglesShader(vs, []uint16{0, 1, 2, 1, 3, 2}, s, op)
+	return true
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Координатная сетка и оси под силовыми линиями. Шаг сетки выбирается из
+// ряда 1, 2, 5 · 10^k так, чтобы на экране линии шли примерно через gridTarget.
+
+const gridTarget = 80.0 // желаемый шаг сетки, пикс экрана
+
+// niceStep округляет шаг вверх до 1, 2 или 5 на степень десяти.
+func niceStep(raw float64) float64 {
+	p := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if m*p >= raw {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+func formatTick(v, step float64) string {
+	if step >= 1 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	digits := int(math.Ceil(-math.Log10(step)))
+	return fmt.Sprintf("%.*f", digits, v)
+}
+
+func (g *Game) drawGrid(screen *ebiten.Image) {
+	if !g.grid {
+		return
+	}
+	step := niceStep(gridTarget / g.cam.Zoom)
+	x0, y0 := g.cam.toWorld(0, 0)
+	x1, y1 := g.cam.toWorld(float64(g.cam.W), float64(g.cam.H))
+
+	minor := withAlpha(g.theme().HUD, 40)
+	axis := withAlpha(g.theme().HUD, 150)
+	face := uiFace
+
+	// подписи идут вдоль осей, а если ось за экраном — вдоль ближнего края
+	w, h := float32(g.cam.W), float32(g.cam.H)
+	ax, ay := g.cam.toScreen(0, 0)
+	labelY := min(max(ay, float32(lineHeight())), h-uif(4))
+	labelX := min(max(ax, 2), w-uif(50))
+
+	for x := math.Ceil(x0/step) * step; x <= x1; x += step {
+		sx, _ := g.cam.toScreen(x, 0)
+		col := minor
+		if math.Abs(x) < step/2 {
+			col = axis
+		}
+		vector.StrokeLine(screen, sx, 0, sx, h, 1, col, false)
+		if math.Abs(x) >= step/2 {
+			text.Draw(screen, formatTick(x, step), face, int(sx)+ui(2), int(labelY)-ui(2), g.theme().HUD)
+		}
+	}
+	for y := math.Ceil(y0/step) * step; y <= y1; y += step {
+		_, sy := g.cam.toScreen(0, y)
+		col := minor
+		if math.Abs(y) < step/2 {
+			col = axis
+		}
+		vector.StrokeLine(screen, 0, sy, w, sy, 1, col, false)
+		if math.Abs(y) >= step/2 {
+			text.Draw(screen, formatTick(y, step), face, int(labelX)+ui(3), int(sy)-ui(2), g.theme().HUD)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"electric-field/internal/colormap"
+)
+
+// Тепловая карта фона: модуль поля проходит через передаточную функцию
+// в [0, 1] и окрашивается выбранной палитрой.
+
+type TransferFunc int
+
+const (
+	TransferLinear TransferFunc = iota
+	TransferSqrt
+	TransferLog
+	TransferHistogram // выравнивание гистограммы по текущему кадру
+	transferCount
+)
+
+func (t TransferFunc) String() string {
+	switch t {
+	case TransferSqrt:
+		return tr("sqrt")
+	case TransferLog:
+		return tr("log")
+	case TransferHistogram:
+		return tr("histogram")
+	default:
+		return tr("linear")
+	}
+}
+
+const (
+	heatRangeStep  = 1.5
+	heatMinDecades = 1.0
+	heatMaxDecades = 8.0
+	heatHistBins   = 4096
+)
+
+type heatScale struct {
+	transfer TransferFunc
+	max      float64 // |E|, при котором достигается верх шкалы
+	decades  float64 // динамический диапазон логарифмической шкалы
+
+	// гистограмма log|E|: cdf[i] — доля пикселей не ярче бина i
+	cdf    []float64
+	lo, hi float64
+}
+
+func defaultHeatScale() heatScale {
+	return heatScale{max: 1 / bgScale, decades: 3}
+}
+
+// prepare строит распределение для выравнивания гистограммы.
+func (h *heatScale) prepare(mags []float64) {
+	if h.transfer != TransferHistogram || len(mags) == 0 {
+		return
+	}
+	h.lo, h.hi = math.Inf(1), math.Inf(-1)
+	for _, E := range mags {
+		l := math.Log10(E + 1e-12)
+		h.lo, h.hi = min(h.lo, l), max(h.hi, l)
+	}
+	if h.hi <= h.lo {
+		h.hi = h.lo + 1
+	}
+
+	h.cdf = make([]float64, heatHistBins)
+	for _, E := range mags {
+		h.cdf[h.bin(E)]++
+	}
+	sum := 0.0
+	for i, c := range h.cdf {
+		sum += c
+		h.cdf[i] = sum / float64(len(mags))
+	}
+}
+
+func (h *heatScale) bin(E float64) int {
+	t := (math.Log10(E+1e-12) - h.lo) / (h.hi - h.lo)
+	return max(0, min(heatHistBins-1, int(t*heatHistBins)))
+}
+
+// at — передаточная функция |E| → [0, 1].
+func (h *heatScale) at(E float64) float64 {
+	switch h.transfer {
+	case TransferSqrt:
+		return math.Sqrt(min(E/h.max, 1))
+	case TransferLog:
+		return max(0, min(1, 1+math.Log10(E/h.max+1e-300)/h.decades))
+	case TransferHistogram:
+		if h.cdf == nil {
+			return 0
+		}
+		return h.cdf[h.bin(E)]
+	default:
+		return min(E/h.max, 1)
+	}
+}
+
+// adjustRange расширяет (dir > 0) или сужает динамический диапазон.
+func (h *heatScale) adjustRange(dir int) {
+	switch h.transfer {
+	case TransferLog:
+		h.decades = max(heatMinDecades, min(heatMaxDecades, h.decades+float64(dir)*0.5))
+	case TransferHistogram:
+	default:
+		h.max *= math.Pow(heatRangeStep, float64(dir))
+	}
+}
+
+func (h heatScale) String() string {
+	switch h.transfer {
+	case TransferLog:
+		return fmt.Sprintf(tr("log, %.1f decades below %.0f"), h.decades, h.max)
+	case TransferHistogram:
+		return tr("histogram-equalized")
+	default:
+		return fmt.Sprintf(tr("%s, saturates at |E| = %.1f"), h.transfer, h.max)
+	}
+}
+
+func (g *Game) palette() *colormap.Map {
+	return colormap.All[g.colormap]
+}
+
+func (g *Game) cycleColormap() {
+	g.colormap = (g.colormap + 1) % len(colormap.All)
+	g.dirty = true
+}
+
+func (g *Game) cycleTransfer() {
+	g.heat.transfer = (g.heat.transfer + 1) % transferCount
+	g.dirty = true
+}
+
+func (g *Game) heatColor(E float64) color.RGBA {
+	t := g.heat.at(E)
+	if g.theme().InvertHeat {
+		t = 1 - t
+	}
+	return g.palette().At(t)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Справка по клавишам: таблица в две колонки по keymap, поэтому
+// переопределённые в keys.json клавиши показываются сразу.
+
+const (
+	helpLineH  = 15
+	helpColW   = 430
+	helpKeyCol = 90 // ширина столбца с клавишей
+)
+
+func (g *Game) drawHelp(screen *ebiten.Image) {
+	if !g.help {
+		return
+	}
+	th := g.theme()
+	face := uiFace
+
+	rows := (len(keymap) + 1) / 2
+	lh, colW, pad := ui(helpLineH), ui(helpColW), ui(10)
+	w := float32(2*colW + 2*pad)
+	h := float32(lh*(rows+5) + pad)
+	x := float32(g.cam.W)/2 - w/2
+	y := float32(screenHeight)/2 - h/2
+	vector.DrawFilledRect(screen, x, y, w, h, withAlpha(th.Panel, 240), false)
+	vector.StrokeRect(screen, x, y, w, h, 1, th.Border, false)
+
+	tx, ty := int(x)+pad, int(y)+lh+ui(4)
+	text.Draw(screen, fmt.Sprintf(tr("Keys (%s or Esc closes)"), keyLabel(keyHelp)), face, tx, ty, th.HUD)
+	ty += lh
+	text.Draw(screen, tr("Mouse: click + charge, Shift+click - charge, Ctrl+click pin, Ctrl+Shift+click delete, right click menu, Alt+drag select"), face, tx, ty, th.HUD)
+	ty += lh
+	text.Draw(screen, tr("Drag a charge: move it, double-click a charge: exact X, Y and Q"), face, tx, ty, th.HUD)
+	ty += lh
+	text.Draw(screen, tr("Ctrl+1…9: go to a saved view, Ctrl+Shift+1…9: save the current view"), face, tx, ty, th.HUD)
+	for i, b := range keymap {
+		cx := tx + (i/rows)*colW
+		cy := ty + lh*(i%rows+1)
+		text.Draw(screen, keyLabel(*b.key), face, cx, cy, th.Positive)
+		text.Draw(screen, tr(b.help), face, cx+ui(helpKeyCol), cy, th.HUD)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Подсказки слева. Сверху вниз идут строки состояния и справка по
+// клавишам, снизу вверх от строки состояния — div/curl, сводка сохранения,
+// заметка и выделение. Шаг строк берётся из высоты шрифта, а верхний
+// столбец кончается там, где начинается нижний: в маленьком окне хвост
+// справки заменяется отсылкой к полной справке.
+
+const (
+	hintX      = 10
+	hintTop    = 60 // базовая линия первой верхней строки при масштабе 1
+	hintBottom = 6  // строк снизу: div/curl, сохранение (3), заметка, выделение
+)
+
+type hintLine struct {
+	s   string
+	col color.Color
+}
+
+func hintLineH() int { return lineHeight() + ui(6) }
+
+// hintRow — базовая линия k-й строки снизу, считая от строки состояния.
+func hintRow(k int) int { return screenHeight - statusBarH() - ui(10) - k*hintLineH() }
+
+func (g *Game) drawHints(screen *ebiten.Image, lines []hintLine) {
+	lh, top := hintLineH(), ui(hintTop)
+	n := max(0, (hintRow(hintBottom)-top)/lh+1)
+	if len(lines) > n && n > 0 {
+		more := fmt.Sprintf(tr("… %d more, %s: all keys"), len(lines)-n+1, keyLabel(keyHelp))
+		lines = append(lines[:n-1:n-1], hintLine{more, g.theme().HUD})
+	}
+	for i, l := range lines[:min(n, len(lines))] {
+		text.Draw(screen, l.s, uiFace, ui(hintX), top+i*lh, l.col)
+	}
+}
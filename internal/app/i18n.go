@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/opentype"
+)
+
+// Локализация интерфейса. Ключ каталога — английский текст строки (для
+// форматных строк — сам формат со всеми %-глаголами), поэтому английский
+// язык каталога не требует, а непереведённая строка показывается
+// по-английски. Язык выбирается до запуска окна флагом -lang.
+//
+// Весь текст интерфейса рисуется одним шрифтом uiFace — векторным Go Mono.
+// Прежний растровый basicfont 7×13 содержал только ASCII, а в Go Mono есть
+// кириллица, поэтому отдельный шрифт для русских строк не нужен. При
+// масштабе 1 ширина символа, как и у растрового шрифта, 7 пикс.
+
+type Language int
+
+const (
+	LangEnglish Language = iota
+	LangRussian
+)
+
+var languageCodes = []string{"en", "ru"}
+
+var lang = LangEnglish
+
+var catalogs = map[Language]map[string]string{
+	LangRussian: catalogRu,
+}
+
+// SetLanguage выбирает язык интерфейса по коду "en" или "ru".
+func SetLanguage(code string) error {
+	for i, c := range languageCodes {
+		if strings.EqualFold(code, c) {
+			lang = Language(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown language %q (want %s)", code, strings.Join(languageCodes, ", "))
+}
+
+// tr возвращает строку интерфейса на текущем языке.
+func tr(s string) string {
+	if t, ok := catalogs[lang][s]; ok {
+		return t
+	}
+	return s
+}
+
+const uiFontSize = 7 / 0.6 // кегль при масштабе 1: символ Go Mono шириной 0.6 кегля — 7 пикс
+
+var uiFace = newUIFace(uiFontSize)
+
+func newUIFace(size float64) font.Face {
+	f, err := opentype.Parse(gomono.TTF)
+	if err != nil {
+		log.Fatalf("parse font: %v", err)
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		log.Fatalf("font face: %v", err)
+	}
+	return face
+}
+
+// textWidth — ширина строки в пикселях, с учётом многобайтовых символов.
+func textWidth(s string) int {
+	return font.MeasureString(uiFace, s).Ceil()
+}
+
+// lineHeight — высота строки текста по метрикам шрифта.
+func lineHeight() int {
+	return uiFace.Metrics().Height.Ceil()
+}
+
+// baseline — базовая линия текста, отцентрованного по высоте в полосе
+// от top высотой h.
+func baseline(top, h int) int {
+	m := uiFace.Metrics()
+	return top + (h+m.Ascent.Ceil()-m.Descent.Ceil())/2
+}
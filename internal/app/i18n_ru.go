@@ -0,0 +1,326 @@
+package app
+
+// Русский каталог строк интерфейса. Форматные глаголы должны идти в том же
+// порядке, что и в английском ключе. Подсказки стоит держать не длиннее
+// английских на несколько символов, иначе они не помещаются в окно.
+
+var catalogRu = map[string]string{
+	"Point charge field":      "Поле точечных зарядов",
+	"Gallery":                 "Галерея",
+	"Performance measurement": "Замер производительности",
+
+	// подсказки редактора
+	"Tool: %s | charges %d | particles %d | %s | time x%g | zoom x%.2f":                                                              "Инструмент: %s | зарядов %d | частиц %d | %s | время x%g | масштаб x%.2f",
+	"Ctrl+%s/%s: undo/redo, Ctrl+%s/%s: save/open %s":                                                                                "Ctrl+%s/%s: отменить/вернуть, Ctrl+%s/%s: сохранить/открыть %s",
+	"Click: + charge, Shift+click: - charge, Ctrl+click: pin, right click: charge menu, Alt+drag: select, %s: test charge, %s: keys": "Клик: + заряд, Shift+клик: - заряд, Ctrl+клик: закрепить, правый клик: меню заряда, Alt+протяжка: выделение, %s: пробный заряд, %s: клавиши",
+	"Shift+click: - charge": "Shift+клик: - заряд",
+	"Ctrl+click: pin a probe on empty space, pin or release the charge under the cursor": "Ctrl+клик: щуп на пустом месте, закрепить или отпустить заряд под курсором",
+	"Ctrl+Shift+click: delete the charge under the cursor":                               "Ctrl+Shift+клик: удалить заряд под курсором",
+	"Release to drop": "Отпустите, чтобы поставить",
+	"Drag: move, double-click: exact X, Y and Q, wheel: Q, right click: charge menu": "Протяжка: двигать, двойной щелчок: точные X, Y и Q, колесо: Q, правый клик: меню заряда",
+	"Alt+drag: select charges in a box, Shift: add to the selection":                 "Alt+протяжка: выделить заряды рамкой, Shift: добавить к выделению",
+	"add + charge": "+ заряд",
+	"add - charge": "- заряд",
+	"pin":          "закрепление",
+	"delete":       "удаление",
+	"seed lines":   "затравки линий",
+	"ruler":        "линейка",
+	"angle":        "угломер",
+	"notes":        "заметки",
+	"move":         "перемещение",
+	"select":       "выделение",
+	"static":       "статика",
+	"paused":       "пауза",
+	"running":      "идёт",
+	"%s/Shift+%s: wire with current out of/into the screen, Ctrl+%s: remove wires": "%s/Shift+%s: провод с током из экрана/в экран, Ctrl+%s: убрать провода",
+	"Shift+%s: plasma cloud (%d particles), Ctrl+%s: clear cloud":                  "Shift+%s: облако плазмы (%d частиц), Ctrl+%s: убрать облако",
+	"off": "выкл",
+	"on":  "вкл",
+	"%s: dynamics %s, %s/Shift+%s: damping = %.2f":                                                                                    "%s: динамика %s, %s/Shift+%s: затухание = %.2f",
+	"%s: random scene (%s), Shift+%s: count, Alt+%s: neutral; Shift+%s: %s, Alt+%s: snap step":                                        "%s: случайная сцена (%s), Shift+%s: число, Alt+%s: нейтр.; Shift+%s: %s, Alt+%s: шаг привязки",
+	"%s: background (%s), %s: colormap (%s), %s: equipotentials every %.0f V, Ctrl+%s: 3D export":                                     "%s: фон (%s), %s: палитра (%s), %s: эквипотенциали через %.0f В, Ctrl+%s: экспорт 3D",
+	"Seed tool: click seeds a line, drag seeds along a segment, right click removes, %s/%s: density, Shift: radius":                   "Посев: клик - линия, протяжка - линии вдоль отрезка, правый клик убирает, %s/%s: густота, Shift: радиус",
+	"%s, %s: done, Shift+%s: clear seeds":                                                                                             "%s, %s: готово, Shift+%s: убрать посев",
+	"%s: put charge under cursor on a ring / rod / free it, %s: seed tool for field lines":                                            "%s: заряд под курсором на кольцо / стержень / освободить, %s: посев силовых линий",
+	"%d selected: drag moves, wheel over charge: Q, Alt+wheel: rotate, %s: delete, Ctrl+%s/%s/%s: copy/paste/duplicate":               "Выделено %d: протяжка двигает, колесо над зарядом: Q, Alt+колесо: поворот, %s: удалить, Ctrl+%s/%s/%s: копия/вставка/дубль",
+	"%s: heatmap scale (%s), %s/%s: dynamic range":                                                                                    "%s: шкала фона (%s), %s/%s: диапазон",
+	"%s: arrows (%s), Shift+%s: %s, Ctrl+%s: every %d px, %s: flowing dashes on field lines":                                          "%s: стрелки (%s), Shift+%s: %s, Ctrl+%s: через %d пикс, %s: бегущие штрихи на линиях",
+	"%s/Shift+%s/Ctrl+%s: %s, %s: theme (%s), %s: minimap, hold %s: magnifier":                                                        "%s/Shift+%s/Ctrl+%s: %s, %s: тема (%s), %s: миникарта, держать %s: лупа",
+	"Wheel: zoom (x%.2f), arrows/%s+drag: pan, tap: pause, %s: reset view, %s: grid, %s: probe (Shift: pin, Ctrl: unpin), %s: labels": "Колесо: масштаб (x%.2f), стрелки/%s+протяжка: сдвиг, нажатие: пауза, %s: сброс вида, %s: сетка, %s: щуп (Shift/Ctrl), %s: подписи",
+	"%s: export PNG at x%d resolution, Shift+%s: change, %s: split screen, %s: glow, %s: 3D view, %s: stats":                          "%s: PNG в разрешении x%d, Shift+%s: сменить, %s: две сцены, %s: свечение, %s: 3D, %s: статистика",
+	"%s: field solver: %s, %s: div/curl check, %s twice: plot |E| and V along a segment":                                              "%s: расчёт поля: %s, %s: проверка div/rot, %s дважды: |E| и V вдоль отрезка",
+	"Paused: tap %s to resume, %s: single step":                                                                                       "Пауза: нажмите %s, чтобы продолжить, %s: один шаг",
+	"Time x%g (%s/%s)":        "Время x%g (%s/%s)",
+	"Static equilibrium":      "Статическое равновесие",
+	"… %d more, %s: all keys": "… ещё %d, %s: все клавиши",
+	"Keys (%s or Esc closes)": "Клавиши (%s или Esc закрывает)",
+	"Mouse: click + charge, Shift+click - charge, Ctrl+click pin, Ctrl+Shift+click delete, right click menu, Alt+drag select": "Мышь: клик + заряд, Shift+клик - заряд, Ctrl+клик закрепить, Ctrl+Shift+клик удалить, правый клик меню, Alt+протяжка выделяет",
+	"Presets: number, arrows+Enter or click; Esc closes":                                                                      "Заготовки: цифра, стрелки+Enter или клик; Esc закрывает",
+	"%d charges, %s: single view": "%d зарядов, %s: одна сцена",
+
+	// режимы и состояния
+	"colored by |E|":               "цвет по |E|",
+	"hidden":                       "скрыты",
+	"plain":                        "простые",
+	"length ~ |E|":                 "длина ~ |E|",
+	"length ~ log|E|":              "длина ~ log|E|",
+	"direction only":               "только направление",
+	"basins of attraction":         "бассейны притяжения",
+	"line integral convolution":    "свёртка вдоль линий (LIC)",
+	"signed potential V":           "потенциал V со знаком",
+	"sqrt":                         "корень",
+	"log":                          "логарифм",
+	"histogram":                    "гистограмма",
+	"linear":                       "линейная",
+	"log, %.1f decades below %.0f": "логарифм, %.1f порядка ниже %.0f",
+	"histogram-equalized":          "выравнивание гистограммы",
+	"%s, saturates at |E| = %.1f":  "%s, насыщение при |E| = %.1f",
+	"lines %.1f px, arrows %.1f px, antialiasing %s": "линии %.1f пикс, стрелки %.1f пикс, сглаживание %s",
+	"any net charge":            "любой суммарный заряд",
+	"neutral":                   "нейтральная",
+	"N = %d, |Q| in %d..%d, %s": "N = %d, |Q| от %d до %d, %s",
+	"%d lines per unit charge from r = %.0f, %d manual seeds": "%d линий на единицу заряда от r = %.0f, %d ручных затравок",
+	"snap off":                              "привязка выкл",
+	"snap %g":                               "привязка %g",
+	"direct Coulomb superposition":          "прямая сумма по Кулону",
+	"Fast multipole method":                 "Быстрый метод мультиполей",
+	"order %d, %d charges, %d tree levels":  "порядок %d, зарядов %d, уровней дерева %d",
+	"Poisson grid (multigrid)":              "сетка Пуассона (многосеточный)",
+	"%d×%d, %s, %d V-cycles, residual %.1e": "%d×%d, %s, %d V-циклов, невязка %.1e",
+	"anaglyph (red-cyan)":                   "анаглиф (красный-голубой)",
+	"side-by-side":                          "стереопара рядом",
+	"mono":                                  "моно",
+	"dark":                                  "тёмная",
+	"light":                                 "светлая",
+	"custom":                                "своя",
+	"gray":                                  "серая",
+	"distance":                              "расстояние",
+
+	// панели и режимы просмотра
+	": 3D cut plane": ": трёхмерный срез",
+	"%s cut plane: tilt %.0f deg (%s/%s), offset %.0f (%s/%s), editing disabled":     "%s срез: наклон %.0f град (%s/%s), сдвиг %.0f (%s/%s), правка отключена",
+	"%s diagnostics: max curl %.1e, max div away from charges %.1e (tolerance %.0e)": "%s проверка: max rot %.1e, max div вдали от зарядов %.1e (допуск %.0e)",
+	"Newtonian":             "ньютоновская",
+	"relativistic":          "релятивистская",
+	"%s: test particle %s":  "%s: пробная частица %s",
+	" (faster than light!)": " (быстрее света!)",
+	"KE = %.2f, PE = %.2f, E = %.2f (drift %.2f%%)":                         "Eк = %.2f, Eп = %.2f, E = %.2f (уход %.2f%%)",
+	" (pinned charges absorb momentum)":                                     " (закреплённые заряды забирают импульс)",
+	", annihilated %d pairs: mass %.2f, P = (%.3f, %.3f)":                   ", аннигилировало пар: %d, масса %.2f, P = (%.3f, %.3f)",
+	"P = (%.3f, %.3f), |dP| = %.3f%s":                                       "P = (%.3f, %.3f), |dP| = %.3f%s",
+	"Test particle E = %.2f (drift %.2f%%)":                                 "Пробная частица E = %.2f (уход %.2f%%)",
+	"damping > 0: energy is dissipated":                                     "затухание > 0: энергия рассеивается",
+	"Section A-B, %s: new, Shift+%s: close":                                 "Разрез A-B, %s: новый, Shift+%s: закрыть",
+	"Test particle":                                                         "Пробная частица",
+	"Needs %s: dynamics":                                                    "Нужна динамика: %s",
+	"Potential surface V(x, y), clamped to +/-%.0f. %s: back to field view": "Рельеф потенциала V(x, y), обрезан до +/-%.0f. %s: назад к полю",
+	"3D view: %d field lines. Drag or arrows: orbit, wheel: distance, %s: reset, Shift+%s: stereo (%s), %s: back": "3D: %d силовых линий. Протяжка или стрелки: облёт, колесо: дальность, %s: сброс, Shift+%s: стерео (%s), %s: назад",
+	"charges %d  wires %d  plasma %d": "зарядов %d  проводов %d  плазмы %d",
+	"field lines %d  points %d":       "линий %d  точек %d",
+	"recompute %.1f ms":               "пересчёт %.1f мс",
+	"  lines %.1f ms  bg %.1f ms":     "  линии %.1f мс  фон %.1f мс",
+	"heap %.1f MB  sys %.1f MB":       "куча %.1f МБ  всего %.1f МБ",
+	"GC runs %d  goroutines %d":       "сборок %d  горутин %d",
+
+	// макросы и меню заряда
+	"%s: stop recording (%d actions)":                       "%s: остановить запись (%d действий)",
+	": record macro":                                        ": записать макрос",
+	"%s: record macro, %s: replay %s, Shift+%s: next macro": "%s: записать макрос, %s: повторить %s, Shift+%s: следующий",
+	"Charge %s":                 "Заряд %s",
+	"Flip sign":                 "Сменить знак",
+	"Save macro (%d actions)":   "Сохранить макрос (правок: %d)",
+	"Name":                      "Имя",
+	"Save":                      "Сохранить",
+	"Enter: save, Esc: discard": "Enter: сохранить, Esc: отбросить",
+	"Pin":                       "Закрепить",
+	"Unpin":                     "Открепить",
+	"Duplicate":                 "Дублировать",
+	"Delete":                    "Удалить",
+
+	// сообщения
+	"Copied %d charge(s)":                              "Скопировано зарядов: %d",
+	"Image export failed: ":                            "Не удалось сохранить картинку: ",
+	"Exported %dx%d image to %s":                       "Картинка %dx%d сохранена в %s",
+	"OBJ export failed: ":                              "Не удалось сохранить OBJ: ",
+	"glTF export failed: ":                             "Не удалось сохранить glTF: ",
+	"Exported %d 3D field lines to %s.obj and %s.gltf": "%d силовых линий сохранены в %s.obj и %s.gltf",
+	"Save failed: ":                                    "Не удалось сохранить: ",
+	"Saved %d charges to %s":                           "Сохранено зарядов: %d, файл %s",
+	"Load failed: ":                                    "Не удалось открыть: ",
+	"Loaded %d charges from %s":                        "Загружено зарядов: %d из %s",
+	"Nothing to undo":                                  "Нечего отменять",
+	"Nothing to redo":                                  "Нечего повторять",
+
+	// заготовки
+	"Single charge":                      "Одиночный заряд",
+	"Point dipole":                       "Точечный диполь",
+	"Two like charges":                   "Два одноимённых заряда",
+	"Linear quadrupole":                  "Линейный квадруполь",
+	"Square quadrupole":                  "Квадратный квадруполь",
+	"Parallel-plate capacitor":           "Плоский конденсатор",
+	"Line of charge":                     "Заряженная линия",
+	"Line of alternating charges":        "Линия чередующихся зарядов",
+	"Ring of charges (Faraday cage)":     "Кольцо зарядов (клетка Фарадея)",
+	"Bead on a ring near a fixed charge": "Бусина на кольце у неподвижного заряда",
+
+	// справка по клавишам
+	"test charge (Shift: plasma, Alt: chart)":       "пробный заряд (Shift: плазма, Alt: график)",
+	"random scene (Shift: count, Alt: neutral)":     "случайная сцена (Shift: число, Alt: нейтр.)",
+	"wire out of screen (Shift: into, Ctrl: clear)": "провод из экрана (Shift: в экран, Ctrl: убрать)",
+	"cycle background mode":                         "режим фона",
+	"cycle colormap":                                "палитра",
+	"equipotential contours":                        "эквипотенциали",
+	"heatmap transfer function":                     "шкала теплокарты",
+	"arrows (Shift: scaling, Ctrl: density)":        "стрелки (Shift: длина, Ctrl: шаг)",
+	"flowing dashes on field lines":                 "бегущие штрихи на линиях",
+	"narrow heatmap range / fewer seeds":            "уже диапазон / реже линии",
+	"widen heatmap range / more seeds":              "шире диапазон / гуще линии",
+	"potential surface view":                        "рельеф потенциала",
+	"hold and drag to pan, tap to pause":            "держать и тянуть - сдвиг, нажатие - пауза",
+	"one physics step while paused":                 "один шаг физики на паузе",
+	"slower simulation (down to 0.1x)":              "медленнее (до 0.1x)",
+	"faster simulation (up to 10x)":                 "быстрее (до 10x)",
+	"reset view":                                    "сброс вида",
+	"grid (Shift: snap, Alt: snap step)":            "сетка (Shift: привязка, Alt: шаг)",
+	"probe (Shift: pin, Ctrl: unpin)":               "щуп (Shift: закрепить, Ctrl: снять)",
+	"charge labels":                                 "подписи зарядов",
+	"clear trails":                                  "стереть следы",
+	"line width (Shift: arrows, Ctrl: AA)":          "толщина линий (Shift: стрелок, Ctrl: AA)",
+	"color theme":                                   "цветовая тема",
+	"minimap":                                       "миникарта",
+	"split screen":                                  "две сцены рядом",
+	"glow":                                          "свечение",
+	"3D view (Shift: stereo)":                       "3D (Shift: стерео)",
+	"seed tool (Shift: clear seeds)":                "посев линий (Shift: убрать)",
+	"hold for magnifier":                            "держать - лупа",
+	"section plot (Shift: close)":                   "разрез (Shift: закрыть)",
+	"with Ctrl: undo (Shift: redo)":                 "с Ctrl: отмена (Shift: повтор)",
+	"with Ctrl: redo":                               "с Ctrl: повтор",
+	"with Ctrl: save scene":                         "с Ctrl: сохранить сцену",
+	"with Ctrl: open scene":                         "с Ctrl: открыть сцену",
+	"with Ctrl: 3D export":                          "с Ctrl: экспорт 3D",
+	"export PNG (Shift: resolution)":                "сохранить PNG (Shift: разрешение)",
+	"dynamics":                                      "динамика",
+	"more damping (Shift: less)":                    "сильнее затухание (Shift: слабее)",
+	"preset menu":                                   "меню заготовок",
+	"ring / rod track for charge":                   "кольцо / стержень для заряда",
+	"this help (Shift: tutorial)":                   "эта справка (Shift: обучение)",
+	"scenarios":                                     "сценарии",
+	"relativistic test particle":                    "релятивистская частица",
+	"field solver (Shift: boundary)":                "расчёт поля (Shift: граница)",
+	"div/curl check":                                "проверка div/rot",
+	"cut plane":                                     "срез",
+	"tilt cut plane up":                             "наклон среза вверх",
+	"tilt cut plane down":                           "наклон среза вниз",
+	"move cut plane forward":                        "срез вперёд",
+	"move cut plane back":                           "срез назад",
+	"record macro":                                  "запись макроса",
+	"replay macro (Shift: next)":                    "повтор макроса (Shift: следующий)",
+	"FPS and performance statistics":                "FPS и статистика",
+	"delete selected charges":                       "удалить выделенные",
+	"with Ctrl: copy selection":                     "с Ctrl: копировать",
+	"with Ctrl: paste at cursor":                    "с Ctrl: вставить у курсора",
+	"with Ctrl: duplicate selection":                "с Ctrl: дублировать",
+	"settings panel":                                "панель настроек",
+	"fullscreen":                                    "полный экран",
+	"Restored %d charges, Ctrl+%s: undo":            "Восстановлено зарядов: %d, Ctrl+%s: отмена",
+	"The previous session did not exit cleanly (autosave %s, %d charges).":                                                "Прошлый сеанс завершился аварийно (автосохранение %s, зарядов: %d).",
+	"Enter: restore the scene, Esc: discard":                                                                              "Enter: восстановить сцену, Esc: отказаться",
+	"Drop a scene .json file to load it":                                                                                  "Чтобы загрузить сцену, бросьте на окно её файл .json",
+	"Added %d scene(s), %d charges in total":                                                                              "Добавлено сцен: %d, всего зарядов: %d",
+	"Ruler: drag measures distance, ΔV and Δ|E|, right click removes, %s: done, Shift+%s: clear rulers":                   "Линейка: протяжка измеряет длину, ΔV и Δ|E|, правый клик убирает, %s: готово, Shift+%s: убрать линейки",
+	"ruler tool (Shift: clear rulers)":                                                                                    "линейка (Shift: убрать линейки)",
+	"Angle: click the vertex, then a point on each ray; right click removes, %s: done, Shift+%s: clear angles":            "Угол: щелчок в вершину, затем по точке на каждом луче; правый клик убирает, %s: готово, Shift+%s: убрать углы",
+	"angle tool (Shift: clear angles)":                                                                                    "угломер (Shift: убрать углы)",
+	"Notes: click places or edits a note, Enter: done, Esc: cancel, right click removes, %s: done, Shift+%s: clear notes": "Заметки: щелчок ставит или правит заметку, Enter: готово, Esc: отмена, правый клик убирает, %s: готово, Shift+%s: убрать заметки",
+	"Heatmap on the GPU":                "Теплокарта на видеокарте",
+	"Colorblind-safe colors and shapes": "Цвета и формы для дальтоников",
+	"UI scale x%g":                      "Масштаб интерфейса x%g",
+	"readout units":                     "единицы показаний",
+	"dimensionless":                     "безразмерные",
+	"Units: %s":                         "Единицы: %s",
+	"%s: units (%s); 1 m = %d world units (%.0f px on screen), unit charge = 1 nC": "%s: единицы (%s); 1 м = %d мировых единиц (%.0f пикс на экране), единичный заряд = 1 нКл",
+	"text notes (Shift: clear notes)":                                              "текстовые заметки (Shift: убрать заметки)",
+	"View %d":                                                                      "Вид %d",
+	"Ctrl+1…9: go to a saved view, Ctrl+Shift+1…9: save the current view": "Ctrl+1…9: перейти к сохранённому виду, Ctrl+Shift+1…9: сохранить текущий вид",
+	"Charge #%d": "Заряд №%d",
+	"Apply":      "Применить",
+	"Cancel":     "Отмена",
+	"Tab: next field, Enter: apply, Esc: close":                       "Tab: следующее поле, Enter: применить, Esc: закрыть",
+	"Drag a charge: move it, double-click a charge: exact X, Y and Q": "Протяжка заряда двигает его, двойной щелчок — точные X, Y и Q",
+	"Saved view %d: %s":                                                "Вид %d сохранён: %s",
+	"No view saved at %d (Ctrl+Shift+%d saves)":                        "Вид %d не сохранён (Ctrl+Shift+%d сохраняет)",
+	"View %d: %s":                                                      "Вид %d: %s",
+	"clear scene (press twice)":                                        "очистить сцену (нажать дважды)",
+	"Press %s again to clear all charges and particles":                "Нажмите %s ещё раз, чтобы убрать все заряды и частицы",
+	"Scene cleared, Ctrl+%s: undo":                                     "Сцена очищена, Ctrl+%s: отмена",
+	"Click anywhere to place a positive charge":                        "Щёлкните в любом месте, чтобы поставить положительный заряд",
+	"Shift+click to place a negative charge":                           "Shift+щелчок ставит отрицательный заряд",
+	"Point between the charges and press %s to release a test charge":  "Наведите курсор между зарядами и нажмите %s, чтобы выпустить пробный заряд",
+	"The box at the cursor shows E and V there; Shift+%s pins a probe": "Рамка у курсора показывает E и V в этой точке; Shift+%s закрепляет щуп",
+	"Done! %s lists all keys":                                          "Готово! %s — список всех клавиш",
+	"Tutorial %d/%d":                                                   "Обучение %d/%d",
+	"Enter: skip tutorial":                                             "Enter: пропустить обучение",
+
+	// панель настроек
+	"Settings (%s closes)":                "Настройки (%s закрывает)",
+	"View":                                "Вид",
+	"Background: %s":                      "Фон: %s",
+	"Colormap: %s":                        "Палитра: %s",
+	"Heatmap scale: %s":                   "Шкала фона: %s",
+	"Arrows: %s":                          "Стрелки: %s",
+	"Lines per charge %d":                 "Линий на заряд %d",
+	"Equipotentials":                      "Эквипотенциали",
+	"Flowing dashes":                      "Бегущие штрихи",
+	"Glow":                                "Свечение",
+	"Coordinate grid":                     "Координатная сетка",
+	"Snap to grid":                        "Привязка к сетке",
+	"Charge labels":                       "Подписи зарядов",
+	"Minimap":                             "Миникарта",
+	"Physics":                             "Физика",
+	"Dynamics":                            "Динамика",
+	"Damping %.2f":                        "Затухание %.2f",
+	"Barnes-Hut: exact sum":               "Барнс — Хат: точная сумма",
+	"Barnes-Hut θ %.2f (from %d charges)": "Барнс — Хат θ %.2f (от %d зарядов)",
+	"Coulomb k %.0f":                      "Константа k %.0f",
+	"Paused":                              "Пауза",
+	"Time x%g":                            "Время x%g",
+	"Appearance":                          "Оформление",
+	"Theme: %s":                           "Тема: %s",
+	"Field line width %.1f px":            "Толщина линий %.1f пикс",
+	"Arrow line width %.1f px":            "Толщина стрелок %.1f пикс",
+	"Antialiasing":                        "Сглаживание",
+
+	// сценарии
+	"CRT: electron gun and phosphor screen": "Кинескоп: электронная пушка и экран",
+	"Ua = %.0f V (+/-), Ux = %.0f sin(2pi %.0f t + %.2f) V (Left/Right, W/S, E), Uy = %.0f sin(2pi %.0f t) V (Up/Down, Q/A)": "Ua = %.0f В (+/-), Ux = %.0f sin(2pi %.0f t + %.2f) В (Влево/Вправо, W/S, E), Uy = %.0f sin(2pi %.0f t) В (Вверх/Вниз, Q/A)",
+	"Now: Ux = %+.1f V, Uy = %+.1f V    F2: next scene, Esc: back to editor":                                                 "Сейчас: Ux = %+.1f В, Uy = %+.1f В    F2: следующий сценарий, Esc: редактор",
+	"Electrostatic lens: ":                      "Электростатическая линза: ",
+	"Einzel lens (3 rings, charged middle)":     "Одиночная линза (3 кольца, заряжено среднее)",
+	"Einzel lens with compensating outer rings": "Одиночная линза с компенсирующими кольцами",
+	"Single ring":                               "Одно кольцо",
+	"Ring charge %.1f (Up/Down), beam energy %.0f (Left/Right), half-width %.1f (W/S), P: preset": "Заряд колец %.1f (Вверх/Вниз), энергия пучка %.0f (Влево/Вправо), полуширина %.1f (W/S), P: вариант",
+	"no focus: beam does not cross the axis":                                                      "фокуса нет: пучок не пересекает ось",
+	"paraxial focal length f = %.1f, spherical aberration (marginal - paraxial) = %.1f":           "параксиальный фокус f = %.1f, сферическая аберрация (краевой - параксиальный) = %.1f",
+	"Drag: measuring screen at z = %.0f, spot size %.1f. F2: next scene, Esc: editor":             "Протяжка: экран на z = %.0f, размер пятна %.1f. F2: следующий сценарий, Esc: редактор",
+	"Paul trap (linear quadrupole)":                                                               "Ловушка Пауля (линейный квадруполь)",
+	"Stability diagram (a vs q)":                                                                  "Диаграмма устойчивости (a от q)",
+	"q: 0 .. 1":                                                                                   "q: 0 .. 1",
+	"a: %.1f .. %.1f":                                                                             "a: %.1f .. %.1f",
+	"unstable":                                                                                    "неустойчивое",
+	"stable":                                                                                      "устойчивое",
+	"U = %.3f (Left/Right), V = %.3f (Up/Down), Omega = %.3f (W/S)":                               "U = %.3f (Влево/Вправо), V = %.3f (Вверх/Вниз), Omega = %.3f (W/S)",
+	"a = %.3f, q = %.3f: %s confinement. R: relaunch ion, F2: next scene, Esc: editor": "a = %.3f, q = %.3f: %s удержание. R: новый ион, F2: следующий сценарий, Esc: редактор",
+	"Ion lost to the electrodes":                                                    "Ион ушёл на электроды",
+	"Charged pendulum between capacitor plates":                                     "Заряженный маятник между пластинами конденсатора",
+	"Charged pendulum near a fixed charge":                                          "Заряженный маятник у неподвижного заряда",
+	"Q = %+.2f uC (N: flip sign), d = %.2f m (Left/Right)":                          "Q = %+.2f мкКл (N: знак), d = %.2f м (Влево/Вправо)",
+	"E = %+.1f kV/m, U = %+.1f kV (Left/Right, N: flip)":                            "E = %+.1f кВ/м, U = %+.1f кВ (Влево/Вправо, N: знак)",
+	"q = %.2f uC (Up/Down), %s, C: fixed charge / plates, R: release from vertical": "q = %.2f мкКл (Вверх/Вниз), %s, C: заряд / пластины, R: отпустить с вертикали",
+	"theta = %.2f deg, theory theta_eq = %.2f deg. F2: next scene, Esc: editor":     "theta = %.2f град, теория theta_eq = %.2f град. F2: следующий сценарий, Esc: редактор",
+	"measuring...": "измеряется...",
+	"period: %s, small-oscillation theory %.3f s":             "период: %s, теория малых колебаний %.3f с",
+	"Equilibrium angle vs q (line: theory, dots: simulation)": "Угол равновесия от q (линия: теория, точки: расчёт)",
+	"q: 0 .. %.1f uC":    "q: 0 .. %.1f мкКл",
+	"theta: +/-%.1f deg": "theta: +/-%.1f град",
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Клавиатура и мышь читаются не напрямую из ebiten, а из снимка, который
+// снимается раз в тик (pollInput). Это позволяет записать сеанс в файл и
+// воспроизвести его: запись хранит изменения снимка с номером тика, при
+// воспроизведении снимок собирается из файла вместо устройств. Тики идут
+// с постоянной частотой, а случайные числа берутся из rng с записанным
+// зерном, поэтому повтор совпадает с оригиналом кадр в кадр — при тех же
+// флагах запуска. Геймпад и касания не записываются.
+//
+// Набранные символы (для ввода текста) тоже идут через снимок, а
+// счётчики удержания клавиш дают автоповтор, как у системного ввода.
+
+const (
+	sessionVersion = 1
+	repeatDelay    = 30 // тиков до начала автоповтора
+	repeatEvery    = 4
+)
+
+type inputFrame struct {
+	keys    [ebiten.KeyMax + 1]bool
+	buttons [ebiten.MouseButtonMax + 1]bool
+	x, y    int
+	wx, wy  float64
+	chars   []rune
+}
+
+// SessionHeader — первая строка файла сеанса.
+type SessionHeader struct {
+	Version int    `json:"version"`
+	Seed    uint64 `json:"seed"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+}
+
+type InputEventKind string
+
+const (
+	InputKey    InputEventKind = "key"
+	InputButton InputEventKind = "button"
+	InputMove   InputEventKind = "move"
+	InputWheel  InputEventKind = "wheel"
+	InputChar   InputEventKind = "char"
+)
+
+// InputEvent — одно изменение ввода; остальные строки файла сеанса.
+type InputEvent struct {
+	Tick int64          `json:"tick"`
+	Kind InputEventKind `json:"kind"`
+	Code int            `json:"code,omitempty"` // клавиша, кнопка или символ
+	Down bool           `json:"down,omitempty"`
+	X    float64        `json:"x,omitempty"`
+	Y    float64        `json:"y,omitempty"`
+}
+
+var input struct {
+	cur, prev inputFrame
+	tick      int64
+	held      [ebiten.KeyMax + 1]int // тиков, что клавиша зажата
+	rec       *sessionRecorder
+	play      *sessionPlayer
+}
+
+// pollInput снимает ввод нового тика; вызывается в начале Update.
+func pollInput() {
+	input.prev = input.cur
+	input.tick++
+	defer countHeld()
+	if p := input.play; p != nil {
+		if p.apply(&input.cur, input.tick) {
+			return
+		}
+		input.play = nil
+		log.Printf("replay finished at tick %d", input.tick)
+	}
+	liveInput(&input.cur)
+	if r := input.rec; r != nil {
+		r.diff(&input.prev, &input.cur, input.tick)
+	}
+}
+
+func countHeld() {
+	for k, down := range input.cur.keys {
+		if down {
+			input.held[k]++
+		} else {
+			input.held[k] = 0
+		}
+	}
+}
+
+func liveInput(f *inputFrame) {
+	for k := range f.keys {
+		f.keys[k] = ebiten.IsKeyPressed(ebiten.Key(k))
+	}
+	for b := range f.buttons {
+		f.buttons[b] = ebiten.IsMouseButtonPressed(ebiten.MouseButton(b))
+	}
+	f.x, f.y = ebiten.CursorPosition()
+	f.wx, f.wy = ebiten.Wheel()
+	f.chars = ebiten.AppendInputChars(nil)
+}
+
+func keyPressed(k ebiten.Key) bool      { return input.cur.keys[k] }
+func keyJustPressed(k ebiten.Key) bool  { return input.cur.keys[k] && !input.prev.keys[k] }
+func keyJustReleased(k ebiten.Key) bool { return !input.cur.keys[k] && input.prev.keys[k] }
+
+// keyRepeated — нажатие и автоповтор удерживаемой клавиши.
+func keyRepeated(k ebiten.Key) bool {
+	n := input.held[k]
+	return n == 1 || n >= repeatDelay && (n-repeatDelay)%repeatEvery == 0
+}
+
+// inputChars — символы, набранные в этом тике.
+func inputChars() []rune { return input.cur.chars }
+
+func mousePressed(b ebiten.MouseButton) bool { return input.cur.buttons[b] }
+func mouseJustPressed(b ebiten.MouseButton) bool {
+	return input.cur.buttons[b] && !input.prev.buttons[b]
+}
+
+func cursorPosition() (int, int) { return input.cur.x, input.cur.y }
+func wheel() (float64, float64)  { return input.cur.wx, input.cur.wy }
+
+type sessionRecorder struct {
+	f   *os.File
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func startRecording(path string, h SessionHeader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	r := &sessionRecorder{f: f, w: w, enc: json.NewEncoder(w)}
+	if err := r.enc.Encode(h); err != nil {
+		f.Close()
+		return err
+	}
+	input.rec = r
+	return nil
+}
+
+func (r *sessionRecorder) diff(prev, cur *inputFrame, tick int64) {
+	put := func(e InputEvent) {
+		e.Tick = tick
+		if err := r.enc.Encode(e); err != nil {
+			log.Printf("record input: %v", err)
+		}
+	}
+	for k := range cur.keys {
+		if cur.keys[k] != prev.keys[k] {
+			put(InputEvent{Kind: InputKey, Code: k, Down: cur.keys[k]})
+		}
+	}
+	for b := range cur.buttons {
+		if cur.buttons[b] != prev.buttons[b] {
+			put(InputEvent{Kind: InputButton, Code: b, Down: cur.buttons[b]})
+		}
+	}
+	if cur.x != prev.x || cur.y != prev.y {
+		put(InputEvent{Kind: InputMove, X: float64(cur.x), Y: float64(cur.y)})
+	}
+	if cur.wx != 0 || cur.wy != 0 {
+		put(InputEvent{Kind: InputWheel, X: cur.wx, Y: cur.wy})
+	}
+	for _, c := range cur.chars {
+		put(InputEvent{Kind: InputChar, Code: int(c)})
+	}
+}
+
+// stopRecording дописывает буфер; вызывается при выходе из Run.
+func stopRecording() {
+	r := input.rec
+	if r == nil {
+		return
+	}
+	input.rec = nil
+	err := r.w.Flush()
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Printf("save input recording: %v", err)
+	}
+}
+
+type sessionPlayer struct {
+	events []InputEvent
+	next   int
+}
+
+// loadSession читает файл сеанса целиком.
+func loadSession(path string) (SessionHeader, *sessionPlayer, error) {
+	var h SessionHeader
+	f, err := os.Open(path)
+	if err != nil {
+		return h, nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := dec.Decode(&h); err != nil {
+		return h, nil, fmt.Errorf("%s: header: %w", path, err)
+	}
+	if h.Version != sessionVersion {
+		return h, nil, fmt.Errorf("%s: unsupported version %d", path, h.Version)
+	}
+	p := &sessionPlayer{}
+	for {
+		var e InputEvent
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return h, nil, fmt.Errorf("%s: event %d: %w", path, len(p.events)+1, err)
+		}
+		p.events = append(p.events, e)
+	}
+	return h, p, nil
+}
+
+// apply собирает снимок тика из записанных событий; false — запись
+// кончилась и ввод снова берётся с устройств.
+func (p *sessionPlayer) apply(f *inputFrame, tick int64) bool {
+	if p.next == len(p.events) {
+		return false
+	}
+	f.wx, f.wy = 0, 0
+	f.chars = nil
+	for ; p.next < len(p.events) && p.events[p.next].Tick <= tick; p.next++ {
+		e := p.events[p.next]
+		switch e.Kind {
+		case InputKey:
+			if e.Code >= 0 && e.Code < len(f.keys) {
+				f.keys[e.Code] = e.Down
+			}
+		case InputButton:
+			if e.Code >= 0 && e.Code < len(f.buttons) {
+				f.buttons[e.Code] = e.Down
+			}
+		case InputMove:
+			f.x, f.y = int(e.X), int(e.Y)
+		case InputWheel:
+			f.wx, f.wy = e.X, e.Y
+		case InputChar:
+			f.chars = append(f.chars, rune(e.Code))
+		}
+	}
+	return true
+}
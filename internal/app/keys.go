@@ -0,0 +1,214 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Привязки клавиш редактора. ebiten.Key обозначает физическую клавишу
+// (по положению на US-раскладке), а не символ, поэтому горячие клавиши
+// работают при любой раскладке, в том числе кириллической. Привязки
+// можно переопределить в keys.json каталога настроек; справка по F1
+// строится по keymap и показывает действующие клавиши. Клавиши внутри
+// сценариев (линза, кинескоп, маятник, ловушка) свои и сюда не входят.
+
+var (
+	keyTestParticle = ebiten.KeyT
+	keyRandomScene  = ebiten.KeyR
+	keyWire         = ebiten.KeyW
+	keyBackground   = ebiten.KeyB
+	keyColormap     = ebiten.KeyV // без Ctrl
+	keyContours     = ebiten.KeyI
+	keyTransfer     = ebiten.KeyL
+	keyArrows       = ebiten.KeyA
+	keyDashes       = ebiten.KeyF
+	keyRangeDown    = ebiten.KeyMinus
+	keyRangeUp      = ebiten.KeyEqual
+	keySurface      = ebiten.KeyTab
+	keyPan          = ebiten.KeySpace
+	keyStep         = ebiten.KeyPeriod
+	keySlower       = ebiten.KeyBracketLeft
+	keyFaster       = ebiten.KeyBracketRight
+	keyResetView    = ebiten.Key0
+	keyGrid         = ebiten.KeyX
+	keyProbe        = ebiten.KeyQ
+	keyLabels       = ebiten.KeyN
+	keyClearTrails  = ebiten.KeyY // без Ctrl
+	keyLineStyle    = ebiten.KeyF5
+	keyTheme        = ebiten.KeyF4
+	keyMinimap      = ebiten.KeyJ
+	keySplit        = ebiten.KeyU
+	keyGlow         = ebiten.KeyZ // без Ctrl
+	keyView3D       = ebiten.KeyO // без Ctrl
+	keySeedTool     = ebiten.KeyS // без Ctrl
+	keyMagnifier    = ebiten.KeyM
+	keySection      = ebiten.KeyH
+	keyUndo         = ebiten.KeyZ // с Ctrl
+	keyRedo         = ebiten.KeyY // с Ctrl
+	keySaveScene    = ebiten.KeyS // с Ctrl
+	keyLoadScene    = ebiten.KeyO // с Ctrl
+	keyExport3D     = ebiten.KeyE
+	keyExportImage  = ebiten.KeyE // без Ctrl
+	keyDynamics     = ebiten.KeyD // без Ctrl
+	keyDamping      = ebiten.KeyG
+	keyPresets      = ebiten.KeyP
+	keyTrack        = ebiten.KeyK
+	keyHelp         = ebiten.KeyF1
+	keyScenes       = ebiten.KeyF2
+	keyRelativistic = ebiten.KeyF3
+	keySolver       = ebiten.KeyF6
+	keyDiagnostics  = ebiten.KeyF7
+	keyCutPlane     = ebiten.KeyF8
+	keyCutTiltUp    = ebiten.KeyPageUp
+	keyCutTiltDown  = ebiten.KeyPageDown
+	keyCutForward   = ebiten.KeyHome
+	keyCutBack      = ebiten.KeyEnd
+	keyMacroRecord  = ebiten.KeyF9
+	keyMacroReplay  = ebiten.KeyF10
+	keyStats        = ebiten.KeyF12
+
+	keyDeleteSelection = ebiten.KeyDelete
+	keyCopy            = ebiten.KeyC // с Ctrl
+	keyPaste           = ebiten.KeyV // с Ctrl
+	keyDuplicate       = ebiten.KeyD // с Ctrl
+	keySettings        = ebiten.KeyBackquote
+	keyFullscreen      = ebiten.KeyF11
+	keyClearAll        = ebiten.KeyC // без Ctrl
+	keyRuler           = ebiten.Key1 // без Ctrl
+	keyProtractor      = ebiten.Key2 // без Ctrl
+	keyNote            = ebiten.Key3 // без Ctrl
+	keyUnits           = ebiten.Key4 // без Ctrl
+)
+
+// binding связывает имя в keys.json с переменной клавиши и строкой справки.
+type binding struct {
+	name string
+	key  *ebiten.Key
+	help string
+}
+
+var keymap = []binding{
+	{"test-particle", &keyTestParticle, "test charge (Shift: plasma, Alt: chart)"},
+	{"random-scene", &keyRandomScene, "random scene (Shift: count, Alt: neutral)"},
+	{"wire", &keyWire, "wire out of screen (Shift: into, Ctrl: clear)"},
+	{"background", &keyBackground, "cycle background mode"},
+	{"colormap", &keyColormap, "cycle colormap"},
+	{"contours", &keyContours, "equipotential contours"},
+	{"transfer", &keyTransfer, "heatmap transfer function"},
+	{"arrows", &keyArrows, "arrows (Shift: scaling, Ctrl: density)"},
+	{"dashes", &keyDashes, "flowing dashes on field lines"},
+	{"range-down", &keyRangeDown, "narrow heatmap range / fewer seeds"},
+	{"range-up", &keyRangeUp, "widen heatmap range / more seeds"},
+	{"surface", &keySurface, "potential surface view"},
+	{"pan", &keyPan, "hold and drag to pan, tap to pause"},
+	{"step", &keyStep, "one physics step while paused"},
+	{"slower", &keySlower, "slower simulation (down to 0.1x)"},
+	{"faster", &keyFaster, "faster simulation (up to 10x)"},
+	{"reset-view", &keyResetView, "reset view"},
+	{"grid", &keyGrid, "grid (Shift: snap, Alt: snap step)"},
+	{"probe", &keyProbe, "probe (Shift: pin, Ctrl: unpin)"},
+	{"labels", &keyLabels, "charge labels"},
+	{"clear-trails", &keyClearTrails, "clear trails"},
+	{"line-style", &keyLineStyle, "line width (Shift: arrows, Ctrl: AA)"},
+	{"theme", &keyTheme, "color theme"},
+	{"minimap", &keyMinimap, "minimap"},
+	{"split", &keySplit, "split screen"},
+	{"glow", &keyGlow, "glow"},
+	{"view-3d", &keyView3D, "3D view (Shift: stereo)"},
+	{"seed-tool", &keySeedTool, "seed tool (Shift: clear seeds)"},
+	{"magnifier", &keyMagnifier, "hold for magnifier"},
+	{"section", &keySection, "section plot (Shift: close)"},
+	{"undo", &keyUndo, "with Ctrl: undo (Shift: redo)"},
+	{"redo", &keyRedo, "with Ctrl: redo"},
+	{"save-scene", &keySaveScene, "with Ctrl: save scene"},
+	{"load-scene", &keyLoadScene, "with Ctrl: open scene"},
+	{"export-3d", &keyExport3D, "with Ctrl: 3D export"},
+	{"export-image", &keyExportImage, "export PNG (Shift: resolution)"},
+	{"dynamics", &keyDynamics, "dynamics"},
+	{"damping", &keyDamping, "more damping (Shift: less)"},
+	{"presets", &keyPresets, "preset menu"},
+	{"track", &keyTrack, "ring / rod track for charge"},
+	{"help", &keyHelp, "this help (Shift: tutorial)"},
+	{"scenes", &keyScenes, "scenarios"},
+	{"relativistic", &keyRelativistic, "relativistic test particle"},
+	{"solver", &keySolver, "field solver (Shift: boundary)"},
+	{"diagnostics", &keyDiagnostics, "div/curl check"},
+	{"cut-plane", &keyCutPlane, "cut plane"},
+	{"cut-tilt-up", &keyCutTiltUp, "tilt cut plane up"},
+	{"cut-tilt-down", &keyCutTiltDown, "tilt cut plane down"},
+	{"cut-forward", &keyCutForward, "move cut plane forward"},
+	{"cut-back", &keyCutBack, "move cut plane back"},
+	{"macro-record", &keyMacroRecord, "record macro"},
+	{"macro-replay", &keyMacroReplay, "replay macro (Shift: next)"},
+	{"stats", &keyStats, "FPS and performance statistics"},
+	{"delete-selection", &keyDeleteSelection, "delete selected charges"},
+	{"copy", &keyCopy, "with Ctrl: copy selection"},
+	{"paste", &keyPaste, "with Ctrl: paste at cursor"},
+	{"duplicate", &keyDuplicate, "with Ctrl: duplicate selection"},
+	{"settings", &keySettings, "settings panel"},
+	{"fullscreen", &keyFullscreen, "fullscreen"},
+	{"clear-all", &keyClearAll, "clear scene (press twice)"},
+	{"ruler", &keyRuler, "ruler tool (Shift: clear rulers)"},
+	{"protractor", &keyProtractor, "angle tool (Shift: clear angles)"},
+	{"notes", &keyNote, "text notes (Shift: clear notes)"},
+	{"units", &keyUnits, "readout units"},
+}
+
+const keysFile = "keys.json"
+
+// loadKeymap переопределяет привязки из keys.json: {"glow": "G", ...}.
+// Имена клавиш — как у ebiten.Key (A, Digit1, F5, PageUp).
+func loadKeymap() error {
+	dir, err := prefsDir()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, keysFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var keys map[string]ebiten.Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("parse %s: %w", keysFile, err)
+	}
+	for name, k := range keys {
+		i := slices.IndexFunc(keymap, func(b binding) bool { return b.name == name })
+		if i < 0 {
+			return fmt.Errorf("%s: unknown action %q", keysFile, name)
+		}
+		*keymap[i].key = k
+	}
+	return nil
+}
+
+// keyLabel возвращает подпись клавиши в текущей раскладке, если её можно
+// вывести шрифтом интерфейса, иначе имя физической клавиши.
+func keyLabel(k ebiten.Key) string {
+	name := ebiten.KeyName(k)
+	if strings.TrimSpace(name) == "" || !isPrintableASCII(name) {
+		return strings.TrimPrefix(k.String(), "Digit")
+	}
+	return strings.ToUpper(name)
+}
+
+func isPrintableASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Подписи величины зарядов. Для каждой подписи перебираются позиции вокруг
+// заряда, берётся первая, не задевающая уже поставленные подписи и кружки
+// других зарядов; если свободных нет — та, где перекрытие меньше.
+
+const labelGap = 3 // зазор между кружком заряда и подписью
+
+func formatQ(q float64) string {
+	if q == math.Trunc(q) {
+		return fmt.Sprintf("%+.0fq", q)
+	}
+	return fmt.Sprintf("%+.2gq", q)
+}
+
+func overlapArea(a, b image.Rectangle) int {
+	r := a.Intersect(b)
+	return r.Dx() * r.Dy()
+}
+
+func (g *Game) drawChargeLabels(screen *ebiten.Image) {
+	if !g.labels {
+		return
+	}
+	charges := g.sliceCharges()
+
+	var blocked []image.Rectangle
+	for _, c := range charges {
+		x, y := g.cam.toScreen(c.X, c.Y)
+		r := int(chargeGlyphRadius(c.Q))
+		blocked = append(blocked, image.Rect(int(x)-r, int(y)-r, int(x)+r, int(y)+r))
+	}
+
+	face, h := uiFace, lineHeight()
+	for _, c := range charges {
+		s := formatQ(c.Q)
+		w := textWidth(s)
+		cx, cy := g.cam.toScreen(c.X, c.Y)
+
+		var best image.Rectangle
+		bestCost := math.MaxInt
+		for k := 0; k < 8; k++ {
+			a := float64(k) * math.Pi / 4
+			d := chargeGlyphRadius(c.Q) + float64(ui(labelGap))
+			// центр подписи на окружности вокруг заряда, рамка вынесена наружу
+			px := float64(cx) + (d+float64(w)/2)*math.Cos(a)
+			py := float64(cy) - (d+float64(h)/2)*math.Sin(a)
+			rect := image.Rect(int(px)-w/2, int(py)-h/2, int(px)+w/2, int(py)-h/2+h)
+
+			cost := 0
+			for _, b := range blocked {
+				cost += overlapArea(rect, b)
+			}
+			if cost < bestCost {
+				best, bestCost = rect, cost
+			}
+			if cost == 0 {
+				break
+			}
+		}
+		blocked = append(blocked, best)
+		text.Draw(screen, s, face, best.Min.X, baseline(best.Min.Y, h), g.theme().HUD)
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import "image"
+
+// Нижние панели — миникарта, графики разреза и пробной частицы, легенда
+// теплокарты — привязаны к нижнему и правому краям вида и пересчитываются
+// в resize и при смене масштаба интерфейса. Размеры ниже заданы для
+// масштаба 1 и проходят через ui. При размере по умолчанию (900×600)
+// раскладка та же, что была с постоянными координатами; в узком окне
+// график частицы уходит над графиком разреза, чтобы не залезать под легенду.
+
+const (
+	panelsBottom = 74 // над строкой состояния: место под строки энергии и подсказок
+	panelsRight  = 95 // место под подписи легенды
+	panelGap     = 10
+	plotH        = 115
+	sectionW     = 380
+	stripW       = 215
+	colorbarH    = 184
+)
+
+type hudLayout struct {
+	scale          float64 // uiScale, при котором посчитана раскладка
+	minimap        image.Rectangle
+	section, strip plotPanel
+	colorbarTop    int
+}
+
+// layoutHUD раскладывает нижние панели по текущему размеру вида.
+func (g *Game) layoutHUD() {
+	w, h := g.cam.W, g.cam.H
+	l := &g.hud
+	l.scale = uiScale
+	bottom := h - statusBarH() - ui(panelsBottom)
+	right := w - ui(panelsRight)
+	gap, ph := ui(panelGap), ui(plotH)
+
+	l.minimap = image.Rect(gap, bottom-ui(minimapH), gap+ui(minimapW), bottom)
+
+	x := l.minimap.Max.X + gap
+	sw, tw := ui(sectionW), ui(stripW)
+	l.section = plotPanel{X: float32(x), Y: float32(bottom - ph), W: float32(min(sw, right-x)), H: float32(ph), XLabel: "distance"}
+	l.strip = plotPanel{X: float32(right - tw), Y: float32(bottom - ph), W: float32(tw), H: float32(ph), XLabel: "t"}
+	if x+sw+gap+tw > right {
+		l.strip.Y -= float32(ph + gap)
+	}
+
+	l.colorbarTop = h - ui(colorbarMargin) - ui(colorbarH)
+}
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Электростатическая линза из заряженных колец. Осесимметричное поле
+// берётся в параксиальном приближении по потенциалу на оси Φ(z):
+// E_z = -Φ' + r²/4·Φ''', E_r = r/2·Φ''. Член r² даёт сферическую аберрацию.
+
+const (
+	lensRays    = 15    // лучей в пучке
+	lensDt      = 0.05  // шаг интегрирования луча
+	lensZStart  = -430  // откуда стартует пучок
+	lensZEnd    = 430   // где заканчивается трассировка
+	lensBeamQM  = 1.0   // q/m ионов пучка
+	lensMaxStep = 40000 // ограничение на число шагов луча
+)
+
+type lensRing struct {
+	Z, R, Q float64
+}
+
+type lensPreset struct {
+	name  string
+	rings []lensRing
+}
+
+var lensPresets = []lensPreset{
+	{"Einzel lens (3 rings, charged middle)", []lensRing{{-70, 60, 0}, {0, 60, 1}, {70, 60, 0}}},
+	{"Einzel lens with compensating outer rings", []lensRing{{-70, 60, -0.5}, {0, 60, 1}, {70, 60, -0.5}}},
+	{"Single ring", []lensRing{{0, 60, 1}}},
+}
+
+type lensScenario struct {
+	preset  int
+	centerQ float64 // заряд среднего кольца, масштабирует пресет
+	energy  float64 // кинетическая энергия ионов на единицу массы
+	width   float64 // полуширина пучка
+
+	screenZ  float64 // положение измерительного экрана
+	dragging bool
+
+	rays    [][]Vec2
+	crosses []float64 // точки пересечения оси по лучам, от параксиального к краевому
+	dirty   bool
+}
+
+func newLensScenario() *lensScenario {
+	return &lensScenario{
+		centerQ: 27,
+		energy:  1000,
+		width:   20,
+		screenZ: 250,
+		dirty:   true,
+	}
+}
+
+func (s *lensScenario) Name() string {
+	return tr("Electrostatic lens: ") + tr(lensPresets[s.preset].name)
+}
+
+// axisPotential возвращает Φ, Φ', Φ”, Φ”' на оси.
+func (s *lensScenario) axisPotential(z float64) (float64, float64, float64, float64) {
+	var p0, p1, p2, p3 float64
+	for _, r := range lensPresets[s.preset].rings {
+		q := kConst * r.Q * s.centerQ
+		d := z - r.Z
+		D := r.R*r.R + d*d
+		sq := math.Sqrt(D)
+
+		p0 += q / sq
+		p1 += q * -d / (D * sq)
+		p2 += q * (2*d*d - r.R*r.R) / (D * D * sq)
+		p3 += q * 3 * d * (3*r.R*r.R - 2*d*d) / (D * D * D * sq)
+	}
+	return p0, p1, p2, p3
+}
+
+func (s *lensScenario) traceRays() {
+	s.rays = s.rays[:0]
+	s.crosses = s.crosses[:0]
+
+	type cross struct{ h, z float64 }
+	var found []cross
+
+	v0 := math.Sqrt(2 * s.energy)
+	for i := 0; i < lensRays; i++ {
+		h := s.width * (2*float64(i)/(lensRays-1) - 1)
+		if h == 0 {
+			continue
+		}
+
+		z, r := float64(lensZStart), h
+		vz, vr := v0, 0.0
+		path := []Vec2{{X: z, Y: r}}
+		crossZ := math.NaN()
+
+		for step := 0; step < lensMaxStep && z < lensZEnd && z > lensZStart-1; step++ {
+			_, p1, p2, p3 := s.axisPotential(z)
+			ez := -p1 + r*r/4*p3
+			er := r / 2 * p2
+
+			vz += lensBeamQM * ez * lensDt
+			vr += lensBeamQM * er * lensDt
+			prevR := r
+			z += vz * lensDt
+			r += vr * lensDt
+
+			if math.IsNaN(crossZ) && z > 0 && prevR*r < 0 {
+				crossZ = z
+			}
+			if step%10 == 0 {
+				path = append(path, Vec2{X: z, Y: r})
+			}
+			if math.Abs(r) > halfH {
+				break
+			}
+		}
+		path = append(path, Vec2{X: z, Y: r})
+
+		s.rays = append(s.rays, path)
+		if !math.IsNaN(crossZ) {
+			found = append(found, cross{h: math.Abs(h), z: crossZ})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].h < found[j].h })
+	for _, c := range found {
+		s.crosses = append(s.crosses, c.z)
+	}
+	s.dirty = false
+}
+
+// spotSize возвращает размах пучка на измерительном экране.
+func (s *lensScenario) spotSize() float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, ray := range s.rays {
+		for i := 1; i < len(ray); i++ {
+			a, b := ray[i-1], ray[i]
+			if a.X <= s.screenZ && b.X > s.screenZ {
+				t := (s.screenZ - a.X) / (b.X - a.X)
+				r := a.Y + t*(b.Y-a.Y)
+				lo = math.Min(lo, r)
+				hi = math.Max(hi, r)
+				break
+			}
+		}
+	}
+	if lo > hi {
+		return math.NaN()
+	}
+	return hi - lo
+}
+
+func (s *lensScenario) Update() {
+	changed := true
+	switch {
+	case keyPressed(ebiten.KeyUp):
+		s.centerQ += 0.2
+	case keyPressed(ebiten.KeyDown):
+		s.centerQ -= 0.2
+	case keyPressed(ebiten.KeyRight):
+		s.energy = math.Min(s.energy+20, 20000)
+	case keyPressed(ebiten.KeyLeft):
+		s.energy = math.Max(s.energy-20, 100)
+	case keyPressed(ebiten.KeyW):
+		s.width = math.Min(s.width+0.5, 40)
+	case keyPressed(ebiten.KeyS):
+		s.width = math.Max(s.width-0.5, 2)
+	case keyJustPressed(ebiten.KeyP):
+		s.preset = (s.preset + 1) % len(lensPresets)
+	default:
+		changed = false
+	}
+	if changed {
+		s.dirty = true
+	}
+
+	mx, _ := cursorPosition()
+	if mouseJustPressed(ebiten.MouseButtonLeft) {
+		s.dragging = true
+	}
+	if !mousePressed(ebiten.MouseButtonLeft) {
+		s.dragging = false
+	}
+	if s.dragging {
+		s.screenZ = float64(mx) - halfW
+	}
+
+	if s.dirty {
+		s.traceRays()
+	}
+}
+
+func (s *lensScenario) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 14, 255})
+
+	toScreen := func(z, r float64) (float32, float32) {
+		return float32(z + halfW), float32(halfH - r)
+	}
+
+	ax0, ay := toScreen(lensZStart, 0)
+	ax1, _ := toScreen(lensZEnd, 0)
+	vector.StrokeLine(screen, ax0, ay, ax1, ay, 1, color.RGBA{90, 90, 100, 255}, false)
+
+	for _, r := range lensPresets[s.preset].rings {
+		q := r.Q * s.centerQ
+		if q == 0 {
+			continue
+		}
+		col := plateColor(q)
+		for _, sign := range []float64{1, -1} {
+			x, y := toScreen(r.Z, sign*r.R)
+			vector.DrawFilledRect(screen, x-4, y-8*float32(sign)-4, 8, 16, col, false)
+		}
+	}
+
+	for _, ray := range s.rays {
+		for i := 1; i < len(ray); i++ {
+			x1, y1 := toScreen(ray[i-1].X, ray[i-1].Y)
+			x2, y2 := toScreen(ray[i].X, ray[i].Y)
+			vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{255, 220, 80, 200}, false)
+		}
+	}
+
+	face := uiFace
+	if len(s.crosses) > 0 {
+		fz := s.crosses[0]
+		fx, fy := toScreen(fz, 0)
+		vector.StrokeCircle(screen, fx, fy, 5, 2, color.RGBA{80, 255, 120, 255}, false)
+		text.Draw(screen, "F", face, int(fx)-textWidth("F")/2, int(fy)-ui(10), color.RGBA{80, 255, 120, 255})
+	}
+
+	sx, sy0 := toScreen(s.screenZ, halfH-70)
+	_, sy1 := toScreen(s.screenZ, -halfH+10)
+	vector.StrokeLine(screen, sx, sy0, sx, sy1, 1, color.RGBA{120, 180, 255, 255}, false)
+
+	text.Draw(screen, s.Name(), face, ui(10), ui(20), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("Ring charge %.1f (Up/Down), beam energy %.0f (Left/Right), half-width %.1f (W/S), P: preset"),
+		s.centerQ, s.energy, s.width), face, ui(10), ui(40), color.White)
+
+	focus := tr("no focus: beam does not cross the axis")
+	if len(s.crosses) > 0 {
+		f := s.crosses[0]
+		aberr := s.crosses[len(s.crosses)-1] - f
+		focus = fmt.Sprintf(tr("paraxial focal length f = %.1f, spherical aberration (marginal - paraxial) = %.1f"), f, aberr)
+	}
+	text.Draw(screen, focus, face, ui(10), ui(60), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("Drag: measuring screen at z = %.0f, spot size %.1f. F2: next scene, Esc: editor"),
+		s.screenZ, s.spotSize()), face, ui(10), ui(80), color.White)
+}
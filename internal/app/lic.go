@@ -0,0 +1,60 @@
+package app
+
+import (
+	"math"
+
+	"electric-field/internal/lic"
+)
+
+// Фон LIC: шум, размазанный вдоль линий поля, поверх цвета тепловой
+// карты. Шум создаётся один раз, свёртка пересчитывается вместе со сценой.
+
+const (
+	licLength = 20  // полудлина ядра свёртки, пикс
+	licSeed   = 1   // зерно шума, чтобы текстура не мерцала между пересчётами
+	licTint   = 110 // яркость текстуры там, где тепловая карта тёмная
+)
+
+func (g *Game) recomputeLIC() {
+	w, h := g.cam.W, g.cam.H
+	f := lic.NewField(w, h)
+	if len(g.bgMag) != w*h {
+		g.bgMag = make([]float64, w*h)
+	}
+
+	parallelRows(h, func(py int) {
+		for px := 0; px < w; px++ {
+			Ex, Ey := g.sliceField(g.cam.toWorld(float64(px), float64(py)))
+			k := py*w + px
+			f.U[k], f.V[k] = float32(Ex), float32(Ey)
+			g.bgMag[k] = math.Hypot(Ex, Ey)
+		}
+	})
+
+	if len(g.licNoise) != w*h {
+		g.licNoise = lic.Noise(w, h, licSeed)
+	}
+	tex := lic.Convolve(f, g.licNoise, licLength)
+
+	// усреднение гасит контраст шума: растягиваем ±2σ на всю шкалу
+	var mean, sq float64
+	for _, v := range tex {
+		mean += float64(v)
+		sq += float64(v) * float64(v)
+	}
+	mean /= float64(len(tex))
+	sigma := math.Sqrt(math.Max(sq/float64(len(tex))-mean*mean, 1e-12))
+
+	g.heat.prepare(g.bgMag)
+	pix := g.bgPixels()
+	for k, v := range tex {
+		t := math.Max(0, math.Min(1, 0.5+(float64(v)-mean)/(4*sigma)))
+		c := g.heatColor(g.bgMag[k])
+		shade := func(ch uint8) byte {
+			return byte(math.Min(255, float64(ch)*(0.35+0.65*t)+licTint*t))
+		}
+		pix[4*k], pix[4*k+1], pix[4*k+2], pix[4*k+3] = shade(c.R), shade(c.G), shade(c.B), 255
+	}
+
+	g.writeBackground(pix)
+}
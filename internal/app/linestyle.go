@@ -0,0 +1,28 @@
+package app
+
+import "fmt"
+
+// Толщина линий и сглаживание: для проектора и записи экрана нужны
+// толстые сглаженные линии, для обычной работы хватает тонких.
+
+var lineWidths = []float32{1, 1.5, 2, 3, 4}
+
+type lineStyle struct {
+	field     int // индекс в lineWidths для силовых линий
+	arrow     int // индекс в lineWidths для стрелок
+	Antialias bool
+}
+
+func (s lineStyle) FieldWidth() float32 { return lineWidths[s.field] }
+func (s lineStyle) ArrowWidth() float32 { return lineWidths[s.arrow] }
+
+func (s *lineStyle) cycleField() { s.field = (s.field + 1) % len(lineWidths) }
+func (s *lineStyle) cycleArrow() { s.arrow = (s.arrow + 1) % len(lineWidths) }
+
+func (s lineStyle) String() string {
+	aa := tr("off")
+	if s.Antialias {
+		aa = tr("on")
+	}
+	return fmt.Sprintf(tr("lines %.1f px, arrows %.1f px, antialiasing %s"), s.FieldWidth(), s.ArrowWidth(), aa)
+}
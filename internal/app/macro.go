@@ -0,0 +1,289 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Макросы: запись последовательности правок сцены и повтор в позиции курсора.
+// Правки существующих зарядов (Q ± шаг, смена знака) при повторе находят
+// заряд в той же точке относительно курсора; если там пусто, правка
+// пропускается. По окончании записи макросу дают имя.
+
+const macrosFile = "macros.json"
+
+type EditKind string
+
+const (
+	EditAddCharge EditKind = "add_charge"
+	EditAdjustQ   EditKind = "adjust_q" // Q — приращение заряда
+	EditFlipSign  EditKind = "flip_sign"
+)
+
+const macroNameMaxLen = 32
+
+// EditAction хранит координаты относительно первой записанной правки,
+// чтобы макрос можно было воспроизвести в любом месте.
+type EditAction struct {
+	Kind EditKind `json:"kind"`
+	DX   float64  `json:"dx"`
+	DY   float64  `json:"dy"`
+	Q    float64  `json:"q"`
+}
+
+type Macro struct {
+	Name    string       `json:"name"`
+	Actions []EditAction `json:"actions"`
+}
+
+type macroRecorder struct {
+	macros   []Macro
+	selected int
+
+	recording bool
+	anchor    Vec2
+	actions   []EditAction
+
+	naming bool // запись окончена, вводится имя
+	name   []rune
+	blink  int
+	ui     widgetState
+}
+
+func prefsDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "physics", "electric-field"), nil
+}
+
+func loadMacros() ([]Macro, error) {
+	dir, err := prefsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, macrosFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var macros []Macro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", macrosFile, err)
+	}
+	return macros, nil
+}
+
+func saveMacros(macros []Macro) error {
+	dir, err := prefsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(macros, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, macrosFile), data, 0o644)
+}
+
+func (g *Game) toggleMacroRecording() {
+	m := &g.macro
+
+	if !m.recording {
+		m.recording = true
+		m.actions = nil
+		return
+	}
+
+	m.recording = false
+	if len(m.actions) == 0 {
+		return
+	}
+	m.naming, m.blink = true, 0
+	m.name = []rune(fmt.Sprintf("macro-%d", len(m.macros)+1))
+}
+
+// saveRecordedMacro сохраняет записанные правки под введённым именем.
+func (g *Game) saveRecordedMacro() {
+	m := &g.macro
+	name := strings.TrimSpace(string(m.name))
+	if name == "" {
+		name = fmt.Sprintf("macro-%d", len(m.macros)+1)
+	}
+	m.macros = append(m.macros, Macro{Name: name, Actions: m.actions})
+	m.selected = len(m.macros) - 1
+	m.actions, m.naming = nil, false
+
+	if err := saveMacros(m.macros); err != nil {
+		log.Printf("save macros: %v", err)
+	}
+}
+
+func (g *Game) macroNameRect() image.Rectangle {
+	w, h := ui(editorW), 4*ui(widgetRowH)+2*ui(editorPad)
+	x, y := (g.cam.W-w)/2, (g.cam.H-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func (g *Game) macroNameWidgets(dst *ebiten.Image) *widgets {
+	r := g.macroNameRect()
+	return &widgets{
+		dst: dst, th: g.theme(), st: &g.macro.ui,
+		x: r.Min.X + ui(editorPad), y: r.Min.Y + ui(editorPad), w: r.Dx() - 2*ui(editorPad),
+	}
+}
+
+func (g *Game) macroNameLayout(u *widgets) (save bool) {
+	m := &g.macro
+	u.label(fmt.Sprintf(tr("Save macro (%d actions)"), len(m.actions)))
+	s := string(m.name)
+	if m.blink/30%2 == 0 {
+		s += "|"
+	}
+	u.field(tr("Name"), s, true, false)
+	save = u.button(tr("Save"))
+	u.label(tr("Enter: save, Esc: discard"))
+	return save
+}
+
+// updateMacroName забирает клавиатуру, пока вводится имя макроса.
+func (g *Game) updateMacroName() {
+	m := &g.macro
+	m.blink++
+
+	u := g.macroNameWidgets(nil)
+	u.mx, u.my = g.cursor()
+	u.click = mouseJustPressed(ebiten.MouseButtonLeft)
+	save := g.macroNameLayout(u)
+
+	switch {
+	case keyJustPressed(ebiten.KeyEscape):
+		m.actions, m.naming = nil, false
+	case save || keyJustPressed(ebiten.KeyEnter) || keyJustPressed(ebiten.KeyNumpadEnter):
+		g.saveRecordedMacro()
+	}
+	if !m.naming {
+		g.lastLeft = mousePressed(ebiten.MouseButtonLeft) // щелчок не ставит заряд
+		return
+	}
+
+	for _, c := range inputChars() {
+		if unicode.IsPrint(c) && len(m.name) < macroNameMaxLen {
+			m.name = append(m.name, c)
+		}
+	}
+	if keyRepeated(ebiten.KeyBackspace) && len(m.name) > 0 {
+		m.name = m.name[:len(m.name)-1]
+	}
+	if len(inputChars()) > 0 || keyPressed(ebiten.KeyBackspace) {
+		m.blink = 0
+	}
+}
+
+func (g *Game) drawMacroName(screen *ebiten.Image) {
+	if !g.macro.naming {
+		return
+	}
+	th := g.theme()
+	r := g.macroNameRect()
+	vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), withAlpha(th.Panel, 240), false)
+	vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, th.Border, false)
+	g.macroNameLayout(g.macroNameWidgets(screen))
+}
+
+func (g *Game) recordEdit(kind EditKind, x, y, q float64) {
+	m := &g.macro
+	if !m.recording {
+		return
+	}
+
+	if len(m.actions) == 0 {
+		m.anchor = Vec2{X: x, Y: y}
+	}
+	m.actions = append(m.actions, EditAction{
+		Kind: kind,
+		DX:   x - m.anchor.X,
+		DY:   y - m.anchor.Y,
+		Q:    q,
+	})
+}
+
+func (g *Game) cycleMacro() {
+	m := &g.macro
+	if len(m.macros) == 0 {
+		return
+	}
+	m.selected = (m.selected + 1) % len(m.macros)
+}
+
+func (g *Game) replayMacroAtMouse() {
+	m := &g.macro
+	if m.recording || len(m.macros) == 0 {
+		return
+	}
+
+	x, y := g.cursorWorld()
+	g.checkpoint()
+	for _, a := range m.macros[m.selected].Actions {
+		switch a.Kind {
+		case EditAddCharge:
+			g.addCharge(x+a.DX, y+a.DY, a.Q)
+		case EditAdjustQ, EditFlipSign:
+			i := g.chargeAt(x+a.DX, y+a.DY)
+			if i < 0 {
+				continue
+			}
+			c := &g.charges[i]
+			if a.Kind == EditFlipSign {
+				c.Q = -c.Q
+			} else {
+				c.Q = stepQ(c.Q, a.Q)
+			}
+			g.dirty = true
+			g.equilibrium = false
+			g.conservation.resetSystem()
+		}
+	}
+}
+
+// stepQ меняет заряд на d; через ноль проскакивает: нулевой заряд не нужен.
+func stepQ(q, d float64) float64 {
+	q += d
+	if math.Abs(q) < 1e-9 {
+		q += d
+	}
+	return q
+}
+
+func (m *macroRecorder) status() string {
+	rec, play := keyLabel(keyMacroRecord), keyLabel(keyMacroReplay)
+	switch {
+	case m.recording:
+		return fmt.Sprintf(tr("%s: stop recording (%d actions)"), rec, len(m.actions))
+	case len(m.macros) == 0:
+		return rec + tr(": record macro")
+	default:
+		return fmt.Sprintf(tr("%s: record macro, %s: replay %s, Shift+%s: next macro"), rec, play, m.macros[m.selected].Name, play)
+	}
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Магнитостатика: прямые провода с током, перпендикулярные экрану.
+// Поле провода B = μI/(2πr) закручено вокруг него в плоскости экрана.
+// Сила q·v×B при скорости в плоскости направлена вдоль провода, поэтому у
+// пробной частицы учитывается скорость VZ; поля считаются однородными
+// вдоль z (положение частицы по z не отслеживается). Система координат
+// правая: x вправо, y вниз, z в экран.
+
+const (
+	muConst         = 60.0 // магнитная постоянная в единицах симуляции
+	wireRadius      = 6.0
+	magneticSeedR   = 20.0 // радиус первой затравки силовой линии B
+	magneticSeeds   = 4    // затравок на провод, радиус удваивается
+	magneticMaxStep = 3000
+)
+
+// Wire — провод с током I; I > 0 — ток на наблюдателя (из экрана).
+type Wire struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	I float64 `json:"i"`
+}
+
+func (g *Game) addWireAtMouse(i float64) {
+	x, y := g.cursorWorld()
+	g.checkpoint()
+	g.wires = append(g.wires, Wire{X: x, Y: y, I: i})
+	g.dirty = true
+}
+
+func (g *Game) magneticAt(x, y float64) (float64, float64) {
+	var Bx, By float64
+	for _, w := range g.wires {
+		dx := x - w.X
+		dy := y - w.Y
+		r2 := math.Max(dx*dx+dy*dy, minR2)
+
+		// ток из экрана направлен по -z: B = μI/(2π r²) · (-ẑ × r) = μI/(2π r²) · (dy, -dx),
+		// то есть против часовой стрелки для наблюдателя
+		f := muConst * w.I / (2 * math.Pi * r2)
+		Bx += f * dy
+		By += -f * dx
+	}
+	return Bx, By
+}
+
+// lorentzAccel добавляет к электрическому ускорению (ax, ay) магнитную
+// часть q/m·v×B с учётом скорости вдоль провода.
+func (g *Game) lorentzAccel(p *Particle, ax, ay float64) (float64, float64, float64) {
+	if len(g.wires) == 0 {
+		return ax, ay, 0
+	}
+	Bx, By := g.magneticAt(p.X, p.Y)
+	qm := testCharge / testMass
+	return ax - qm*p.VZ*By, ay + qm*p.VZ*Bx, qm * (p.VX*By - p.VY*Bx)
+}
+
+// traceMagneticLine ведёт замкнутую линию B до возвращения к началу.
+func (g *Game) traceMagneticLine(x, y float64) []Vec2 {
+	sx, sy := x, y
+	points := []Vec2{{X: x, Y: y}}
+
+	for i := 0; i < magneticMaxStep; i++ {
+		// средняя точка (RK2), иначе окружности раскручиваются в спираль
+		Bx, By := g.magneticAt(x, y)
+		B := math.Hypot(Bx, By)
+		if B < 1e-9 {
+			break
+		}
+		mx := x + Bx/B*fieldLineStep/2
+		my := y + By/B*fieldLineStep/2
+		Bx, By = g.magneticAt(mx, my)
+		B = math.Hypot(Bx, By)
+		if B < 1e-9 {
+			break
+		}
+		x += Bx / B * fieldLineStep
+		y += By / B * fieldLineStep
+		points = append(points, Vec2{X: x, Y: y})
+
+		if i > 10 && math.Hypot(x-sx, y-sy) < fieldLineStep {
+			points = append(points, Vec2{X: sx, Y: sy})
+			break
+		}
+		if !inWorld(x, y) {
+			break
+		}
+	}
+	return points
+}
+
+func (g *Game) recomputeMagneticLines() {
+	g.magneticLine = g.magneticLine[:0]
+	for _, w := range g.wires {
+		r := magneticSeedR
+		for k := 0; k < magneticSeeds; k++ {
+			g.magneticLine = append(g.magneticLine, g.traceMagneticLine(w.X+r, w.Y))
+			r *= 2
+		}
+	}
+}
+
+func (g *Game) drawMagnetic(screen *ebiten.Image) {
+	col := color.RGBA{255, 170, 60, 160}
+	var s splinePath
+	for _, line := range g.magneticLine {
+		for _, p := range line {
+			s.add(g.cam.toScreen(p.X, p.Y))
+		}
+		s.end()
+	}
+	s.stroke(screen, g.cam.px(g.lines.FieldWidth()), col, g.lines.Antialias)
+
+	r, lw := g.cam.px(wireRadius), g.cam.px(2)
+	for _, w := range g.wires {
+		x, y := g.cam.toScreen(w.X, w.Y)
+		vector.DrawFilledCircle(screen, x, y, r, color.RGBA{40, 40, 40, 255}, false)
+		vector.StrokeCircle(screen, x, y, r, lw, color.RGBA{255, 170, 60, 255}, false)
+		if w.I > 0 {
+			// ток из экрана: точка
+			vector.DrawFilledCircle(screen, x, y, lw, color.RGBA{255, 170, 60, 255}, false)
+		} else {
+			// ток в экран: крестик
+			d := r * 0.6
+			vector.StrokeLine(screen, x-d, y-d, x+d, y+d, lw, color.RGBA{255, 170, 60, 255}, false)
+			vector.StrokeLine(screen, x-d, y+d, x+d, y-d, lw, color.RGBA{255, 170, 60, 255}, false)
+		}
+	}
+}
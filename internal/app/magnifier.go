@@ -0,0 +1,153 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/colormap"
+)
+
+// Лупа: пока зажата клавиша, в углу показывается увеличенная окрестность
+// курсора. Фон и линии тока в ней считаются заново с шагом в magZoom раз
+// мельче, поэтому седловые и нулевые точки между зарядами видны без
+// ступенек. Пересчёт — только когда сдвинулся курсор или сама сцена.
+
+const (
+	magSize     = 200 // сторона врезки, пикс
+	magZoom     = 4.0
+	magSeeds    = 5 // линий тока на сторону врезки
+	magMaxSteps = 400
+)
+
+type magnifier struct {
+	on     bool
+	stale  bool
+	cam    camera // вид внутри врезки
+	img    *ebiten.Image
+	view   *ebiten.Image
+	stream [][]Vec2
+}
+
+// magnifierFrame — экранное положение врезки: в правом нижнем углу левее легенды.
+func (g *Game) magnifierFrame() (x, y float32) {
+	return float32(g.cam.W - magSize - ui(80)), float32(g.cam.H - magSize - statusBarH() - ui(10))
+}
+
+func (g *Game) updateMagnifier() {
+	m := &g.magnifier
+	if !m.on {
+		return
+	}
+	x, y := g.cursorWorld()
+	lc := camera{X: x, Y: y, Zoom: g.cam.Zoom * magZoom, W: magSize, H: magSize, Scale: 1}
+	if m.img != nil && !m.stale && lc == m.cam {
+		return
+	}
+	m.cam, m.stale = lc, false
+
+	pix := make([]byte, 4*magSize*magSize)
+	for py := 0; py < magSize; py++ {
+		for px := 0; px < magSize; px++ {
+			c := g.magnifierColor(lc.toWorld(float64(px), float64(py)))
+			i := 4 * (py*magSize + px)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = c.R, c.G, c.B, 255
+		}
+	}
+	if m.img == nil {
+		m.img = ebiten.NewImage(magSize, magSize)
+		m.view = ebiten.NewImage(magSize, magSize)
+	}
+	m.img.WritePixels(pix)
+
+	m.stream = m.stream[:0]
+	for i := 0; i < magSeeds; i++ {
+		for j := 0; j < magSeeds; j++ {
+			sx, sy := lc.toWorld((float64(i)+0.5)*magSize/magSeeds, (float64(j)+0.5)*magSize/magSeeds)
+			m.stream = append(m.stream, g.traceMagnified(sx, sy, -1), g.traceMagnified(sx, sy, +1))
+		}
+	}
+}
+
+func (g *Game) magnifierColor(x, y float64) color.RGBA {
+	if g.bgMode == BackgroundPotential {
+		V := g.slicePotential(x, y)
+		return colormap.Coolwarm.Signed(math.Copysign(g.potScale.at(math.Abs(V)), V))
+	}
+	return g.heatColor(math.Hypot(g.sliceField(x, y)))
+}
+
+// traceMagnified — линия тока внутри врезки с уменьшенным шагом.
+func (g *Game) traceMagnified(x, y, dir float64) []Vec2 {
+	lc := g.magnifier.cam
+	h := fieldLineStep / magZoom
+	pts := []Vec2{{X: x, Y: y}}
+	for i := 0; i < magMaxSteps && lc.inView(x, y, 0); i++ {
+		Ex, Ey := g.sliceField(x, y)
+		E := math.Hypot(Ex, Ey)
+		if E < 1e-9 {
+			break
+		}
+		x += Ex / E * dir * h
+		y += Ey / E * dir * h
+		pts = append(pts, Vec2{X: x, Y: y})
+	}
+	return pts
+}
+
+func (g *Game) drawMagnifier(screen *ebiten.Image) {
+	m := &g.magnifier
+	if !m.on || m.img == nil {
+		return
+	}
+	th := g.theme()
+	lc := m.cam
+	v := m.view
+	v.DrawImage(m.img, nil)
+
+	var fine splinePath
+	for _, line := range m.stream {
+		for _, p := range line {
+			fine.add(lc.toScreen(p.X, p.Y))
+		}
+		fine.end()
+	}
+	fine.stroke(v, 1, withAlpha(th.FieldLines, 110), true)
+
+	var s splinePath
+	for _, line := range g.fieldLines {
+		for _, p := range line {
+			s.add(lc.toScreen(p.X, p.Y))
+		}
+		s.end()
+	}
+	s.stroke(v, g.lines.FieldWidth(), th.FieldLines, true)
+
+	for _, c := range g.sliceCharges() {
+		x, y := lc.toScreen(c.X, c.Y)
+		g.drawChargeGlyph(v, x, y, float32(chargeGlyphRadius(c.Q)*magZoom), c.Q, 255)
+	}
+	vector.StrokeLine(v, magSize/2-6, magSize/2, magSize/2+6, magSize/2, 1, th.HUD, false)
+	vector.StrokeLine(v, magSize/2, magSize/2-6, magSize/2, magSize/2+6, 1, th.HUD, false)
+
+	// рамка увеличиваемой области у курсора
+	cx, cy := g.cam.toScreen(lc.X, lc.Y)
+	r := float32(magSize / magZoom / 2)
+	vector.StrokeRect(screen, cx-r, cy-r, 2*r, 2*r, 1, th.Border, false)
+
+	x, y := g.magnifierFrame()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(v, op)
+	vector.StrokeRect(screen, x, y, magSize, magSize, 1, th.Border, false)
+
+	Ex, Ey := g.sliceField(lc.X, lc.Y)
+	u := g.units
+	label := fmt.Sprintf("x%.0f  |E| %.3g%s  V %+.3g%s", magZoom, u.field(math.Hypot(Ex, Ey)), u.fieldUnit(),
+		u.potential(g.slicePotential(lc.X, lc.Y)), u.potentialUnit())
+	text.Draw(screen, label, uiFace, int(x), int(y)-ui(6), th.HUD)
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Всплывающее меню: список пунктов у курсора, щелчок по пункту выполняет
+// действие, щелчок мимо или Esc закрывает. Пункт без действия — заголовок.
+
+const (
+	menuLineH = 18
+	menuPad   = 6
+
+	chargeQStep     = 0.5
+	duplicateOffset = 30.0 // сдвиг копии заряда, пикс
+)
+
+type menuItem struct {
+	label    string
+	action   func()
+	keepOpen bool // меню не закрывается, например при подстройке Q
+}
+
+type popupMenu struct {
+	open  bool
+	x, y  float32
+	items []menuItem
+	hover int
+}
+
+func (m *popupMenu) openAt(x, y int, items []menuItem) {
+	m.open, m.items, m.hover = true, items, -1
+	m.x, m.y = float32(x), float32(y)
+}
+
+func (m *popupMenu) size() (w, h float32) {
+	n := 0
+	for _, it := range m.items {
+		n = max(n, textWidth(it.label))
+	}
+	return float32(n + 2*ui(menuPad)), float32(len(m.items)*ui(menuLineH) + ui(menuPad))
+}
+
+// itemAt — пункт под точкой экрана или -1.
+func (m *popupMenu) itemAt(x, y int) int {
+	w, h := m.size()
+	pad := float32(ui(menuPad))
+	fx, fy := float32(x)-m.x, float32(y)-m.y-pad/2
+	if fx < 0 || fx > w || fy < 0 || fy >= h-pad {
+		return -1
+	}
+	return int(fy) / ui(menuLineH)
+}
+
+// update обрабатывает мышь вместо сцены; click — нажатие в этом кадре.
+func (m *popupMenu) update(x, y int, click bool) {
+	m.hover = m.itemAt(x, y)
+	if keyJustPressed(ebiten.KeyEscape) {
+		m.open = false
+		return
+	}
+	if !click {
+		return
+	}
+	if m.hover < 0 {
+		m.open = false
+		return
+	}
+	it := m.items[m.hover]
+	if it.action == nil {
+		return
+	}
+	it.action()
+	m.open = it.keepOpen
+}
+
+func (m *popupMenu) draw(screen *ebiten.Image, th *Theme) {
+	if !m.open {
+		return
+	}
+	w, h := m.size()
+	lh, pad := ui(menuLineH), ui(menuPad)
+	vector.DrawFilledRect(screen, m.x, m.y, w, h, withAlpha(th.Panel, 235), false)
+	vector.StrokeRect(screen, m.x, m.y, w, h, 1, th.Border, false)
+	for i, it := range m.items {
+		top := m.y + float32(pad/2+i*lh)
+		col := th.HUD
+		if it.action == nil {
+			col = withAlpha(th.HUD, 150)
+		} else if i == m.hover {
+			vector.DrawFilledRect(screen, m.x+2, top, w-4, float32(lh), withAlpha(th.Border, 120), false)
+		}
+		text.Draw(screen, it.label, uiFace, int(m.x)+pad, baseline(int(top), lh), col)
+	}
+}
+
+// openChargeMenu открывает меню заряда под курсором, если он есть.
+func (g *Game) openChargeMenu() {
+	i := g.hovered()
+	if i < 0 {
+		return
+	}
+	x, y := g.cursor()
+	g.menu.openAt(x, y, g.chargeMenuItems(i))
+}
+
+func (g *Game) chargeMenuItems(i int) []menuItem {
+	c := g.charges[i]
+	pin := tr("Pin")
+	if c.Pinned {
+		pin = tr("Unpin")
+	}
+	// меню закрывается или пересобирается после каждого действия, так что
+	// индекс i остаётся верным, пока оно открыто
+	edit := func(f func()) func() {
+		return func() {
+			if i >= len(g.charges) {
+				return
+			}
+			g.checkpoint()
+			f()
+			g.dirty = true
+			g.equilibrium = false
+			g.conservation.resetSystem()
+			if g.menu.open && i < len(g.charges) {
+				g.menu.items = g.chargeMenuItems(i)
+			}
+		}
+	}
+	adjustQ := func(d float64) func() {
+		return edit(func() {
+			c := &g.charges[i]
+			c.Q = stepQ(c.Q, d)
+			g.recordEdit(EditAdjustQ, c.X, c.Y, d)
+		})
+	}
+	return []menuItem{
+		{label: fmt.Sprintf(tr("Charge %s"), formatQ(c.Q))},
+		{label: fmt.Sprintf("Q + %g", chargeQStep), action: adjustQ(+chargeQStep), keepOpen: true},
+		{label: fmt.Sprintf("Q - %g", chargeQStep), action: adjustQ(-chargeQStep), keepOpen: true},
+		{label: tr("Flip sign"), action: edit(func() {
+			c := &g.charges[i]
+			c.Q = -c.Q
+			g.recordEdit(EditFlipSign, c.X, c.Y, 0)
+		}), keepOpen: true},
+		{label: pin, action: edit(func() {
+			ch := &g.charges[i]
+			ch.Pinned, ch.VX, ch.VY = !ch.Pinned, 0, 0
+		})},
+		{label: tr("Duplicate"), action: edit(func() {
+			d := g.charges[i]
+			d.X += duplicateOffset / g.cam.Zoom
+			d.Y += duplicateOffset / g.cam.Zoom
+			d.VX, d.VY = 0, 0
+			g.charges = append(g.charges, d)
+		})},
+		{label: tr("Delete"), action: edit(func() {
+			g.charges = slices.Delete(g.charges, i, i+1)
+			g.menu.open = false
+		})},
+	}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Миникарта в углу: все заряды и прямоугольник текущего вида. Видна, только
+// когда сцена не помещается на экран; клик или перетаскивание по ней
+// переносит центр камеры в указанную точку.
+
+const (
+	minimapW   = 180 // при масштабе интерфейса 1
+	minimapH   = 120
+	minimapPad = 0.1 // запас вокруг сцены, доля размера
+)
+
+type minimap struct {
+	on   bool
+	drag bool
+
+	// отображение мира: левый верхний угол и пикселей миникарты на единицу
+	x0, y0, scale float64
+}
+
+// minimapFrame подбирает отображение так, чтобы в карту вошли заряды и вид.
+func (g *Game) minimapFrame() {
+	vx0, vy0 := g.cam.toWorld(0, 0)
+	vx1, vy1 := g.cam.toWorld(float64(g.cam.W), float64(g.cam.H))
+	minX, minY, maxX, maxY := vx0, vy0, vx1, vy1
+	for _, c := range g.charges {
+		minX, maxX = math.Min(minX, c.X), math.Max(maxX, c.X)
+		minY, maxY = math.Min(minY, c.Y), math.Max(maxY, c.Y)
+	}
+	padX, padY := (maxX-minX)*minimapPad, (maxY-minY)*minimapPad
+	minX, maxX = minX-padX, maxX+padX
+	minY, maxY = minY-padY, maxY+padY
+
+	m := &g.minimap
+	w, h := float64(g.hud.minimap.Dx()), float64(g.hud.minimap.Dy())
+	m.scale = math.Min(w/(maxX-minX), h/(maxY-minY))
+	m.x0 = (minX+maxX)/2 - w/2/m.scale
+	m.y0 = (minY+maxY)/2 - h/2/m.scale
+}
+
+// minimapVisible: есть заряды за краем экрана.
+func (g *Game) minimapVisible() bool {
+	if !g.minimap.on || g.hideHUD || g.cut.active || g.surfaceView {
+		return false
+	}
+	for _, c := range g.charges {
+		if !g.cam.inView(c.X, c.Y, -chargeRadius) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) overMinimap(x, y int) bool {
+	return image.Pt(x, y).In(g.hud.minimap)
+}
+
+// updateMinimap вызывается из updateCamera: пока тянем по карте, её масштаб
+// заморожен, иначе прямоугольник вида «убегал» бы из-под курсора.
+func (g *Game) updateMinimap() {
+	m := &g.minimap
+	x, y := g.cursor()
+	if mouseJustPressed(ebiten.MouseButtonLeft) && g.overMinimap(x, y) && g.minimapVisible() {
+		m.drag = true
+	}
+	if !mousePressed(ebiten.MouseButtonLeft) {
+		m.drag = false
+	}
+	if !m.drag {
+		g.minimapFrame()
+		return
+	}
+	g.cam.X = m.x0 + float64(x-g.hud.minimap.Min.X)/m.scale
+	g.cam.Y = m.y0 + float64(y-g.hud.minimap.Min.Y)/m.scale
+}
+
+func (g *Game) toMinimap(x, y float64) (float32, float32) {
+	m, o := &g.minimap, g.hud.minimap.Min
+	return float32(float64(o.X) + (x-m.x0)*m.scale), float32(float64(o.Y) + (y-m.y0)*m.scale)
+}
+
+func (g *Game) drawMinimap(screen *ebiten.Image) {
+	if !g.minimapVisible() {
+		return
+	}
+	th := g.theme()
+	r := g.hud.minimap
+	mx, my, mw, mh := float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy())
+
+	vector.DrawFilledRect(screen, mx, my, mw, mh, th.Panel, false)
+	vector.StrokeRect(screen, mx, my, mw, mh, 1, th.Border, false)
+
+	for _, c := range g.charges {
+		x, y := g.toMinimap(c.X, c.Y)
+		col := th.Positive
+		if c.Q < 0 {
+			col = th.Negative
+		}
+		vector.DrawFilledCircle(screen, x, y, uif(2), col, false)
+	}
+
+	x0, y0 := g.toMinimap(g.cam.toWorld(0, 0))
+	x1, y1 := g.toMinimap(g.cam.toWorld(float64(g.cam.W), float64(g.cam.H)))
+	// при заморозке масштаба вид может выйти за карту — прижимаем рамку
+	x0, y0 = max(x0, mx), max(y0, my)
+	x1, y1 = min(x1, mx+mw), min(y1, my+mh)
+	if x1 > x0 && y1 > y0 {
+		vector.StrokeRect(screen, x0, y0, x1-x0, y1-y0, 1, th.HUD, false)
+	}
+}
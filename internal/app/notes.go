@@ -0,0 +1,153 @@
+package app
+
+import (
+	"image"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Текстовые заметки на сцене («седловая точка», «E = 0»): привязаны к
+// мировой точке, двигаются вместе с видом и сохраняются со сценой.
+// С инструментом заметок щелчок на пустом месте ставит новую, щелчок по
+// заметке правит её, правая кнопка убирает. Пока заметка набирается,
+// клавиатура целиком уходит в текст: Enter или щелчок мышью — готово,
+// Esc — отмена.
+
+const (
+	noteMaxLen = 80
+	notePad    = 3
+)
+
+type Note struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Text string  `json:"text"`
+}
+
+type noteTool struct {
+	tool  bool
+	items []Note
+
+	editing bool
+	index   int
+	orig    string // текст до правки, для Esc; у новой заметки пуст
+	blink   int
+}
+
+func (g *Game) toggleNoteTool() {
+	on := !g.notes.tool
+	g.toolsOff()
+	g.notes.tool = on
+}
+
+// noteRect — рамка подписи заметки на экране.
+func (g *Game) noteRect(n Note) image.Rectangle {
+	x, y := g.cam.toScreen(n.X, n.Y)
+	w := max(textWidth(n.Text), textWidth(" "))
+	pad := ui(notePad)
+	return image.Rect(int(x), int(y)-lineHeight()-2*pad, int(x)+w+2*pad, int(y))
+}
+
+func (g *Game) noteAt(x, y int) int {
+	for i := len(g.notes.items) - 1; i >= 0; i-- {
+		if image.Pt(x, y).In(g.noteRect(g.notes.items[i]).Inset(-2)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *Game) updateNotes(leftNow, rightNow bool) {
+	n := &g.notes
+	x, y := g.cursor()
+	switch {
+	case leftNow && !g.lastLeft:
+		if i := g.noteAt(x, y); i >= 0 {
+			g.editNote(i)
+			return
+		}
+		wx, wy := g.snap.at(g.cursorWorld())
+		n.items = append(n.items, Note{X: wx, Y: wy})
+		g.editNote(len(n.items) - 1)
+	case rightNow && !g.lastRight:
+		if i := g.noteAt(x, y); i >= 0 {
+			n.items = slices.Delete(n.items, i, i+1)
+		}
+	}
+}
+
+func (g *Game) editNote(i int) {
+	n := &g.notes
+	n.editing, n.index, n.blink = true, i, 0
+	n.orig = n.items[i].Text
+}
+
+// updateNoteEntry забирает ввод, пока набирается заметка.
+func (g *Game) updateNoteEntry() {
+	n := &g.notes
+	note := &n.items[n.index]
+	n.blink++
+
+	switch {
+	case keyJustPressed(ebiten.KeyEscape):
+		note.Text = n.orig
+		g.finishNote()
+		return
+	case keyJustPressed(ebiten.KeyEnter), keyJustPressed(ebiten.KeyNumpadEnter),
+		mouseJustPressed(ebiten.MouseButtonLeft), mouseJustPressed(ebiten.MouseButtonRight):
+		g.finishNote()
+		return
+	}
+
+	r := []rune(note.Text)
+	for _, c := range inputChars() {
+		if unicode.IsPrint(c) && len(r) < noteMaxLen {
+			r = append(r, c)
+		}
+	}
+	if keyRepeated(ebiten.KeyBackspace) && len(r) > 0 {
+		r = r[:len(r)-1]
+	}
+	note.Text = string(r)
+	if len(inputChars()) > 0 || keyPressed(ebiten.KeyBackspace) {
+		n.blink = 0 // курсор виден, пока печатают
+	}
+}
+
+// finishNote завершает правку; пустая заметка убирается.
+func (g *Game) finishNote() {
+	n := &g.notes
+	n.editing = false
+	note := &n.items[n.index]
+	note.Text = strings.TrimSpace(note.Text)
+	if note.Text == "" {
+		n.items = slices.Delete(n.items, n.index, n.index+1)
+	}
+	g.lastLeft = mousePressed(ebiten.MouseButtonLeft) // щелчок не ставит заряд
+	g.lastRight = mousePressed(ebiten.MouseButtonRight)
+}
+
+func (g *Game) drawNotes(screen *ebiten.Image) {
+	th := g.theme()
+	n := &g.notes
+	for i, note := range n.items {
+		r := g.noteRect(note)
+		x, y := g.cam.toScreen(note.X, note.Y)
+		vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), th.Panel, false)
+		vector.DrawFilledCircle(screen, x, y, 2, th.HUD, true)
+		s := note.Text
+		if n.editing && i == n.index {
+			vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, th.Border, false)
+			if n.blink/30%2 == 0 {
+				s += "|"
+			}
+		}
+		pad := ui(notePad)
+		text.Draw(screen, s, uiFace, r.Min.X+pad, baseline(r.Min.Y+pad, lineHeight()), th.HUD)
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Короткие уведомления о результате действий (экспорт, сохранение).
+
+const noticeFrames = 240
+
+func (g *Game) notify(msg string) {
+	g.notice = msg
+	g.noticeLeft = noticeFrames
+}
+
+func (g *Game) updateNotice() {
+	if g.noticeLeft > 0 {
+		g.noticeLeft--
+	}
+}
+
+func (g *Game) drawNotice(screen *ebiten.Image) {
+	if g.noticeLeft == 0 {
+		return
+	}
+	a := uint8(255 * min(1, float64(g.noticeLeft)/60))
+	text.Draw(screen, g.notice, uiFace, ui(hintX), hintRow(4), withAlpha(g.theme().HUD, a))
+}
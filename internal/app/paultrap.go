@@ -0,0 +1,249 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Ловушка Пауля: линейный квадруполь с потенциалом
+// Φ = (U + V cos Ωt)(x² - y²) / (2r0²). В безразмерных единицах
+// (Q/m = 1, r0 = 1) параметры Матье a = 4U/Ω², q = 2V/Ω².
+
+const (
+	paulSubsteps   = 8   // шагов интегрирования за кадр
+	paulStepsPerRF = 64  // шагов на период ВЧ поля
+	paulTrailLen   = 600 // длина следа иона
+	paulScale      = 180 // пикселей на r0
+
+	paulDiagW  = 240 // размер диаграммы стабильности в пикселях
+	paulDiagH  = 200
+	paulQMax   = 1.0
+	paulAMin   = -0.4
+	paulAMax   = 0.3
+	mathieuRK4 = 200 // шагов RK4 на период для матрицы монодромии
+)
+
+type paulScenario struct {
+	U, V, Omega float64
+
+	t      float64
+	x, y   float64
+	vx, vy float64
+	lost   bool
+	trail  []Vec2
+
+	diagram *ebiten.Image
+}
+
+func newPaulScenario() *paulScenario {
+	s := &paulScenario{U: 0, V: 0.3, Omega: 1}
+	s.reset()
+	return s
+}
+
+func (s *paulScenario) Name() string { return tr("Paul trap (linear quadrupole)") }
+
+func (s *paulScenario) reset() {
+	s.t = 0
+	s.x, s.y = 0.25, 0.15
+	s.vx, s.vy = 0, 0.01
+	s.lost = false
+	s.trail = s.trail[:0]
+}
+
+func (s *paulScenario) mathieu() (float64, float64) {
+	w2 := s.Omega * s.Omega
+	return 4 * s.U / w2, 2 * s.V / w2
+}
+
+func (s *paulScenario) accel(x, y, t float64) (float64, float64) {
+	k := s.U + s.V*math.Cos(s.Omega*t)
+	return -k * x, k * y
+}
+
+func (s *paulScenario) Update() {
+	if keyPressed(ebiten.KeyUp) {
+		s.V += 0.002
+	}
+	if keyPressed(ebiten.KeyDown) {
+		s.V = math.Max(0, s.V-0.002)
+	}
+	if keyPressed(ebiten.KeyRight) {
+		s.U += 0.001
+	}
+	if keyPressed(ebiten.KeyLeft) {
+		s.U -= 0.001
+	}
+	if keyJustPressed(ebiten.KeyW) {
+		s.Omega *= 1.1
+	}
+	if keyJustPressed(ebiten.KeyS) {
+		s.Omega /= 1.1
+	}
+	if keyJustPressed(ebiten.KeyR) {
+		s.reset()
+	}
+
+	if s.lost {
+		return
+	}
+
+	dt := 2 * math.Pi / s.Omega / paulStepsPerRF
+	for i := 0; i < paulSubsteps; i++ {
+		ax, ay := s.accel(s.x, s.y, s.t)
+		s.vx += ax * dt
+		s.vy += ay * dt
+		s.x += s.vx * dt
+		s.y += s.vy * dt
+		s.t += dt
+
+		if math.Abs(s.x) > 1 || math.Abs(s.y) > 1 {
+			s.lost = true
+			break
+		}
+	}
+
+	s.trail = append(s.trail, Vec2{X: s.x, Y: s.y})
+	if len(s.trail) > paulTrailLen {
+		s.trail = s.trail[1:]
+	}
+}
+
+// mathieuStable проверяет устойчивость уравнения Матье
+// d²u/dξ² + (a - 2q cos 2ξ) u = 0 по следу матрицы монодромии за период π.
+func mathieuStable(a, q float64) bool {
+	f := func(xi, u, du float64) (float64, float64) {
+		return du, -(a - 2*q*math.Cos(2*xi)) * u
+	}
+
+	solve := func(u, du float64) (float64, float64) {
+		h := math.Pi / mathieuRK4
+		xi := 0.0
+		for i := 0; i < mathieuRK4; i++ {
+			k1u, k1d := f(xi, u, du)
+			k2u, k2d := f(xi+h/2, u+h/2*k1u, du+h/2*k1d)
+			k3u, k3d := f(xi+h/2, u+h/2*k2u, du+h/2*k2d)
+			k4u, k4d := f(xi+h, u+h*k3u, du+h*k3d)
+			u += h / 6 * (k1u + 2*k2u + 2*k3u + k4u)
+			du += h / 6 * (k1d + 2*k2d + 2*k3d + k4d)
+			xi += h
+		}
+		return u, du
+	}
+
+	u1, _ := solve(1, 0)
+	_, du2 := solve(0, 1)
+	return math.Abs(u1+du2) < 2
+}
+
+func (s *paulScenario) buildDiagram() *ebiten.Image {
+	pix := make([]byte, 4*paulDiagW*paulDiagH)
+	for py := 0; py < paulDiagH; py++ {
+		a := paulAMax - (paulAMax-paulAMin)*float64(py)/paulDiagH
+		for px := 0; px < paulDiagW; px++ {
+			q := paulQMax * float64(px) / paulDiagW
+
+			sx := mathieuStable(a, q)
+			sy := mathieuStable(-a, q)
+
+			i := 4 * (py*paulDiagW + px)
+			switch {
+			case sx && sy:
+				pix[i], pix[i+1], pix[i+2] = 60, 160, 80
+			case sx || sy:
+				pix[i], pix[i+1], pix[i+2] = 40, 60, 90
+			default:
+				pix[i], pix[i+1], pix[i+2] = 20, 20, 28
+			}
+			pix[i+3] = 255
+		}
+	}
+
+	img := ebiten.NewImage(paulDiagW, paulDiagH)
+	img.WritePixels(pix)
+	return img
+}
+
+func (s *paulScenario) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 14, 255})
+
+	const cx, cy = 300.0, 320.0
+
+	// электроды-гиперболы x² - y² = ±r0²
+	k := s.U + s.V*math.Cos(s.Omega*s.t)
+	for _, e := range []struct {
+		sx, sy float64
+		sign   float64
+	}{{1, 0, 1}, {-1, 0, 1}, {0, 1, -1}, {0, -1, -1}} {
+		col := plateColor(e.sign * k)
+		var prev Vec2
+		for i := -20; i <= 20; i++ {
+			t := float64(i) / 20 * 1.2
+			u, w := math.Cosh(t), math.Sinh(t)
+			var p Vec2
+			if e.sx != 0 {
+				p = Vec2{X: e.sx * u, Y: w}
+			} else {
+				p = Vec2{X: w, Y: e.sy * u}
+			}
+			if i > -20 {
+				vector.StrokeLine(screen,
+					float32(cx+prev.X*paulScale), float32(cy-prev.Y*paulScale),
+					float32(cx+p.X*paulScale), float32(cy-p.Y*paulScale),
+					3, col, false)
+			}
+			prev = p
+		}
+	}
+
+	for i := 1; i < len(s.trail); i++ {
+		a := uint8(40 + 200*i/len(s.trail))
+		vector.StrokeLine(screen,
+			float32(cx+s.trail[i-1].X*paulScale), float32(cy-s.trail[i-1].Y*paulScale),
+			float32(cx+s.trail[i].X*paulScale), float32(cy-s.trail[i].Y*paulScale),
+			1, color.RGBA{255, 255, 0, a}, false)
+	}
+	if !s.lost {
+		vector.DrawFilledCircle(screen, float32(cx+s.x*paulScale), float32(cy-s.y*paulScale), 4, color.RGBA{255, 255, 0, 255}, false)
+	}
+
+	if s.diagram == nil {
+		s.diagram = s.buildDiagram()
+	}
+	const dx, dy = 620.0, 120.0
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(dx, dy)
+	screen.DrawImage(s.diagram, op)
+	vector.StrokeRect(screen, dx, dy, paulDiagW, paulDiagH, 1, color.RGBA{150, 150, 160, 255}, false)
+
+	a, q := s.mathieu()
+	mx := dx + float32(q/paulQMax*paulDiagW)
+	my := dy + float32((paulAMax-a)/(paulAMax-paulAMin)*paulDiagH)
+	stable := mathieuStable(a, q) && mathieuStable(-a, q)
+	marker := color.RGBA{255, 80, 80, 255}
+	if stable {
+		marker = color.RGBA{255, 255, 255, 255}
+	}
+	vector.StrokeCircle(screen, mx, my, 5, 2, marker, false)
+
+	face := uiFace
+	text.Draw(screen, tr("Stability diagram (a vs q)"), face, int(dx), int(dy)-ui(8), color.White)
+	text.Draw(screen, tr("q: 0 .. 1"), face, int(dx), int(dy)+paulDiagH+ui(16), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("a: %.1f .. %.1f"), paulAMin, paulAMax), face, int(dx), int(dy)+paulDiagH+ui(32), color.White)
+
+	state := tr("unstable")
+	if stable {
+		state = tr("stable")
+	}
+	text.Draw(screen, s.Name(), face, ui(10), ui(20), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("U = %.3f (Left/Right), V = %.3f (Up/Down), Omega = %.3f (W/S)"), s.U, s.V, s.Omega), face, ui(10), ui(40), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("a = %.3f, q = %.3f: %s confinement. R: relaunch ion, F2: next scene, Esc: editor"), a, q, state), face, ui(10), ui(60), color.White)
+	if s.lost {
+		text.Draw(screen, tr("Ion lost to the electrodes"), face, ui(10), ui(80), color.RGBA{255, 80, 80, 255})
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Пауза и темп физики. Пробел занят перетаскиванием вида, поэтому паузу
+// переключает только короткое нажатие, во время которого вид не тянули.
+// Пока пауза, «.» делает ровно один шаг physicsStep. Отрисовка, камера
+// и правка сцены работают и на паузе.
+//
+// Темп меняет не dt, а число шагов за кадр: накопитель набирает timeScale
+// за кадр и отдаёт целые шаги dynDt, так что интегратор всегда идёт с
+// одним и тем же шагом и при 0.1×, и при 10×.
+
+var timeScales = []float64{0.1, 0.25, 0.5, 1, 2, 4, 10}
+
+const maxStepsPerFrame = 10 // защита от лавины шагов, если кадр затянулся
+
+type pauseState struct {
+	paused   bool
+	panned   bool // за текущее нажатие пробела вид перетаскивали
+	stepOnce bool
+
+	scale int     // индекс в timeScales
+	acc   float64 // накопленные, но не сделанные шаги
+}
+
+func (p *pauseState) timeScale() float64 { return timeScales[p.scale] }
+
+// steps возвращает, сколько шагов физики сделать в этом кадре.
+func (p *pauseState) steps() int {
+	if p.paused {
+		p.acc = 0
+		if p.stepOnce {
+			p.stepOnce = false
+			return 1
+		}
+		return 0
+	}
+	p.acc = math.Min(p.acc+p.timeScale(), maxStepsPerFrame)
+	n := int(p.acc)
+	p.acc -= float64(n)
+	return n
+}
+
+func (g *Game) updatePause() {
+	p := &g.pause
+	if panning() && mousePressed(ebiten.MouseButtonLeft) {
+		p.panned = true
+	}
+	if keyJustReleased(keyPan) {
+		if !p.panned {
+			p.paused = !p.paused
+		}
+		p.panned = false
+	}
+	if p.paused && keyJustPressed(keyStep) {
+		p.stepOnce = true
+	}
+	if keyJustPressed(keySlower) {
+		p.scale = max(0, p.scale-1)
+	}
+	if keyJustPressed(keyFaster) {
+		p.scale = min(len(timeScales)-1, p.scale+1)
+	}
+}
+
+// physicsStep — один шаг всего, что движется: заряды в динамике,
+// пробная частица, плазма и следы.
+func (g *Game) physicsStep() {
+	if g.dynamics {
+		g.stepDynamics()
+		g.recordStrip()
+	} else {
+		g.updateTestParticle()
+	}
+	g.recordTrail()
+
+	g.stepPlasma()
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Заряженный шарик на невесомом стержне рядом с неподвижным зарядом или
+// между пластинами конденсатора: уравнение mL²θ'' = -mgL sinθ + L·(F·t̂) - bθ',
+// где F — электрическая сила, t̂ — касательная к траектории шарика. Расчёт в СИ.
+
+const (
+	coulombK = 8.9875e9 // 1/(4πε0), Н·м²/Кл²
+	gravityG = 9.81
+
+	pendMass     = 0.01 // масса шарика, кг
+	pendLen      = 0.5  // длина стержня, м
+	pendDamping  = 0.4  // коэффициент вязкого трения, 1/с
+	pendSubsteps = 20
+	pendScale    = 500.0 // пикселей на метр
+	pendQMax     = 2e-6  // верхняя граница заряда шарика на графике, Кл
+	pendCurveN   = 80    // точек кривой равновесия
+
+	pendPlateX    = 0.3   // пластины конденсатора на x = ±pendPlateX от подвеса, м
+	pendBallR     = 0.018 // радиус шарика, м
+	pendFieldMax  = 50e3  // предел поля конденсатора, В/м
+	pendFieldStep = 200.0
+)
+
+type pendulumScenario struct {
+	q     float64 // заряд шарика, Кл
+	Q     float64 // неподвижный заряд, Кл
+	d     float64 // горизонтальное смещение неподвижного заряда от вертикали, м
+	theta float64
+	omega float64
+
+	plates bool    // вместо неподвижного заряда однородное поле конденсатора
+	E      float64 // поле между пластинами, В/м, положительное направлено вправо
+
+	time      float64
+	lastCross float64 // момент последнего прохода через равновесие снизу вверх
+	period    float64 // измеренный период, 0 пока не было двух проходов
+
+	curve    []Vec2 // (q, θ_eq) теоретическая кривая
+	measured []Vec2 // (q, θ) установившиеся углы из симуляции
+	settled  bool
+	dirty    bool
+}
+
+func newPendulumScenario() *pendulumScenario {
+	return &pendulumScenario{q: 0.5e-6, Q: 1e-6, d: 0.25, E: 20e3, dirty: true}
+}
+
+func (s *pendulumScenario) Name() string {
+	if s.plates {
+		return tr("Charged pendulum between capacitor plates")
+	}
+	return tr("Charged pendulum near a fixed charge")
+}
+
+func (s *pendulumScenario) ballPos(theta float64) (float64, float64) {
+	return pendLen * math.Sin(theta), -pendLen * math.Cos(theta)
+}
+
+// torque возвращает момент сил относительно точки подвеса при заряде q.
+func (s *pendulumScenario) torque(theta, q float64) float64 {
+	fx, fy := q*s.E, 0.0
+	if !s.plates {
+		bx, by := s.ballPos(theta)
+		dx, dy := bx-s.d, by+pendLen
+		r2 := math.Max(dx*dx+dy*dy, 1e-6)
+		r := math.Sqrt(r2)
+		f := coulombK * q * s.Q / (r2 * r)
+		fx, fy = f*dx, f*dy
+	}
+
+	tx, ty := math.Cos(theta), math.Sin(theta)
+	return -pendMass*gravityG*pendLen*math.Sin(theta) + pendLen*(fx*tx+fy*ty)
+}
+
+// equilibriumAngle ищет устойчивое положение равновесия, ближайшее к
+// вертикали: смена знака момента с «+» на «−» при росте θ.
+func (s *pendulumScenario) equilibriumAngle(q float64) float64 {
+	const steps = 720
+	best := math.NaN()
+	prevT := s.torque(-math.Pi/2, q)
+	for i := 1; i <= steps; i++ {
+		th := -math.Pi/2 + math.Pi*float64(i)/steps
+		t := s.torque(th, q)
+		if prevT > 0 && t <= 0 {
+			lo, hi := th-math.Pi/steps, th
+			for k := 0; k < 50; k++ {
+				mid := (lo + hi) / 2
+				if s.torque(mid, q) > 0 {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			root := (lo + hi) / 2
+			if math.IsNaN(best) || math.Abs(root) < math.Abs(best) {
+				best = root
+			}
+		}
+		prevT = t
+	}
+	return best
+}
+
+// theoryPeriod — период малых затухающих колебаний около θ_eq:
+// ω0² = -τ'(θ_eq)/(mL²), ω² = ω0² - (b/2)².
+func (s *pendulumScenario) theoryPeriod(thetaEq float64) float64 {
+	const h = 1e-4
+	k := -(s.torque(thetaEq+h, s.q) - s.torque(thetaEq-h, s.q)) / (2 * h)
+	w2 := k/(pendMass*pendLen*pendLen) - pendDamping*pendDamping/4
+	if w2 <= 0 {
+		return math.NaN()
+	}
+	return 2 * math.Pi / math.Sqrt(w2)
+}
+
+// maxTheta ограничивает отклонение касанием пластины конденсатора.
+func (s *pendulumScenario) maxTheta() float64 {
+	if !s.plates {
+		return math.Inf(1)
+	}
+	return math.Asin((pendPlateX - pendBallR) / pendLen)
+}
+
+func (s *pendulumScenario) resetPeriod() {
+	s.settled = false
+	s.lastCross = 0
+	s.period = 0
+}
+
+func (s *pendulumScenario) rebuildCurve() {
+	s.curve = s.curve[:0]
+	for i := 0; i <= pendCurveN; i++ {
+		q := pendQMax * float64(i) / pendCurveN
+		s.curve = append(s.curve, Vec2{X: q, Y: s.equilibriumAngle(q)})
+	}
+	s.measured = s.measured[:0]
+	s.dirty = false
+}
+
+func (s *pendulumScenario) Update() {
+	step := 0.02e-6
+	if keyPressed(ebiten.KeyUp) {
+		s.q = math.Min(s.q+step, pendQMax)
+		s.resetPeriod()
+	}
+	if keyPressed(ebiten.KeyDown) {
+		s.q = math.Max(s.q-step, 0)
+		s.resetPeriod()
+	}
+	if keyPressed(ebiten.KeyRight) {
+		if s.plates {
+			s.E = math.Min(s.E+pendFieldStep, pendFieldMax)
+		} else {
+			s.d = math.Min(s.d+0.002, 0.45)
+		}
+		s.dirty = true
+	}
+	if keyPressed(ebiten.KeyLeft) {
+		if s.plates {
+			s.E = math.Max(s.E-pendFieldStep, -pendFieldMax)
+		} else {
+			s.d = math.Max(s.d-0.002, 0.05)
+		}
+		s.dirty = true
+	}
+	if keyJustPressed(ebiten.KeyN) {
+		s.Q = -s.Q
+		s.E = -s.E
+		s.dirty = true
+	}
+	if keyJustPressed(ebiten.KeyC) {
+		s.plates = !s.plates
+		s.theta, s.omega = 0, 0
+		s.dirty = true
+	}
+	if keyJustPressed(ebiten.KeyR) {
+		s.theta, s.omega = 0, 0
+		s.resetPeriod()
+	}
+	if s.dirty {
+		s.rebuildCurve()
+		s.resetPeriod()
+	}
+
+	eq := s.equilibriumAngle(s.q)
+	thMax := s.maxTheta()
+	dt := 1.0 / 60 / pendSubsteps
+	for i := 0; i < pendSubsteps; i++ {
+		alpha := s.torque(s.theta, s.q)/(pendMass*pendLen*pendLen) - pendDamping*s.omega
+		s.omega += alpha * dt
+		prev := s.theta
+		s.theta += s.omega * dt
+		s.time += dt
+
+		// шарик на стержне упирается в пластину и останавливается
+		if math.Abs(s.theta) > thMax {
+			s.theta = math.Copysign(thMax, s.theta)
+			s.omega = 0
+		}
+
+		// период между соседними проходами равновесия в одном направлении
+		if !math.IsNaN(eq) && prev < eq && s.theta >= eq {
+			if s.lastCross > 0 {
+				s.period = s.time - s.lastCross
+			}
+			s.lastCross = s.time
+		}
+	}
+
+	alpha := s.torque(s.theta, s.q) / (pendMass * pendLen * pendLen)
+	if !s.settled && math.Abs(s.omega) < 1e-4 && math.Abs(alpha) < 1e-3 {
+		s.settled = true
+		s.measured = append(s.measured, Vec2{X: s.q, Y: s.theta})
+	}
+}
+
+func (s *pendulumScenario) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 10, 14, 255})
+
+	const px0, py0 = 250.0, 110.0
+	toScreen := func(x, y float64) (float32, float32) {
+		return float32(px0 + x*pendScale), float32(py0 - y*pendScale)
+	}
+
+	bx, by := s.ballPos(s.theta)
+	sx, sy := toScreen(bx, by)
+	vector.StrokeLine(screen, float32(px0)-60, float32(py0), float32(px0)+60, float32(py0), 3, color.RGBA{150, 150, 160, 255}, false)
+	vector.StrokeLine(screen, float32(px0), float32(py0), sx, sy, 1, color.White, false)
+	vector.DrawFilledCircle(screen, sx, sy, 9, plateColor(s.q), false)
+
+	if s.plates {
+		left, top := toScreen(-pendPlateX, -0.03)
+		right, bot := toScreen(pendPlateX, -pendLen-0.1)
+		// потенциал левой пластины выше при поле вправо
+		vector.StrokeLine(screen, left, top, left, bot, 4, plateColor(s.E), false)
+		vector.StrokeLine(screen, right, top, right, bot, 4, plateColor(-s.E), false)
+	} else {
+		qx, qy := toScreen(s.d, -pendLen)
+		vector.DrawFilledCircle(screen, qx, qy, 9, plateColor(s.Q), false)
+	}
+
+	s.drawCurve(screen)
+
+	face := uiFace
+	text.Draw(screen, s.Name(), face, ui(10), ui(20), color.White)
+	source := fmt.Sprintf(tr("Q = %+.2f uC (N: flip sign), d = %.2f m (Left/Right)"), s.Q*1e6, s.d)
+	if s.plates {
+		source = fmt.Sprintf(tr("E = %+.1f kV/m, U = %+.1f kV (Left/Right, N: flip)"), s.E/1e3, s.E*2*pendPlateX/1e3)
+	}
+	text.Draw(screen, fmt.Sprintf(tr("q = %.2f uC (Up/Down), %s, C: fixed charge / plates, R: release from vertical"),
+		s.q*1e6, source), face, ui(10), ui(40), color.White)
+	eq := s.equilibriumAngle(s.q)
+	text.Draw(screen, fmt.Sprintf(tr("theta = %.2f deg, theory theta_eq = %.2f deg. F2: next scene, Esc: editor"),
+		s.theta*180/math.Pi, eq*180/math.Pi), face, ui(10), ui(60), color.White)
+	measured := tr("measuring...")
+	if s.period > 0 {
+		measured = fmt.Sprintf("%.3f s", s.period)
+	}
+	text.Draw(screen, fmt.Sprintf(tr("period: %s, small-oscillation theory %.3f s"), measured, s.theoryPeriod(eq)),
+		face, ui(10), ui(80), color.White)
+}
+
+// drawCurve рисует θ_eq(q): линия — теория, точки — установившиеся углы.
+func (s *pendulumScenario) drawCurve(screen *ebiten.Image) {
+	const (
+		x0, y0 = 560.0, 140.0
+		w, h   = 300.0, 200.0
+	)
+	thMax := 1e-9
+	for _, p := range s.curve {
+		if !math.IsNaN(p.Y) {
+			thMax = math.Max(thMax, math.Abs(p.Y))
+		}
+	}
+	toPlot := func(q, th float64) (float32, float32) {
+		return float32(x0 + q/pendQMax*w), float32(y0 + h/2 - th/thMax*h/2)
+	}
+
+	vector.StrokeRect(screen, x0, y0, w, h, 1, color.RGBA{150, 150, 160, 255}, false)
+	vector.StrokeLine(screen, x0, y0+h/2, x0+w, y0+h/2, 1, color.RGBA{70, 70, 80, 255}, false)
+
+	for i := 1; i < len(s.curve); i++ {
+		a, b := s.curve[i-1], s.curve[i]
+		if math.IsNaN(a.Y) || math.IsNaN(b.Y) {
+			continue
+		}
+		x1, y1 := toPlot(a.X, a.Y)
+		x2, y2 := toPlot(b.X, b.Y)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{80, 255, 120, 255}, false)
+	}
+	for _, m := range s.measured {
+		x, y := toPlot(m.X, m.Y)
+		vector.DrawFilledCircle(screen, x, y, 3, color.RGBA{255, 255, 0, 255}, false)
+	}
+	cx, cy := toPlot(s.q, s.theta)
+	vector.StrokeCircle(screen, cx, cy, 5, 1, color.White, false)
+
+	face := uiFace
+	text.Draw(screen, tr("Equilibrium angle vs q (line: theory, dots: simulation)"), face, int(x0)-40, int(y0)-ui(8), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("q: 0 .. %.1f uC"), pendQMax*1e6), face, int(x0), int(y0+h)+ui(16), color.White)
+	text.Draw(screen, fmt.Sprintf(tr("theta: +/-%.1f deg"), thMax*180/math.Pi), face, int(x0), int(y0+h)+ui(32), color.White)
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Замер масштабирования: сцены из N случайных зарядов прогоняются через
+// обычные Update и Draw, время каждого кадра собирается в перцентили.
+// Результат в JSON, чтобы сравнивать прогоны до и после оптимизаций.
+
+const (
+	PerfModeStatic    = "static"    // поле считается один раз, дальше только отрисовка
+	PerfModeRecompute = "recompute" // полный пересчёт линий и фона каждый кадр
+	PerfModeDynamics  = "dynamics"  // заряды движутся, пересчёт каждый шаг
+)
+
+type PerfConfig struct {
+	Sizes  []int
+	Frames int
+	Warmup int
+	Mode   string
+	Seed   uint64
+}
+
+type PerfStats struct {
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+type PerfResult struct {
+	Charges    int       `json:"charges"`
+	Frames     int       `json:"frames"`
+	FieldLines int       `json:"field_lines"`
+	Update     PerfStats `json:"update"`
+	Draw       PerfStats `json:"draw"`
+	Frame      PerfStats `json:"frame"`
+}
+
+type PerfReport struct {
+	Mode    string       `json:"mode"`
+	Solver  string       `json:"solver"`
+	Results []PerfResult `json:"results"`
+}
+
+func ParsePerfSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("bad scene size %q", f)
+		}
+		sizes = append(sizes, n)
+	}
+	return sizes, nil
+}
+
+// perfScene раскладывает n зарядов по экрану; знаки чередуются, так что
+// сцена близка к нейтральной, как и в генераторе случайных сцен.
+func perfScene(n int, seed uint64) []Charge {
+	r := rand.New(rand.NewPCG(seed, uint64(n)))
+	charges := make([]Charge, n)
+	for i := range charges {
+		q := float64(1 + r.IntN(3))
+		if i%2 == 1 {
+			q = -q
+		}
+		charges[i] = Charge{
+			X: (r.Float64()*2 - 1) * (halfW - randomMargin),
+			Y: (r.Float64()*2 - 1) * (halfH - randomMargin),
+			Q: q,
+		}
+	}
+	return charges
+}
+
+func perfStats(samples []time.Duration) PerfStats {
+	if len(samples) == 0 {
+		return PerfStats{}
+	}
+	s := slices.Clone(samples)
+	slices.Sort(s)
+
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	at := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(s)))) - 1
+		return ms(s[max(i, 0)])
+	}
+
+	var sum time.Duration
+	for _, d := range s {
+		sum += d
+	}
+	return PerfStats{
+		Mean: ms(sum) / float64(len(s)),
+		P50:  at(0.50),
+		P90:  at(0.90),
+		P99:  at(0.99),
+		Max:  ms(s[len(s)-1]),
+	}
+}
+
+// perfHarness оборачивает Game и сам завершает RunGame после последней сцены.
+type perfHarness struct {
+	cfg    PerfConfig
+	report PerfReport
+
+	game    *Game
+	scene   int
+	frame   int
+	pending time.Duration // время последнего Update, ждущего своей отрисовки
+	updated bool
+	update  []time.Duration
+	draw    []time.Duration
+}
+
+func (h *perfHarness) startScene() {
+	h.game = NewGame()
+	h.game.charges = perfScene(h.cfg.Sizes[h.scene], h.cfg.Seed)
+	h.game.dynamics = h.cfg.Mode == PerfModeDynamics
+	h.frame = 0
+	h.update = h.update[:0]
+	h.draw = h.draw[:0]
+}
+
+func (h *perfHarness) finishScene() {
+	frames := make([]time.Duration, len(h.update))
+	for i := range frames {
+		frames[i] = h.update[i] + h.draw[i]
+	}
+	h.report.Results = append(h.report.Results, PerfResult{
+		Charges:    h.cfg.Sizes[h.scene],
+		Frames:     len(frames),
+		FieldLines: len(h.game.fieldLines),
+		Update:     perfStats(h.update),
+		Draw:       perfStats(h.draw),
+		Frame:      perfStats(frames),
+	})
+	h.scene++
+	h.game = nil
+}
+
+func (h *perfHarness) Update() error {
+	if h.game == nil {
+		if h.scene == len(h.cfg.Sizes) {
+			return ebiten.Termination
+		}
+		h.startScene()
+	}
+	if h.cfg.Mode == PerfModeRecompute {
+		h.game.dirty = true
+	}
+	start := time.Now()
+	err := h.game.Update()
+	h.pending, h.updated = time.Since(start), true
+	return err
+}
+
+func (h *perfHarness) Draw(screen *ebiten.Image) {
+	if h.game == nil || !h.updated {
+		return
+	}
+	start := time.Now()
+	h.game.Draw(screen)
+	d := time.Since(start)
+
+	// кадр засчитывается парой Update+Draw, лишние отрисовки без Update пропускаются
+	h.updated = false
+	if h.frame >= h.cfg.Warmup {
+		h.update = append(h.update, h.pending)
+		h.draw = append(h.draw, d)
+	}
+	h.frame++
+	if len(h.draw) == h.cfg.Frames {
+		h.report.Solver = h.game.solverStatus()
+		h.finishScene()
+	}
+}
+
+func (h *perfHarness) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+// RunPerfCommand открывает окно и гоняет сцены без ограничения частоты
+// кадров; время Draw включает только подготовку команд, не работу GPU.
+func RunPerfCommand(cfg PerfConfig, out io.Writer) error {
+	switch cfg.Mode {
+	case PerfModeStatic, PerfModeRecompute, PerfModeDynamics:
+	default:
+		return fmt.Errorf("unknown perf mode %q", cfg.Mode)
+	}
+	if cfg.Frames <= 0 {
+		return fmt.Errorf("perf frames must be positive")
+	}
+
+	h := &perfHarness{cfg: cfg, report: PerfReport{Mode: cfg.Mode}}
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle(tr("Performance measurement"))
+	ebiten.SetVsyncEnabled(false)
+	ebiten.SetTPS(ebiten.SyncWithFPS)
+	if err := ebiten.RunGame(h); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h.report)
+}
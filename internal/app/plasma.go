@@ -0,0 +1,158 @@
+package app
+
+import (
+	"image/color"
+	"runtime"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Плазменное облако: тысячи лёгких заряженных частиц в поле зарядов
+// пользователя без обратного влияния. Данные хранятся по столбцам,
+// шаг считается пачками в нескольких горутинах, отрисовка — одним
+// вызовом DrawTriangles.
+
+const (
+	plasmaSpawnCount = 2000
+	plasmaMaxCount   = 20000
+	plasmaSpawnSigma = 30.0
+	plasmaThermal    = 0.3  // разброс начальных скоростей
+	plasmaMass       = 0.25 // масса частицы облака
+	plasmaSubsteps   = 4
+	plasmaChunk      = 2048 // частиц на горутину
+	plasmaDotSize    = 2.0
+)
+
+type plasmaCloud struct {
+	x, y   []float64
+	vx, vy []float64
+	q      []float64
+
+	verts []ebiten.Vertex
+	idx   []uint16
+	dot   *ebiten.Image
+}
+
+func (p *plasmaCloud) len() int { return len(p.x) }
+
+func (p *plasmaCloud) clear() {
+	p.x, p.y, p.vx, p.vy, p.q = p.x[:0], p.y[:0], p.vx[:0], p.vy[:0], p.q[:0]
+}
+
+// spawn добавляет квазинейтральное облако: поровну частиц обоих знаков.
+func (p *plasmaCloud) spawn(cx, cy float64, n int) {
+	n = min(n, plasmaMaxCount-p.len())
+	for i := 0; i < n; i++ {
+		q := 1.0
+		if i%2 == 1 {
+			q = -1
+		}
+		p.x = append(p.x, cx+rng.NormFloat64()*plasmaSpawnSigma)
+		p.y = append(p.y, cy+rng.NormFloat64()*plasmaSpawnSigma)
+		p.vx = append(p.vx, rng.NormFloat64()*plasmaThermal)
+		p.vy = append(p.vy, rng.NormFloat64()*plasmaThermal)
+		p.q = append(p.q, q)
+	}
+}
+
+func (p *plasmaCloud) remove(i int) {
+	last := p.len() - 1
+	p.x[i], p.y[i], p.vx[i], p.vy[i], p.q[i] = p.x[last], p.y[last], p.vx[last], p.vy[last], p.q[last]
+	p.x, p.y, p.vx, p.vy, p.q = p.x[:last], p.y[:last], p.vx[:last], p.vy[:last], p.q[:last]
+}
+
+func (g *Game) stepPlasmaRange(field func(x, y float64) (float64, float64), tree *quadTree, lo, hi int, dead []bool) {
+	p := &g.plasma
+	dt := dynDt / plasmaSubsteps
+	for i := lo; i < hi; i++ {
+		x, y, vx, vy := p.x[i], p.y[i], p.vx[i], p.vy[i]
+		qm := p.q[i] / plasmaMass
+
+		for s := 0; s < plasmaSubsteps; s++ {
+			Ex, Ey := field(x, y)
+			vx += (qm*Ex - g.damping*vx) * dt
+			vy += (qm*Ey - g.damping*vy) * dt
+			x += vx * dt
+			y += vy * dt
+		}
+		p.x[i], p.y[i], p.vx[i], p.vy[i] = x, y, vx, vy
+
+		if !inWorld(x, y) {
+			dead[i] = true
+			continue
+		}
+		if tree.near(x, y, seedRadius) {
+			dead[i] = true
+		}
+	}
+}
+
+func (g *Game) stepPlasma() {
+	n := g.plasma.len()
+	if n == 0 {
+		return
+	}
+
+	dead := make([]bool, n)
+	// оба дерева только читаются, горутинам можно
+	field, tree := g.bulkField(), newQuadTree(g.charges)
+	if n <= plasmaChunk || runtime.GOMAXPROCS(0) == 1 {
+		g.stepPlasmaRange(field, tree, 0, n, dead)
+	} else {
+		var wg sync.WaitGroup
+		for lo := 0; lo < n; lo += plasmaChunk {
+			wg.Add(1)
+			go func(lo, hi int) {
+				defer wg.Done()
+				g.stepPlasmaRange(field, tree, lo, hi, dead)
+			}(lo, min(lo+plasmaChunk, n))
+		}
+		wg.Wait()
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		if dead[i] {
+			g.plasma.remove(i)
+		}
+	}
+}
+
+func (g *Game) drawPlasma(screen *ebiten.Image) {
+	p := &g.plasma
+	n := p.len()
+	if n == 0 {
+		return
+	}
+
+	if p.dot == nil {
+		p.dot = ebiten.NewImage(1, 1)
+		p.dot.Fill(color.White)
+	}
+	const s = plasmaDotSize / 2
+
+	// индексы uint16: не больше 16384 частиц на вызов
+	const batch = 16384
+	for lo := 0; lo < n; lo += batch {
+		p.verts = p.verts[:0]
+		p.idx = p.idx[:0]
+		for i := lo; i < min(lo+batch, n); i++ {
+			x, y := g.cam.toScreen(p.x[i], p.y[i])
+
+			var r, gr, b float32 = 1, 0.6, 0.2
+			if p.q[i] < 0 {
+				r, gr, b = 0.3, 0.9, 1
+			}
+
+			base := uint16(len(p.verts))
+			for _, c := range [4][2]float32{{-s, -s}, {s, -s}, {s, s}, {-s, s}} {
+				p.verts = append(p.verts, ebiten.Vertex{
+					DstX: x + c[0], DstY: y + c[1], SrcX: 0, SrcY: 0,
+					ColorR: r, ColorG: gr, ColorB: b, ColorA: 1,
+				})
+			}
+			p.idx = append(p.idx, base, base+1, base+2, base, base+2, base+3)
+		}
+		screen.DrawTriangles(p.verts, p.idx, p.dot, nil)
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Встроенный график: панель с несколькими рядами над общей осью x.
+// У каждого ряда своя шкала по y (величины разной размерности), её
+// пределы подписаны цветом ряда у левого края.
+
+const (
+	plotPad    = 6 // при масштабе интерфейса 1
+	plotTitleH = 16
+	plotLabelW = 90 // ширина столбика пределов одного ряда
+)
+
+type plotSeries struct {
+	Name  string
+	Color color.RGBA
+	Y     []float64
+}
+
+type plotPanel struct {
+	X, Y, W, H float32
+	Title      string
+	XLabel     string // подпись оси x, пределы берутся из xs
+}
+
+// rangeOf — пределы ряда без бесконечностей; ноль включается, если ряд
+// не знакопостоянен, чтобы было видно смену знака.
+func rangeOf(ys []float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, y := range ys {
+		if math.IsInf(y, 0) || math.IsNaN(y) {
+			continue
+		}
+		lo, hi = min(lo, y), max(hi, y)
+	}
+	if lo > hi {
+		return 0, 1
+	}
+	if hi-lo < 1e-12 {
+		lo, hi = lo-1, hi+1
+	}
+	return lo, hi
+}
+
+func (p plotPanel) draw(dst *ebiten.Image, th *Theme, xs []float64, series []plotSeries) {
+	face := uiFace
+	vector.DrawFilledRect(dst, p.X, p.Y, p.W, p.H, th.Panel, false)
+	vector.StrokeRect(dst, p.X, p.Y, p.W, p.H, 1, th.Border, false)
+	pad, lh := uif(plotPad), lineHeight()
+	text.Draw(dst, p.Title, face, int(p.X+pad), baseline(int(p.Y), ui(plotTitleH)), th.HUD)
+	if len(xs) < 2 {
+		return
+	}
+
+	// область рядов, под ней строка подписей оси x
+	ax, ay := p.X+pad, p.Y+uif(plotTitleH)+pad
+	aw, ah := p.W-2*pad, p.H-uif(plotTitleH)-2*pad-float32(lh)
+	x0, x1 := xs[0], xs[len(xs)-1]
+	if x1 == x0 {
+		x1 = x0 + 1
+	}
+	sx := func(x float64) float32 { return ax + float32((x-x0)/(x1-x0))*aw }
+
+	for k, s := range series {
+		lo, hi := rangeOf(s.Y)
+		sy := func(y float64) float32 {
+			y = math.Max(lo, math.Min(hi, y))
+			return ay + ah - float32((y-lo)/(hi-lo))*ah
+		}
+		if lo < 0 && hi > 0 {
+			z := sy(0)
+			vector.StrokeLine(dst, ax, z, ax+aw, z, 1, withAlpha(s.Color, 70), false)
+		}
+		var path vector.Path
+		for i, y := range s.Y {
+			if i >= len(xs) || math.IsNaN(y) {
+				break
+			}
+			if i == 0 {
+				path.MoveTo(sx(xs[i]), sy(y))
+			} else {
+				path.LineTo(sx(xs[i]), sy(y))
+			}
+		}
+		op := &vector.DrawPathOptions{AntiAlias: true}
+		op.ColorScale.ScaleWithColor(s.Color)
+		vector.StrokePath(dst, &path, &vector.StrokeOptions{Width: uif(1.5), LineJoin: vector.LineJoinRound}, op)
+
+		// пределы ряда столбиком у левого края, ряды подписаны по очереди
+		tx := int(ax) + ui(2+plotLabelW*k)
+		text.Draw(dst, fmt.Sprintf("%s %.3g", s.Name, hi), face, tx, baseline(int(ay), lh), s.Color)
+		text.Draw(dst, fmt.Sprintf("%s %.3g", s.Name, lo), face, tx, int(ay+ah), s.Color)
+	}
+
+	by := int(p.Y+p.H) - ui(4)
+	text.Draw(dst, fmt.Sprintf("%.3g", x0), face, int(ax), by, th.HUD)
+	r := fmt.Sprintf("%.3g", x1)
+	text.Draw(dst, r, face, int(ax+aw)-textWidth(r), by, th.HUD)
+	xl := tr(p.XLabel)
+	text.Draw(dst, xl, face, int(ax+aw/2)-textWidth(xl)/2, by, th.HUD)
+}
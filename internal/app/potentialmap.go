@@ -0,0 +1,40 @@
+package app
+
+import (
+	"math"
+
+	"electric-field/internal/colormap"
+)
+
+// Фон потенциала: знак V задаёт сторону расходящейся палитры (красный —
+// плюс, синий — минус, белый — V = 0), модуль проходит через ту же
+// передаточную функцию, что и |E|. Дополняет тепловую карту |E|: видно,
+// где потенциал меняет знак, а не только где поле сильное.
+
+const bgPotentialMax = 100.0 // |V| верха шкалы при исходном диапазоне теплокарты
+
+func (g *Game) recomputePotentialBackground() {
+	w, h := g.cam.W, g.cam.H
+	vs := make([]float64, w*h)
+	mags := make([]float64, w*h)
+	parallelRows(h, func(py int) {
+		for px := 0; px < w; px++ {
+			V := g.slicePotential(g.cam.toWorld(float64(px), float64(py)))
+			vs[py*w+px], mags[py*w+px] = V, math.Abs(V)
+		}
+	})
+
+	// шкала |V| следует за клавишами диапазона теплокарты
+	g.potScale = g.heat
+	g.potScale.cdf = nil
+	g.potScale.max = bgPotentialMax * g.heat.max / defaultHeatScale().max
+	g.potScale.prepare(mags)
+
+	pix := g.bgPixels()
+	for i, V := range vs {
+		s := math.Copysign(g.potScale.at(mags[i]), V)
+		c := colormap.Coolwarm.Signed(s)
+		pix[4*i], pix[4*i+1], pix[4*i+2], pix[4*i+3] = c.R, c.G, c.B, 255
+	}
+	g.writeBackground(pix)
+}
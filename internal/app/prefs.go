@@ -0,0 +1,164 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"electric-field/internal/colormap"
+)
+
+// Настройки пользователя: тема, палитра, переключатели вида, единицы,
+// доступность и размер окна сохраняются при выходе в prefs.json каталога
+// настроек и читаются при запуске; флаги командной строки важнее файла.
+// Привязки клавиш живут отдельно, в keys.json (loadKeymap). Сеансы записи
+// и повтора ввода файл не читают и не пишут: повтор должен идти с теми же
+// настройками, что и запись.
+
+const prefsFile = "prefs.json"
+
+type Prefs struct {
+	Theme      string  `json:"theme"`
+	Colormap   string  `json:"colormap"`
+	Background int     `json:"background"`
+	Transfer   int     `json:"heatmap_scale"`
+	Arrows     int     `json:"arrows"`
+	ArrowScale int     `json:"arrow_scaling"`
+	ArrowStep  int     `json:"arrow_step"` // пикс
+	FieldWidth float32 `json:"field_line_width"`
+	ArrowWidth float32 `json:"arrow_line_width"`
+	Antialias  bool    `json:"antialias"`
+	Contours   bool    `json:"contours"`
+	Dashes     bool    `json:"dashes"`
+	Glow       bool    `json:"glow"`
+	Grid       bool    `json:"grid"`
+	Snap       bool    `json:"snap"`
+	Probe      bool    `json:"probe"`
+	Labels     bool    `json:"labels"`
+	Minimap    bool    `json:"minimap"`
+	Units      int     `json:"units"`
+	Colorblind bool    `json:"colorblind"`
+	UIScale    float64 `json:"ui_scale"`
+	Lang       string  `json:"lang"`
+	Width      int     `json:"window_width"`
+	Height     int     `json:"window_height"`
+}
+
+func prefsPath() (string, error) {
+	dir, err := prefsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, prefsFile), nil
+}
+
+// loadPrefs читает prefs.json; ok == false, если файла ещё нет.
+func loadPrefs() (p Prefs, ok bool, err error) {
+	path, err := prefsPath()
+	if err != nil {
+		return p, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, false, nil
+	}
+	if err != nil {
+		return p, false, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, false, fmt.Errorf("parse %s: %w", prefsFile, err)
+	}
+	return p, true, nil
+}
+
+func savePrefs(p Prefs) error {
+	path, err := prefsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// prefs снимает текущие настройки сцены g.
+func (g *Game) prefs() Prefs {
+	return Prefs{
+		Theme:      g.themes[g.themeIndex].Name,
+		Colormap:   g.palette().Name,
+		Background: int(g.bgMode),
+		Transfer:   int(g.heat.transfer),
+		Arrows:     int(g.arrowStyle),
+		ArrowScale: int(g.arrowScaling),
+		ArrowStep:  g.arrowStep(),
+		FieldWidth: g.lines.FieldWidth(),
+		ArrowWidth: g.lines.ArrowWidth(),
+		Antialias:  g.lines.Antialias,
+		Contours:   g.contours,
+		Dashes:     g.dashes,
+		Glow:       g.glow,
+		Grid:       g.grid,
+		Snap:       g.snap.on,
+		Probe:      g.probe,
+		Labels:     g.labels,
+		Minimap:    g.minimap.on,
+		Units:      int(g.units),
+		Colorblind: g.colorblind,
+		UIScale:    uiScale,
+		Lang:       languageCodes[lang],
+		Width:      screenWidth,
+		Height:     screenHeight,
+	}
+}
+
+// applyPrefs возвращает настройки; значения вне допустимых (например,
+// палитра, которой больше нет) оставляют умолчание.
+func (g *Game) applyPrefs(p Prefs) {
+	if i := slices.IndexFunc(g.themes, func(t Theme) bool { return t.Name == p.Theme }); i >= 0 {
+		g.themeIndex = i
+	}
+	if i := slices.IndexFunc(colormap.All, func(m *colormap.Map) bool { return m.Name == p.Colormap }); i >= 0 {
+		g.colormap = i
+	}
+	if p.Background >= 0 && p.Background < int(backgroundModeCount) {
+		g.bgMode = BackgroundMode(p.Background)
+	}
+	if p.Transfer >= 0 && p.Transfer < int(transferCount) {
+		g.heat.transfer = TransferFunc(p.Transfer)
+	}
+	if p.Arrows >= 0 && p.Arrows < int(arrowStyleCount) {
+		g.arrowStyle = ArrowStyle(p.Arrows)
+	}
+	if p.ArrowScale >= 0 && p.ArrowScale < int(arrowScalingCount) {
+		g.arrowScaling = ArrowScaling(p.ArrowScale)
+	}
+	if i := slices.Index(arrowGridSteps, p.ArrowStep); i >= 0 {
+		g.arrowStepIndex = i
+	}
+	if i := slices.Index(lineWidths, p.FieldWidth); i >= 0 {
+		g.lines.field = i
+	}
+	if i := slices.Index(lineWidths, p.ArrowWidth); i >= 0 {
+		g.lines.arrow = i
+	}
+	g.lines.Antialias = p.Antialias
+	g.contours, g.dashes, g.glow = p.Contours, p.Dashes, p.Glow
+	g.grid, g.snap.on, g.probe, g.labels, g.minimap.on = p.Grid, p.Snap, p.Probe, p.Labels, p.Minimap
+	if p.Units >= 0 && p.Units < int(unitModeCount) {
+		g.units = UnitMode(p.Units)
+	}
+	g.colorblind = p.Colorblind
+	if slices.Contains(uiScales, p.UIScale) {
+		setUIScale(p.UIScale)
+	}
+	g.dirty = true
+}
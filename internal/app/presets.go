@@ -0,0 +1,278 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Встроенные конфигурации с точными координатами
+
+type Preset struct {
+	Name    string
+	Charges []Charge
+}
+
+var presets = []Preset{
+	{
+		Name:    "Single charge",
+		Charges: []Charge{{X: 0, Y: 0, Q: +1}},
+	},
+	{
+		Name: "Point dipole",
+		Charges: []Charge{
+			{X: -20, Y: 0, Q: +1},
+			{X: +20, Y: 0, Q: -1},
+		},
+	},
+	{
+		Name: "Two like charges",
+		Charges: []Charge{
+			{X: -100, Y: 0, Q: +1},
+			{X: +100, Y: 0, Q: +1},
+		},
+	},
+	{
+		Name: "Linear quadrupole",
+		Charges: []Charge{
+			{X: -100, Y: 0, Q: +1},
+			{X: 0, Y: 0, Q: -2},
+			{X: +100, Y: 0, Q: +1},
+		},
+	},
+	{
+		Name: "Square quadrupole",
+		Charges: []Charge{
+			{X: -80, Y: -80, Q: +1},
+			{X: +80, Y: -80, Q: -1},
+			{X: +80, Y: +80, Q: +1},
+			{X: -80, Y: +80, Q: -1},
+		},
+	},
+	{
+		Name:    "Parallel-plate capacitor",
+		Charges: append(chargeRow(13, 25, -70, +0.3), chargeRow(13, 25, 70, -0.3)...),
+	},
+	{
+		Name:    "Line of charge",
+		Charges: chargeRow(15, 20, 0, +0.3),
+	},
+	{
+		Name:    "Line of alternating charges",
+		Charges: alternatingLine(7, 100),
+	},
+	{
+		// поле внутри кольца почти нулевое, как в клетке Фарадея
+		Name:    "Ring of charges (Faraday cage)",
+		Charges: chargeRing(24, 160, +0.25),
+	},
+	{
+		Name: "Bead on a ring near a fixed charge",
+		Charges: []Charge{
+			{X: 60, Y: 0, Q: +2, Pinned: true},
+			{X: 0, Y: -150, Q: +1, Track: ringTrack(0, 0, 150)},
+		},
+	},
+}
+
+func alternatingLine(n int, spacing float64) []Charge {
+	charges := make([]Charge, n)
+	x0 := -spacing * float64(n-1) / 2
+	for i := range charges {
+		q := 1.0
+		if i%2 == 1 {
+			q = -1
+		}
+		charges[i] = Charge{X: x0 + spacing*float64(i), Y: 0, Q: q}
+	}
+	return charges
+}
+
+// chargeRow — n одинаковых зарядов q в ряд на высоте y.
+func chargeRow(n int, spacing, y, q float64) []Charge {
+	charges := make([]Charge, n)
+	x0 := -spacing * float64(n-1) / 2
+	for i := range charges {
+		charges[i] = Charge{X: x0 + spacing*float64(i), Y: y, Q: q}
+	}
+	return charges
+}
+
+// chargeRing — n одинаковых зарядов q по окружности радиуса r.
+func chargeRing(n int, r, q float64) []Charge {
+	charges := make([]Charge, n)
+	for i := range charges {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		charges[i] = Charge{X: r * math.Cos(a), Y: r * math.Sin(a), Q: q}
+	}
+	return charges
+}
+
+func (g *Game) applyPreset(p Preset) {
+	g.checkpoint()
+	g.charges = append([]Charge(nil), p.Charges...)
+	g.testParticle = Particle{}
+	g.flashes = nil
+	g.equilibrium = false
+	g.dirty = true
+	g.conservation.resetSystem()
+	g.conservation.resetTest()
+}
+
+// presetMenuDigits — клавиши быстрого выбора: 1–9, затем 0 для десятого.
+var presetMenuDigits = []ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9, ebiten.Key0,
+}
+
+const (
+	presetLineH = 18
+	presetMenuW = 380
+)
+
+func (g *Game) presetMenuRect() (x, y, w, h float32) {
+	w = float32(ui(presetMenuW))
+	h = float32(ui(presetLineH)*(len(presets)+2) + ui(10))
+	return float32(g.cam.W)/2 - w/2, float32(screenHeight)/2 - h/2, w, h
+}
+
+// presetRowAt — пункт меню под точкой экрана или -1.
+func (g *Game) presetRowAt(px, py int) int {
+	x, y, w, _ := g.presetMenuRect()
+	if float32(px) < x || float32(px) > x+w {
+		return -1
+	}
+	top := presetRowsTop(y)
+	i := (py - top) / ui(presetLineH)
+	if py < top || i >= len(presets) {
+		return -1
+	}
+	return i
+}
+
+// updatePresetMenu обрабатывает открытое меню пресетов и сообщает,
+// забрало ли оно ввод этого кадра.
+func (g *Game) updatePresetMenu() bool {
+	if keyJustPressed(keyPresets) {
+		g.presetMenu = !g.presetMenu
+		return true
+	}
+	if !g.presetMenu {
+		return false
+	}
+	// клик по меню не должен стать зарядом после его закрытия
+	defer func() { g.lastLeft = mousePressed(ebiten.MouseButtonLeft) }()
+
+	choose := -1
+	for i, k := range presetMenuDigits {
+		if i < len(presets) && keyJustPressed(k) {
+			choose = i
+		}
+	}
+	switch {
+	case keyJustPressed(ebiten.KeyEscape):
+		g.presetMenu = false
+		return true
+	case keyJustPressed(ebiten.KeyArrowDown):
+		g.presetSel = (g.presetSel + 1) % len(presets)
+	case keyJustPressed(ebiten.KeyArrowUp):
+		g.presetSel = (g.presetSel + len(presets) - 1) % len(presets)
+	case keyJustPressed(ebiten.KeyEnter):
+		choose = g.presetSel
+	}
+
+	x, y := g.cursor()
+	row := g.presetRowAt(x, y)
+	if row >= 0 {
+		g.presetSel = row
+	}
+	if mouseJustPressed(ebiten.MouseButtonLeft) {
+		if row < 0 {
+			g.presetMenu = false // щелчок мимо меню закрывает его
+			return true
+		}
+		choose = row
+	}
+
+	if choose >= 0 {
+		g.presetSel = choose
+		g.applyPreset(presets[choose])
+		g.presetMenu = false
+	}
+	return true
+}
+
+// presetRowsTop — верх первого пункта, под заголовком меню с верхом y.
+func presetRowsTop(y float32) int { return int(y) + ui(6) + ui(presetLineH) }
+
+func (g *Game) drawPresetMenu(screen *ebiten.Image) {
+	if !g.presetMenu {
+		return
+	}
+
+	x, y, w, h := g.presetMenuRect()
+	th := g.theme()
+	vector.DrawFilledRect(screen, x, y, w, h, withAlpha(th.Panel, 230), false)
+	vector.StrokeRect(screen, x, y, w, h, 1, th.Border, false)
+
+	face := uiFace
+	lh := ui(presetLineH)
+	tx, top := int(x)+ui(12), presetRowsTop(y)
+	text.Draw(screen, tr("Presets: number, arrows+Enter or click; Esc closes"), face, tx, baseline(top-lh, lh), th.HUD)
+	for i, p := range presets {
+		r := top + lh*i
+		ry := baseline(r, lh)
+		if i == g.presetSel {
+			vector.DrawFilledRect(screen, x+4, float32(r), w-8, float32(lh-2), withAlpha(th.Border, 120), false)
+		}
+		label := "  "
+		if i < len(presetMenuDigits) {
+			label = keyLabel(presetMenuDigits[i]) + "."
+		}
+		text.Draw(screen, label+" "+tr(p.Name), face, tx, ry, th.HUD)
+	}
+}
+
+// presetSlug — имя пресета для командной строки: "Point dipole" → "point-dipole".
+func presetSlug(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+}
+
+// findPreset ищет пресет по полному имени или по части имени: "dipole",
+// "faraday-cage". Неоднозначная часть — ошибка со списком кандидатов.
+func findPreset(name string) (Preset, error) {
+	want := presetSlug(name)
+	var found []Preset
+	for _, p := range presets {
+		s := presetSlug(p.Name)
+		if s == want {
+			return p, nil
+		}
+		if strings.Contains(s, want) {
+			found = append(found, p)
+		}
+	}
+	switch len(found) {
+	case 1:
+		return found[0], nil
+	case 0:
+		var names []string
+		for _, p := range presets {
+			names = append(names, presetSlug(p.Name))
+		}
+		return Preset{}, fmt.Errorf("unknown preset %q, have: %s", name, strings.Join(names, ", "))
+	default:
+		var names []string
+		for _, p := range found {
+			names = append(names, presetSlug(p.Name))
+		}
+		return Preset{}, fmt.Errorf("preset %q is ambiguous: %s", name, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Щуп у курсора: поле и потенциал в мировой точке под мышью, каждый кадр.
+
+const (
+	probeOffset = 16 // отступ рамки от курсора, пикс
+)
+
+func (g *Game) probeLines() []string {
+	x, y := g.cursorWorld()
+	Ex, Ey := g.sliceField(x, y)
+	u := g.units
+	if u.si() {
+		eu := u.fieldUnit()
+		return []string{
+			fmt.Sprintf("x %8.1f  y %8.1f", x, y),
+			fmt.Sprintf("Ex %+10.4g%s", u.field(Ex), eu),
+			fmt.Sprintf("Ey %+10.4g%s", u.field(Ey), eu),
+			fmt.Sprintf("|E| %9.4g%s", u.field(math.Hypot(Ex, Ey)), eu),
+			fmt.Sprintf("V %+11.4g%s", u.potential(g.slicePotential(x, y)), u.potentialUnit()),
+		}
+	}
+	return []string{
+		fmt.Sprintf("x %8.1f  y %8.1f", x, y),
+		fmt.Sprintf("Ex %+9.3f", Ex),
+		fmt.Sprintf("Ey %+9.3f", Ey),
+		fmt.Sprintf("|E| %8.3f", math.Hypot(Ex, Ey)),
+		fmt.Sprintf("V %+10.2f", g.slicePotential(x, y)),
+	}
+}
+
+func (g *Game) drawProbe(screen *ebiten.Image) {
+	if !g.probe {
+		return
+	}
+	lines := g.probeLines()
+	w := 0
+	for _, l := range lines {
+		w = max(w, textWidth(l))
+	}
+	lh, pad := lineHeight(), ui(5)
+	w += 2 * pad
+	h := lh*len(lines) + ui(6)
+
+	// рамка уходит на другую сторону курсора у края экрана
+	cx, cy := g.cursor()
+	off := ui(probeOffset)
+	x, y := cx+off, cy+off
+	if x+w > screenWidth {
+		x = cx - off - w
+	}
+	if y+h > screenHeight {
+		y = cy - off - h
+	}
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), g.theme().Panel, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, g.theme().Border, false)
+	face := uiFace
+	for i, l := range lines {
+		text.Draw(screen, l, face, x+pad, baseline(y+ui(3)+lh*i, lh), g.theme().HUD)
+	}
+}
+
+// Закреплённые щупы: метки P1, P2, ... в мировых точках, показания
+// пересчитываются каждый кадр, так что следуют за движением зарядов.
+
+const (
+	pinPickRadius = 10 // пикс экрана
+	pinListW      = 300
+)
+
+type PinnedProbe struct {
+	X, Y float64
+	N    int // номер в подписи
+}
+
+func (g *Game) pinProbeAtMouse() {
+	x, y := g.cursorWorld()
+	g.pinSeq++
+	g.pins = append(g.pins, PinnedProbe{X: x, Y: y, N: g.pinSeq})
+	g.tutorialDid(tutProbe, x, y)
+}
+
+func (g *Game) removePinAtMouse() {
+	x, y := g.cursorWorld()
+	best, bestD := -1, pinPickRadius/g.cam.Zoom
+	for i, p := range g.pins {
+		if d := math.Hypot(p.X-x, p.Y-y); d <= bestD {
+			best, bestD = i, d
+		}
+	}
+	if best >= 0 {
+		g.pins = slices.Delete(g.pins, best, best+1)
+	}
+}
+
+func (g *Game) drawPins(screen *ebiten.Image) {
+	th := g.theme()
+	face := uiFace
+	for _, p := range g.pins {
+		x, y := g.cam.toScreen(p.X, p.Y)
+		vector.StrokeLine(screen, x-5, y, x+5, y, 1, th.HUD, false)
+		vector.StrokeLine(screen, x, y-5, x, y+5, 1, th.HUD, false)
+		vector.StrokeCircle(screen, x, y, 3, 1, th.HUD, false)
+
+		Ex, Ey := g.sliceField(p.X, p.Y)
+		s := fmt.Sprintf("P%d |E| %.3g%s  V %+.3g%s", p.N, g.units.field(math.Hypot(Ex, Ey)), g.units.fieldUnit(),
+			g.units.potential(g.slicePotential(p.X, p.Y)), g.units.potentialUnit())
+		lh := lineHeight()
+		bx, by := int(x)+ui(6), int(y)-ui(4)-lh
+		vector.DrawFilledRect(screen, float32(bx), float32(by), float32(textWidth(s)+ui(4)), float32(lh), th.Panel, false)
+		text.Draw(screen, s, face, bx+ui(2), baseline(by, lh), th.HUD)
+	}
+}
+
+// drawPinList — сводка по всем щупам в правом верхнем углу.
+func (g *Game) drawPinList(screen *ebiten.Image) {
+	if len(g.pins) == 0 {
+		return
+	}
+	th := g.theme()
+	u := g.units
+	head := "     x      y      Ex      Ey       V"
+	if u.si() {
+		head += fmt.Sprintf("  (E%s, V%s)", u.fieldUnit(), u.potentialUnit())
+	}
+	lh, pad := lineHeight(), ui(5)
+	w := max(ui(pinListW), textWidth(head)+2*pad)
+	x, y := g.cam.W-w-ui(10), ui(10)
+	h := g.pinListH()
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), th.Panel, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, th.Border, false)
+
+	face := uiFace
+	text.Draw(screen, head, face, x+pad, baseline(y+ui(3), lh), th.HUD)
+	for i, p := range g.pins {
+		Ex, Ey := g.sliceField(p.X, p.Y)
+		s := fmt.Sprintf("P%-2d %6.0f %6.0f %+7.3g %+7.3g %+7.3g", p.N, p.X, p.Y, u.field(Ex), u.field(Ey), u.potential(g.slicePotential(p.X, p.Y)))
+		if !u.si() {
+			s = fmt.Sprintf("P%-2d %6.0f %6.0f %+7.3f %+7.3f %+7.2f", p.N, p.X, p.Y, Ex, Ey, g.slicePotential(p.X, p.Y))
+		}
+		text.Draw(screen, s, face, x+pad, baseline(y+ui(3)+lh*(i+1), lh), th.HUD)
+	}
+}
+
+// pinListH — высота сводки щупов; под ней раскладываются другие панели.
+func (g *Game) pinListH() int { return lineHeight()*(len(g.pins)+1) + ui(6) }
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Угломер: три щелчка — вершина и по точке на каждом луче. Дуга между
+// лучами подписывается углом в градусах (0–180). Удобно сверять угол
+// выхода силовых линий из проводника и направление оси диполя. Как и
+// линейки, углы сохраняются со сценой и убираются правой кнопкой.
+
+const (
+	angleArcR     = 28 // радиус дуги, пикс
+	angleArcSteps = 24
+)
+
+type Angle struct {
+	V Vec2 `json:"vertex"`
+	A Vec2 `json:"a"`
+	B Vec2 `json:"b"`
+}
+
+// Degrees — угол между лучами VA и VB.
+func (a Angle) Degrees() float64 {
+	return math.Abs(angleBetween(a)) * 180 / math.Pi
+}
+
+// angleBetween — угол поворота от VA к VB со знаком, в (-π, π].
+func angleBetween(a Angle) float64 {
+	t := math.Atan2(a.B.Y-a.V.Y, a.B.X-a.V.X) - math.Atan2(a.A.Y-a.V.Y, a.A.X-a.V.X)
+	return math.Remainder(t, 2*math.Pi)
+}
+
+type protractor struct {
+	tool  bool
+	marks []Angle
+	pts   []Vec2 // уже поставленные точки незаконченного угла
+}
+
+func (g *Game) toggleProtractor() {
+	on := !g.protractor.tool
+	g.toolsOff()
+	g.protractor.tool = on
+}
+
+func (g *Game) updateProtractor(leftNow, rightNow bool) {
+	p := &g.protractor
+	if leftNow && !g.lastLeft {
+		x, y := g.snap.at(g.cursorWorld())
+		p.pts = append(p.pts, Vec2{X: x, Y: y})
+		if len(p.pts) == 3 {
+			p.marks = append(p.marks, Angle{V: p.pts[0], A: p.pts[1], B: p.pts[2]})
+			p.pts = nil
+		}
+	}
+	if rightNow && !g.lastRight {
+		if len(p.pts) > 0 {
+			p.pts = nil // сначала отменяется незаконченный угол
+			return
+		}
+		g.removeAngleAt(g.cursorWorld())
+	}
+}
+
+func (g *Game) removeAngleAt(x, y float64) {
+	p := &g.protractor
+	pick := seedPickR / g.cam.Zoom
+	for i, a := range p.marks {
+		if segmentDist(a.V, a.A, x, y) < pick || segmentDist(a.V, a.B, x, y) < pick {
+			p.marks = slices.Delete(p.marks, i, i+1)
+			return
+		}
+	}
+}
+
+func (g *Game) drawAngles(screen *ebiten.Image) {
+	p := &g.protractor
+	for _, a := range p.marks {
+		g.drawAngle(screen, a)
+	}
+	// незаконченный угол тянется за курсором
+	x, y := g.snap.at(g.cursorWorld())
+	cur := Vec2{X: x, Y: y}
+	switch len(p.pts) {
+	case 1:
+		vx, vy := g.cam.toScreen(p.pts[0].X, p.pts[0].Y)
+		cx, cy := g.cam.toScreen(cur.X, cur.Y)
+		vector.StrokeLine(screen, vx, vy, cx, cy, 1.5, g.theme().HUD, true)
+	case 2:
+		g.drawAngle(screen, Angle{V: p.pts[0], A: p.pts[1], B: cur})
+	}
+}
+
+func (g *Game) drawAngle(screen *ebiten.Image, a Angle) {
+	th := g.theme()
+	vx, vy := g.cam.toScreen(a.V.X, a.V.Y)
+	ax, ay := g.cam.toScreen(a.A.X, a.A.Y)
+	bx, by := g.cam.toScreen(a.B.X, a.B.Y)
+	vector.StrokeLine(screen, vx, vy, ax, ay, 1.5, th.HUD, true)
+	vector.StrokeLine(screen, vx, vy, bx, by, 1.5, th.HUD, true)
+
+	start := math.Atan2(a.A.Y-a.V.Y, a.A.X-a.V.X)
+	sweep := angleBetween(a)
+	r := float64(g.cam.px(angleArcR))
+	var path vector.Path
+	for i := 0; i <= angleArcSteps; i++ {
+		s, c := math.Sincos(start + sweep*float64(i)/angleArcSteps)
+		px, py := float32(float64(vx)+r*c), float32(float64(vy)+r*s)
+		if i == 0 {
+			path.MoveTo(px, py)
+		} else {
+			path.LineTo(px, py)
+		}
+	}
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(th.HUD)
+	vector.StrokePath(screen, &path, &vector.StrokeOptions{Width: 1.5}, op)
+
+	// подпись снаружи дуги, на её биссектрисе
+	s, c := math.Sincos(start + sweep/2)
+	label := fmt.Sprintf("%.1f°", a.Degrees())
+	d := r + float64(ui(10))
+	lx := float64(vx) + d*c - float64(textWidth(label))/2
+	ly := float64(vy) + d*s - float64(lineHeight())/2
+	text.Draw(screen, label, uiFace, int(lx), baseline(int(ly), lineHeight()), th.HUD)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Правка заряда колесом: над выделенным зарядом колесо меняет Q всех
+// выделенных зарядов вместо масштаба. Пока колесо крутится, каждый кадр
+// пересчитываются только линии; фон и контуры догоняют, когда прокрутка
+// затихла на qEditSettle кадров. Весь жест — одна точка отмены.
+
+const qEditSettle = 20
+
+type qEdit struct {
+	active bool
+	idle   int // кадров без прокрутки
+}
+
+// overSelection — курсор над выделенным зарядом, колесо правит Q.
+func (g *Game) overSelection() bool {
+	if !g.hasSelection() || keyPressed(ebiten.KeyAlt) {
+		return false
+	}
+	i := g.hovered()
+	return i >= 0 && g.charges[i].Selected
+}
+
+func (g *Game) updateQEdit() {
+	e := &g.qEdit
+	_, wy := wheel()
+	if wy == 0 || !g.overSelection() {
+		if e.active {
+			if e.idle++; e.idle >= qEditSettle {
+				e.active = false
+				g.dirty = true
+			}
+		}
+		return
+	}
+
+	if !e.active {
+		g.checkpoint()
+		e.active = true
+	}
+	e.idle = 0
+	d := wy * chargeQStep
+	for i := range g.charges {
+		c := &g.charges[i]
+		if !c.Selected {
+			continue
+		}
+		c.Q = stepQ(c.Q, d)
+		g.recordEdit(EditAdjustQ, c.X, c.Y, d)
+	}
+	g.linesStale = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+	if i := g.hovered(); i >= 0 {
+		g.notify(fmt.Sprintf(tr("Charge %s"), formatQ(g.charges[i].Q)))
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import "math"
+
+// Квадродерево по положениям зарядов для вопросов «есть ли заряд ближе r»:
+// линия поля, частица и посев проверяют это на каждом шаге, и перебор всех
+// зарядов при сотнях источников стоит больше самого шага. Дерево строится
+// один раз на пересчёт и не меняется; при движении зарядов его строят
+// заново, это O(n log n) против O(n) на каждом шаге.
+
+const (
+	quadLeafSize = 8  // зарядов в листе, дальше лист делится
+	quadMaxDepth = 16 // предел на случай совпадающих зарядов
+)
+
+type quadTree struct {
+	charges []Charge
+	root    *quadNode
+}
+
+type quadNode struct {
+	minX, minY, maxX, maxY float64
+	idx                    []int        // индексы зарядов листа
+	kids                   *[4]quadNode // nil у листа
+}
+
+// newQuadTree строит дерево по charges; срез не копируется и не должен
+// меняться, пока дерево используется.
+func newQuadTree(charges []Charge) *quadTree {
+	t := &quadTree{charges: charges}
+	if len(charges) == 0 {
+		return t
+	}
+	n := &quadNode{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+	n.idx = make([]int, len(charges))
+	for i, c := range charges {
+		n.idx[i] = i
+		n.minX, n.maxX = math.Min(n.minX, c.X), math.Max(n.maxX, c.X)
+		n.minY, n.maxY = math.Min(n.minY, c.Y), math.Max(n.maxY, c.Y)
+	}
+	t.split(n, 0)
+	t.root = n
+	return t
+}
+
+func (t *quadTree) split(n *quadNode, depth int) {
+	if len(n.idx) <= quadLeafSize || depth >= quadMaxDepth {
+		return
+	}
+	cx, cy := (n.minX+n.maxX)/2, (n.minY+n.maxY)/2
+	n.kids = &[4]quadNode{
+		{minX: n.minX, minY: n.minY, maxX: cx, maxY: cy},
+		{minX: cx, minY: n.minY, maxX: n.maxX, maxY: cy},
+		{minX: n.minX, minY: cy, maxX: cx, maxY: n.maxY},
+		{minX: cx, minY: cy, maxX: n.maxX, maxY: n.maxY},
+	}
+	for _, i := range n.idx {
+		q := 0
+		if t.charges[i].X >= cx {
+			q++
+		}
+		if t.charges[i].Y >= cy {
+			q += 2
+		}
+		n.kids[q].idx = append(n.kids[q].idx, i)
+	}
+	n.idx = nil
+	for k := range n.kids {
+		t.split(&n.kids[k], depth+1)
+	}
+}
+
+// nearest возвращает индекс ближайшего к (x, y) заряда строго ближе r
+// или -1.
+func (t *quadTree) nearest(x, y, r float64) int {
+	best, bestD2 := -1, r*r
+	if t.root != nil {
+		t.walk(t.root, x, y, &best, &bestD2)
+	}
+	return best
+}
+
+func (t *quadTree) walk(n *quadNode, x, y float64, best *int, bestD2 *float64) {
+	// расстояние до прямоугольника узла
+	dx := math.Max(0, math.Max(n.minX-x, x-n.maxX))
+	dy := math.Max(0, math.Max(n.minY-y, y-n.maxY))
+	if dx*dx+dy*dy >= *bestD2 {
+		return
+	}
+	if n.kids == nil {
+		for _, i := range n.idx {
+			c := t.charges[i]
+			if d2 := (c.X-x)*(c.X-x) + (c.Y-y)*(c.Y-y); d2 < *bestD2 {
+				*best, *bestD2 = i, d2
+			}
+		}
+		return
+	}
+	for k := range n.kids {
+		t.walk(&n.kids[k], x, y, best, bestD2)
+	}
+}
+
+// near сообщает, есть ли заряд строго ближе r к (x, y).
+func (t *quadTree) near(x, y, r float64) bool {
+	return t.nearest(x, y, r) >= 0
+}
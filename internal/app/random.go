@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Генератор случайных конфигураций
+
+const (
+	randomMargin     = 60.0 // отступ от края экрана
+	randomMinSpacing = 30.0 // минимальное расстояние между зарядами
+	randomAttempts   = 200
+)
+
+var randomCounts = []int{2, 4, 8, 16, 32}
+
+// rng — генератор случайных сцен и облаков плазмы. Зерно задаётся явно,
+// чтобы запись сеанса воспроизводилась с теми же случайными числами.
+var rng = rand.New(rand.NewPCG(rand.Uint64(), 0))
+
+func seedRandom(seed uint64) {
+	rng = rand.New(rand.NewPCG(seed, 0))
+}
+
+type randomConfig struct {
+	countIndex int
+	MinQ, MaxQ int  // диапазон модуля заряда
+	Neutral    bool // суммарный заряд равен нулю
+}
+
+func defaultRandomConfig() randomConfig {
+	return randomConfig{countIndex: 2, MinQ: 1, MaxQ: 3, Neutral: true}
+}
+
+func (rc randomConfig) count() int { return randomCounts[rc.countIndex] }
+
+func (rc *randomConfig) cycleCount() {
+	rc.countIndex = (rc.countIndex + 1) % len(randomCounts)
+}
+
+func (rc randomConfig) String() string {
+	net := tr("any net charge")
+	if rc.Neutral {
+		net = tr("neutral")
+	}
+	return fmt.Sprintf(tr("N = %d, |Q| in %d..%d, %s"), rc.count(), rc.MinQ, rc.MaxQ, net)
+}
+
+func (rc randomConfig) randomQ() float64 {
+	q := float64(rc.MinQ + rng.IntN(rc.MaxQ-rc.MinQ+1))
+	if rng.IntN(2) == 0 {
+		q = -q
+	}
+	return q
+}
+
+// magnitudes подбирает заряды; при условии нейтральности последний
+// заряд компенсирует сумму остальных и должен попасть в диапазон.
+func (rc randomConfig) magnitudes() []float64 {
+	n := rc.count()
+	qs := make([]float64, n)
+
+	for attempt := 0; ; attempt++ {
+		sum := 0.0
+		for i := range qs {
+			qs[i] = rc.randomQ()
+			sum += qs[i]
+		}
+		if !rc.Neutral {
+			return qs
+		}
+
+		last := qs[n-1] - sum
+		if a := math.Abs(last); a >= float64(rc.MinQ) && a <= float64(rc.MaxQ) {
+			qs[n-1] = last
+			return qs
+		}
+
+		// запасной путь: чередование знаков всегда даёт нейтральность при чётном n
+		if attempt == randomAttempts && n%2 == 0 {
+			for i := 0; i < n; i += 2 {
+				qs[i] = math.Abs(qs[i])
+				qs[i+1] = -qs[i]
+			}
+			return qs
+		}
+	}
+}
+
+func (g *Game) generateRandomScene() {
+	g.checkpoint()
+	qs := g.random.magnitudes()
+
+	charges := make([]Charge, 0, len(qs))
+	for _, q := range qs {
+		var x, y float64
+		for attempt := 0; attempt < randomAttempts; attempt++ {
+			x = (rng.Float64()*2 - 1) * (halfW - randomMargin)
+			y = (rng.Float64()*2 - 1) * (halfH - randomMargin)
+
+			ok := true
+			for _, c := range charges {
+				if math.Hypot(c.X-x, c.Y-y) < randomMinSpacing {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				break
+			}
+		}
+		charges = append(charges, Charge{X: x, Y: y, Q: q})
+	}
+
+	g.charges = charges
+	g.testParticle = Particle{}
+	g.flashes = nil
+	g.equilibrium = false
+	g.dirty = true
+	g.conservation.resetSystem()
+	g.conservation.resetTest()
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Линейка: с инструментом линейки протяжка измеряет отрезок в мировых
+// единицах, подпись показывает длину и разности потенциала и |E| между
+// концами. Измерения остаются на сцене, сохраняются вместе с ней и
+// убираются правой кнопкой; показания пересчитываются каждый кадр.
+
+const rulerTick = 5 // полудлина засечек на концах, пикс
+
+type Ruler struct {
+	A Vec2 `json:"a"`
+	B Vec2 `json:"b"`
+}
+
+type rulerTool struct {
+	tool    bool
+	marks   []Ruler
+	drawing bool
+	from    Vec2
+}
+
+// toolsOff выключает инструменты, заменяющие левую кнопку; включение
+// одного из них выключает остальные.
+func (g *Game) toolsOff() {
+	g.seeding.tool, g.seeding.drawing = false, false
+	g.ruler.tool, g.ruler.drawing = false, false
+	g.protractor.tool, g.protractor.pts = false, nil
+	g.notes.tool = false
+}
+
+// leftTool сообщает, занята ли левая кнопка одним из инструментов.
+func (g *Game) leftTool() bool {
+	return g.seeding.tool || g.ruler.tool || g.protractor.tool || g.notes.tool
+}
+
+func (g *Game) toggleRulerTool() {
+	on := !g.ruler.tool
+	g.toolsOff()
+	g.ruler.tool = on
+}
+
+func (g *Game) updateRuler(leftNow, rightNow bool) {
+	r := &g.ruler
+	x, y := g.snap.at(g.cursorWorld())
+
+	if leftNow && !g.lastLeft {
+		r.drawing, r.from = true, Vec2{X: x, Y: y}
+	}
+	if !leftNow && r.drawing {
+		r.drawing = false
+		fx, fy := g.cam.toScreen(r.from.X, r.from.Y)
+		cx, cy := g.cam.toScreen(x, y)
+		if math.Hypot(float64(cx-fx), float64(cy-fy)) >= seedClickSlop {
+			r.marks = append(r.marks, Ruler{A: r.from, B: Vec2{X: x, Y: y}})
+		}
+	}
+	if rightNow && !g.lastRight {
+		g.removeRulerAt(g.cursorWorld())
+	}
+}
+
+func (g *Game) removeRulerAt(x, y float64) {
+	r := &g.ruler
+	pick := seedPickR / g.cam.Zoom
+	for i, m := range r.marks {
+		if segmentDist(m.A, m.B, x, y) < pick {
+			r.marks = slices.Delete(r.marks, i, i+1)
+			return
+		}
+	}
+}
+
+func (g *Game) rulerLabel(m Ruler) string {
+	ax, ay := g.sliceField(m.A.X, m.A.Y)
+	bx, by := g.sliceField(m.B.X, m.B.Y)
+	u := g.units
+	d := fmt.Sprintf("%.1f", math.Hypot(m.B.X-m.A.X, m.B.Y-m.A.Y))
+	if u.si() {
+		d = fmt.Sprintf("%.3g m", math.Hypot(m.B.X-m.A.X, m.B.Y-m.A.Y)/pxPerMeter)
+	}
+	return fmt.Sprintf("%s  ΔV %+.3g%s  Δ|E| %+.3g%s", d,
+		u.potential(g.slicePotential(m.B.X, m.B.Y)-g.slicePotential(m.A.X, m.A.Y)), u.potentialUnit(),
+		u.field(math.Hypot(bx, by)-math.Hypot(ax, ay)), u.fieldUnit())
+}
+
+func (g *Game) drawRulers(screen *ebiten.Image) {
+	r := &g.ruler
+	for _, m := range r.marks {
+		g.drawRuler(screen, m)
+	}
+	if r.drawing {
+		x, y := g.snap.at(g.cursorWorld())
+		g.drawRuler(screen, Ruler{A: r.from, B: Vec2{X: x, Y: y}})
+	}
+}
+
+func (g *Game) drawRuler(screen *ebiten.Image, m Ruler) {
+	th := g.theme()
+	ax, ay := g.cam.toScreen(m.A.X, m.A.Y)
+	bx, by := g.cam.toScreen(m.B.X, m.B.Y)
+	vector.StrokeLine(screen, ax, ay, bx, by, 1.5, th.HUD, true)
+
+	// засечки поперёк отрезка
+	dx, dy := bx-ax, by-ay
+	if l := float32(math.Hypot(float64(dx), float64(dy))); l > 0 {
+		nx, ny := -dy/l*rulerTick, dx/l*rulerTick
+		vector.StrokeLine(screen, ax-nx, ay-ny, ax+nx, ay+ny, 1.5, th.HUD, true)
+		vector.StrokeLine(screen, bx-nx, by-ny, bx+nx, by+ny, 1.5, th.HUD, true)
+	}
+
+	s := g.rulerLabel(m)
+	mx, my := (ax+bx)/2, (ay+by)/2
+	lh := lineHeight()
+	tx, ty := int(mx)+ui(6), int(my)-ui(4)-lh
+	vector.DrawFilledRect(screen, float32(tx), float32(ty), float32(textWidth(s)+ui(4)), float32(lh), th.Panel, false)
+	text.Draw(screen, s, uiFace, tx+ui(2), baseline(ty, lh), th.HUD)
+}
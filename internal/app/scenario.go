@@ -0,0 +1,48 @@
+package app
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Сценарии: самостоятельные демонстрации со своей логикой ввода и отрисовки.
+// Пока сценарий активен, редактор зарядов не получает ввод.
+
+type Scenario interface {
+	Name() string
+	Update()
+	Draw(screen *ebiten.Image)
+}
+
+// scenarioFactory — сценарий с постоянным id: Name переводится и зависит
+// от состояния, а по id сценарий ищет галерея.
+type scenarioFactory struct {
+	id  string
+	new func() Scenario
+}
+
+var scenarioFactories = []scenarioFactory{
+	{"crt", func() Scenario { return newCRTScenario() }},
+	{"paul-trap", func() Scenario { return newPaulScenario() }},
+	{"einzel-lens", func() Scenario { return newLensScenario() }},
+	{"pendulum", func() Scenario { return newPendulumScenario() }},
+}
+
+// updateScenarioSelection переключает сценарии по F2 и выходит из них по Esc.
+func (g *Game) updateScenarioSelection() {
+	if keyJustPressed(ebiten.KeyEscape) && g.scenario != nil {
+		g.scenario = nil
+		g.scenarioIndex = -1
+		return
+	}
+	if !keyJustPressed(keyScenes) {
+		return
+	}
+
+	g.scenarioIndex++
+	if g.scenarioIndex >= len(scenarioFactories) {
+		g.scenario = nil
+		g.scenarioIndex = -1
+		return
+	}
+	g.scenario = scenarioFactories[g.scenarioIndex].new()
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"electric-field/internal/colormap"
+)
+
+// Сохранение сцены в JSON для заранее подготовленных демонстраций:
+// заряды, провода, ручные посевы, камера и настройки отображения.
+// Ctrl+S пишет файл сцены, Ctrl+O читает его обратно.
+
+const (
+	defaultScenePath = "scene.json"
+	sceneVersion     = 1
+)
+
+type sceneFile struct {
+	Version int         `json:"version"`
+	Charges []Charge    `json:"charges"`
+	Wires   []Wire      `json:"wires,omitempty"`
+	Seeds   []Vec2      `json:"seeds,omitempty"`
+	Flux    [][2]Vec2   `json:"fluxSegments,omitempty"`
+	Rulers  []Ruler     `json:"rulers,omitempty"`
+	Angles  []Angle     `json:"angles,omitempty"`
+	Notes   []Note      `json:"notes,omitempty"`
+	Views   []Bookmark  `json:"bookmarks,omitempty"`
+	Camera  sceneCamera `json:"camera"`
+	View    sceneView   `json:"view"`
+}
+
+type sceneCamera struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Zoom float64 `json:"zoom"`
+}
+
+type sceneView struct {
+	Background   BackgroundMode `json:"background"`
+	Colormap     string         `json:"colormap"`
+	Transfer     TransferFunc   `json:"transfer"`
+	HeatMax      float64        `json:"heatMax"`
+	HeatDecades  float64        `json:"heatDecades"`
+	Arrows       ArrowStyle     `json:"arrows"`
+	ArrowScaling ArrowScaling   `json:"arrowScaling"`
+	ArrowStep    int            `json:"arrowStep"`
+	Contours     bool           `json:"contours,omitempty"`
+	Dashes       bool           `json:"dashes,omitempty"`
+	Grid         bool           `json:"grid,omitempty"`
+	Labels       bool           `json:"labels,omitempty"`
+	Glow         bool           `json:"glow,omitempty"`
+	SeedsPerQ    int            `json:"seedsPerUnitQ"`
+	SeedRadius   float64        `json:"seedRadius"`
+	Damping      *float64       `json:"damping,omitempty"` // nil — в файле нет, затухание прежнее
+}
+
+func (g *Game) sceneFile() sceneFile {
+	damping := g.damping
+	return sceneFile{
+		Version: sceneVersion,
+		Charges: slices.Clone(g.charges),
+		Wires:   slices.Clone(g.wires),
+		Seeds:   slices.Clone(g.seeding.points),
+		Flux:    slices.Clone(g.seeding.segments),
+		Rulers:  slices.Clone(g.ruler.marks),
+		Angles:  slices.Clone(g.protractor.marks),
+		Notes:   slices.Clone(g.notes.items),
+		Views:   slices.Clone(g.bookmarks.items),
+		Camera:  sceneCamera{X: g.cam.X, Y: g.cam.Y, Zoom: g.cam.Zoom},
+		View: sceneView{
+			Background:   g.bgMode,
+			Colormap:     g.palette().Name,
+			Transfer:     g.heat.transfer,
+			HeatMax:      g.heat.max,
+			HeatDecades:  g.heat.decades,
+			Arrows:       g.arrowStyle,
+			ArrowScaling: g.arrowScaling,
+			ArrowStep:    g.arrowStep(),
+			Contours:     g.contours,
+			Dashes:       g.dashes,
+			Grid:         g.grid,
+			Labels:       g.labels,
+			Glow:         g.glow,
+			SeedsPerQ:    g.seeding.perUnitQ,
+			SeedRadius:   g.seeding.radius,
+			Damping:      &damping,
+		},
+	}
+}
+
+// applySceneFile заменяет сцену; неизвестные или испорченные настройки
+// отображения остаются прежними.
+func (g *Game) applySceneFile(s sceneFile) {
+	g.checkpoint()
+	g.restore(sceneState{charges: s.Charges, wires: s.Wires})
+	g.seeding.points = slices.Clone(s.Seeds)
+	g.seeding.segments = slices.Clone(s.Flux)
+	g.ruler.marks = slices.Clone(s.Rulers)
+	g.protractor.marks = slices.Clone(s.Angles)
+	g.notes.items = slices.Clone(s.Notes)
+	g.notes.editing = false
+	g.bookmarks.items = slices.Clone(s.Views)
+	g.testParticle = Particle{}
+	g.conservation.resetTest()
+
+	if s.Camera.Zoom > 0 {
+		g.cam.X, g.cam.Y = s.Camera.X, s.Camera.Y
+		g.cam.Zoom = min(max(camMinZoom, s.Camera.Zoom), camMaxZoom)
+	}
+
+	v := s.View
+	if v.Background >= 0 && v.Background < backgroundModeCount {
+		g.bgMode = v.Background
+	}
+	if i := slices.IndexFunc(colormap.All, func(m *colormap.Map) bool { return m.Name == v.Colormap }); i >= 0 {
+		g.colormap = i
+	}
+	if v.Transfer >= 0 && v.Transfer < transferCount {
+		g.heat.transfer = v.Transfer
+	}
+	if v.HeatMax > 0 {
+		g.heat.max = v.HeatMax
+	}
+	if v.HeatDecades > 0 {
+		g.heat.decades = v.HeatDecades
+	}
+	if v.Arrows >= 0 && v.Arrows < arrowStyleCount {
+		g.arrowStyle = v.Arrows
+	}
+	if v.ArrowScaling >= 0 && v.ArrowScaling < arrowScalingCount {
+		g.arrowScaling = v.ArrowScaling
+	}
+	if i := slices.Index(arrowGridSteps, v.ArrowStep); i >= 0 {
+		g.arrowStepIndex = i
+	}
+	g.contours, g.dashes, g.grid, g.labels, g.glow = v.Contours, v.Dashes, v.Grid, v.Labels, v.Glow
+	if v.SeedsPerQ > 0 {
+		g.seeding.perUnitQ = min(v.SeedsPerQ, seedMaxPerQ)
+	}
+	if v.SeedRadius > 0 {
+		g.seeding.radius = v.SeedRadius
+	}
+	if v.Damping != nil {
+		g.damping = min(max(0, *v.Damping), maxDamping)
+	}
+}
+
+func (g *Game) saveScene(path string) error {
+	data, err := json.MarshalIndent(g.sceneFile(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (g *Game) loadScene(path string) error {
+	s, err := readSceneFile(path)
+	if err != nil {
+		return err
+	}
+	g.applySceneFile(s)
+	return nil
+}
+
+func readSceneFile(path string) (sceneFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sceneFile{}, err
+	}
+	return parseSceneFile(path, data)
+}
+
+func parseSceneFile(name string, data []byte) (sceneFile, error) {
+	var s sceneFile
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if s.Version > sceneVersion {
+		return s, fmt.Errorf("%s: scene version %d is newer than supported %d", name, s.Version, sceneVersion)
+	}
+	return s, nil
+}
+
+func (g *Game) saveSceneKey() {
+	if err := g.saveScene(g.scenePath); err != nil {
+		g.notify(tr("Save failed: ") + err.Error())
+		return
+	}
+	g.notify(fmt.Sprintf(tr("Saved %d charges to %s"), len(g.charges), g.scenePath))
+}
+
+func (g *Game) loadSceneKey() {
+	if err := g.loadScene(g.scenePath); err != nil {
+		g.notify(tr("Load failed: ") + err.Error())
+		return
+	}
+	g.notify(fmt.Sprintf(tr("Loaded %d charges from %s"), len(g.charges), g.scenePath))
+}
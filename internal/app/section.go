@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Разрез поля вдоль отрезка: первое нажатие клавиши ставит начало
+// отрезка под курсором, второе — конец. Пока разрез открыт, |E| и V
+// вдоль него пересчитываются каждый кадр и рисуются на графике.
+
+const sectionSamples = 200
+
+type section struct {
+	state  int // 0 — нет, 1 — задано начало, 2 — разрез открыт
+	a, b   Vec2
+	dist   []float64
+	mag, V []float64
+}
+
+// sectionKey обрабатывает нажатие клавиши разреза; с Shift разрез закрывается.
+func (g *Game) sectionKey() {
+	s := &g.section
+	if keyPressed(ebiten.KeyShift) {
+		s.state = 0
+		return
+	}
+	x, y := g.cursorWorld()
+	switch s.state {
+	case 1:
+		s.b, s.state = Vec2{X: x, Y: y}, 2
+	default:
+		s.a, s.state = Vec2{X: x, Y: y}, 1
+	}
+}
+
+func (g *Game) updateSection() {
+	s := &g.section
+	if s.state != 2 {
+		return
+	}
+	L := math.Hypot(s.b.X-s.a.X, s.b.Y-s.a.Y)
+	s.dist, s.mag, s.V = s.dist[:0], s.mag[:0], s.V[:0]
+	for i := 0; i <= sectionSamples; i++ {
+		t := float64(i) / sectionSamples
+		x, y := s.a.X+t*(s.b.X-s.a.X), s.a.Y+t*(s.b.Y-s.a.Y)
+		Ex, Ey := g.sliceField(x, y)
+		s.dist = append(s.dist, t*L)
+		s.mag = append(s.mag, math.Hypot(Ex, Ey))
+		s.V = append(s.V, g.slicePotential(x, y))
+	}
+}
+
+func (g *Game) drawSection(screen *ebiten.Image) {
+	s := &g.section
+	if s.state == 0 {
+		return
+	}
+	th := g.theme()
+	face := uiFace
+
+	b := s.b
+	if s.state == 1 {
+		x, y := g.cursorWorld()
+		b = Vec2{X: x, Y: y}
+	}
+	x1, y1 := g.cam.toScreen(s.a.X, s.a.Y)
+	x2, y2 := g.cam.toScreen(b.X, b.Y)
+	vector.StrokeLine(screen, x1, y1, x2, y2, 2, th.HUD, true)
+	text.Draw(screen, "A", face, int(x1)+ui(4), int(y1)-ui(4), th.HUD)
+	text.Draw(screen, "B", face, int(x2)+ui(4), int(y2)-ui(4), th.HUD)
+
+	if s.state != 2 || g.hideHUD {
+		return
+	}
+	p := g.hud.section
+	p.Title = fmt.Sprintf(tr("Section A-B, %s: new, Shift+%s: close"), keyLabel(keySection), keyLabel(keySection))
+	u := g.units
+	p.draw(screen, th, s.dist, []plotSeries{
+		{Name: "|E|" + u.fieldUnit(), Color: th.FieldLines, Y: convertAll(s.mag, u.field)},
+		{Name: "V" + u.potentialUnit(), Color: th.Positive, Y: convertAll(s.V, u.potential)},
+	})
+}
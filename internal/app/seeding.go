@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Ручной посев силовых линий. Инструмент посева заменяет левую кнопку:
+// щелчок добавляет линию через точку, протяжка — линии, равномерно
+// расставленные вдоль отрезка (картина потока через «поверхность»).
+// Там же меняются густота и стартовый радиус линий от зарядов.
+
+const (
+	seedSegmentGap  = 15.0 // шаг линий вдоль отрезка, мировые единицы
+	seedClickSlop   = 5.0  // протяжка короче — щелчок, пикс
+	seedPickR       = 10.0 // радиус выбора посева правой кнопкой, пикс
+	seedDensityStep = 5
+	seedMaxPerQ     = 60
+	seedRadiusStep  = 4.0
+	seedMaxRadius   = 40.0
+)
+
+type seeding struct {
+	tool     bool
+	perUnitQ int     // линий на единицу заряда
+	radius   float64 // стартовое расстояние линии от заряда
+
+	points   []Vec2
+	segments [][2]Vec2
+
+	drawing bool
+	from    Vec2 // начало протягиваемого отрезка
+}
+
+func defaultSeeding() seeding {
+	return seeding{perUnitQ: seedsPerUnitQ, radius: seedRadius}
+}
+
+// seedCount — число линий пропорционально |Q|, чтобы густота линий
+// передавала поток, но не меньше одной у ненулевого заряда.
+func (s *seeding) seedCount(q float64) int {
+	if q == 0 {
+		return 0
+	}
+	return max(1, int(math.Round(float64(s.perUnitQ)*math.Abs(q))))
+}
+
+// adjustSeeding меняет густоту линий, с Shift — стартовый радиус.
+func (g *Game) adjustSeeding(dir int) {
+	s := &g.seeding
+	if keyPressed(ebiten.KeyShift) {
+		s.radius = math.Max(seedRadius, math.Min(seedMaxRadius, s.radius+float64(dir)*seedRadiusStep))
+	} else {
+		s.perUnitQ = max(1, min(seedMaxPerQ, s.perUnitQ+dir*seedDensityStep))
+	}
+	g.dirty = true
+}
+
+func (g *Game) clearSeeds() {
+	g.seeding.points, g.seeding.segments = nil, nil
+	g.dirty = true
+}
+
+// updateSeedTool обрабатывает мышь вместо добавления зарядов.
+func (g *Game) updateSeedTool(leftNow, rightNow bool) {
+	s := &g.seeding
+	x, y := g.cursorWorld()
+
+	if leftNow && !g.lastLeft {
+		s.drawing, s.from = true, Vec2{X: x, Y: y}
+	}
+	if !leftNow && s.drawing {
+		s.drawing = false
+		fx, fy := g.cam.toScreen(s.from.X, s.from.Y)
+		cx, cy := g.cam.toScreen(x, y)
+		if math.Hypot(float64(cx-fx), float64(cy-fy)) < seedClickSlop {
+			s.points = append(s.points, s.from)
+		} else {
+			s.segments = append(s.segments, [2]Vec2{s.from, {X: x, Y: y}})
+		}
+		g.dirty = true
+	}
+	if rightNow && !g.lastRight {
+		g.removeSeedAt(x, y)
+	}
+}
+
+// removeSeedAt удаляет ближайший к курсору посев.
+func (g *Game) removeSeedAt(x, y float64) {
+	s := &g.seeding
+	r := seedPickR / g.cam.Zoom
+	for i, p := range s.points {
+		if math.Hypot(p.X-x, p.Y-y) < r {
+			s.points = slices.Delete(s.points, i, i+1)
+			g.dirty = true
+			return
+		}
+	}
+	for i, seg := range s.segments {
+		if segmentDist(seg[0], seg[1], x, y) < r {
+			s.segments = slices.Delete(s.segments, i, i+1)
+			g.dirty = true
+			return
+		}
+	}
+}
+
+func segmentDist(a, b Vec2, x, y float64) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l2 := dx*dx + dy*dy; l2 > 0 {
+		t = math.Max(0, math.Min(1, ((x-a.X)*dx+(y-a.Y)*dy)/l2))
+	}
+	return math.Hypot(a.X+t*dx-x, a.Y+t*dy-y)
+}
+
+// seedPoints — все точки ручного посева, отрезки разбиты с шагом seedSegmentGap.
+func (s *seeding) seedPoints() []Vec2 {
+	pts := slices.Clone(s.points)
+	for _, seg := range s.segments {
+		a, b := seg[0], seg[1]
+		n := max(1, int(math.Hypot(b.X-a.X, b.Y-a.Y)/seedSegmentGap))
+		for i := 0; i < n; i++ {
+			t := (float64(i) + 0.5) / float64(n)
+			pts = append(pts, Vec2{X: a.X + t*(b.X-a.X), Y: a.Y + t*(b.Y-a.Y)})
+		}
+	}
+	return pts
+}
+
+// traceSeedLine ведёт линию через точку в обе стороны, по направлению поля.
+func (g *Game) traceSeedLine(tree *quadTree, p Vec2) []Vec2 {
+	back := g.traceFieldLine(tree, p.X, p.Y, -1)
+	slices.Reverse(back)
+	line := append(back, p)
+	return append(line, g.traceFieldLine(tree, p.X, p.Y, +1)...)
+}
+
+func (g *Game) drawSeeds(screen *ebiten.Image) {
+	s := &g.seeding
+	if !s.tool && len(s.points) == 0 && len(s.segments) == 0 {
+		return
+	}
+	col := withAlpha(g.theme().FieldLines, 200)
+	for _, p := range s.points {
+		x, y := g.cam.toScreen(p.X, p.Y)
+		vector.DrawFilledCircle(screen, x, y, 3, col, true)
+	}
+	segs := s.segments
+	if s.drawing {
+		x, y := g.cursorWorld()
+		segs = append(slices.Clip(segs), [2]Vec2{s.from, {X: x, Y: y}})
+	}
+	for _, seg := range segs {
+		x1, y1 := g.cam.toScreen(seg[0].X, seg[0].Y)
+		x2, y2 := g.cam.toScreen(seg[1].X, seg[1].Y)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 2, col, true)
+	}
+}
+
+func (s *seeding) String() string {
+	return fmt.Sprintf(tr("%d lines per unit charge from r = %.0f, %d manual seeds"), s.perUnitQ, s.radius, len(s.seedPoints()))
+}
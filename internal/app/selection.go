@@ -0,0 +1,220 @@
+package app
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Групповая правка: Alt+протяжка выделяет заряды рамкой, протяжка за
+// выделенный заряд двигает всю группу, Alt+колесо поворачивает её вокруг
+// центра, Delete удаляет. Признак выделения хранится в самом заряде,
+// поэтому переживает добавление и удаление других зарядов.
+
+const (
+	selectRotateStep = math.Pi / 12 // поворот за щелчок колеса
+	selectRingGap    = 4            // зазор рамки выделения вокруг значка, пикс
+	hoverRingGap     = 8            // пунктир наведения снаружи рамки выделения
+	hoverDashes      = 12
+)
+
+type selection struct {
+	band     bool // тянем рамку
+	dragging bool // тянем группу
+	moved    bool // группа сдвинулась: точка отмены уже поставлена
+	from     Vec2 // начало рамки или последняя точка протяжки, мир
+}
+
+// hovered — заряд под курсором, на который подействуют щелчок,
+// протяжка, меню и клавиши правки, или -1.
+func (g *Game) hovered() int {
+	return g.chargeAt(g.cursorWorld())
+}
+
+func (g *Game) hasSelection() bool {
+	return slices.ContainsFunc(g.charges, func(c Charge) bool { return c.Selected })
+}
+
+func (g *Game) selectedCount() int {
+	n := 0
+	for _, c := range g.charges {
+		if c.Selected {
+			n++
+		}
+	}
+	return n
+}
+
+func (g *Game) clearSelection() {
+	for i := range g.charges {
+		g.charges[i].Selected = false
+	}
+}
+
+// updateSelection начинает и ведёт рамку или перетаскивание группы и
+// сообщает, забрала ли она нажатие левой кнопки.
+func (g *Game) updateSelection(leftNow bool) bool {
+	s := &g.selection
+	x, y := g.cursorWorld()
+	pressed := leftNow && !g.lastLeft
+
+	switch {
+	case s.band:
+		if !leftNow {
+			s.band = false
+			g.selectInBand(s.from, Vec2{X: x, Y: y})
+		}
+		return true
+	case s.dragging:
+		if !leftNow {
+			s.dragging = false
+			return true
+		}
+		dx, dy := x-s.from.X, y-s.from.Y
+		if dx == 0 && dy == 0 {
+			return true
+		}
+		if !s.moved {
+			// точка отмены — только при настоящем сдвиге, не на щелчке
+			g.checkpoint()
+			s.moved = true
+		}
+		for i := range g.charges {
+			if c := &g.charges[i]; c.Selected {
+				c.X, c.Y = c.X+dx, c.Y+dy
+			}
+		}
+		s.from = Vec2{X: x, Y: y}
+		g.dirty = true
+		g.conservation.resetSystem()
+		return true
+	case pressed && keyPressed(ebiten.KeyAlt):
+		s.band, s.from = true, Vec2{X: x, Y: y}
+		return true
+	case pressed && !keyPressed(ebiten.KeyControl) && !keyPressed(ebiten.KeyShift):
+		i := g.hovered()
+		if i < 0 || !g.charges[i].Selected && g.leftTool() {
+			return false
+		}
+		if g.clickCharge(i) {
+			return true
+		}
+		if !g.charges[i].Selected {
+			// щелчок по невыделенному заряду выделяет только его
+			g.clearSelection()
+			g.charges[i].Selected = true
+		}
+		s.dragging, s.moved, s.from = true, false, Vec2{X: x, Y: y}
+		return true
+	}
+	return false
+}
+
+func (g *Game) selectInBand(a, b Vec2) {
+	x0, x1 := math.Min(a.X, b.X), math.Max(a.X, b.X)
+	y0, y1 := math.Min(a.Y, b.Y), math.Max(a.Y, b.Y)
+	add := keyPressed(ebiten.KeyShift) // Shift добавляет к выделению
+	for i := range g.charges {
+		c := &g.charges[i]
+		in := c.X >= x0 && c.X <= x1 && c.Y >= y0 && c.Y <= y1
+		c.Selected = in || add && c.Selected
+	}
+}
+
+// rotateSelection поворачивает выделенные заряды вокруг их центра.
+func (g *Game) rotateSelection(angle float64) {
+	var cx, cy float64
+	n := 0
+	for _, c := range g.charges {
+		if c.Selected {
+			cx, cy, n = cx+c.X, cy+c.Y, n+1
+		}
+	}
+	if n == 0 {
+		return
+	}
+	g.checkpoint()
+	cx, cy = cx/float64(n), cy/float64(n)
+	s, co := math.Sincos(angle)
+	for i := range g.charges {
+		c := &g.charges[i]
+		if !c.Selected {
+			continue
+		}
+		dx, dy := c.X-cx, c.Y-cy
+		c.X, c.Y = cx+dx*co-dy*s, cy+dx*s+dy*co
+		c.VX, c.VY = c.VX*co-c.VY*s, c.VX*s+c.VY*co
+	}
+	g.dirty = true
+	g.conservation.resetSystem()
+}
+
+func (g *Game) deleteSelection() {
+	if !g.hasSelection() {
+		return
+	}
+	g.checkpoint()
+	g.charges = slices.DeleteFunc(g.charges, func(c Charge) bool { return c.Selected })
+	g.menu.open = false
+	g.dirty = true
+	g.equilibrium = false
+	g.conservation.resetSystem()
+}
+
+// selectionRotating — Alt+колесо занято поворотом, а не масштабом.
+func (g *Game) selectionRotating() bool {
+	return keyPressed(ebiten.KeyAlt) && g.hasSelection()
+}
+
+func (g *Game) drawSelection(screen *ebiten.Image) {
+	th := g.theme()
+	for _, c := range g.charges {
+		if !c.Selected {
+			continue
+		}
+		x, y := g.cam.toScreen(c.X, c.Y)
+		r := g.cam.px(float32(chargeGlyphRadius(c.Q))) + selectRingGap
+		vector.StrokeCircle(screen, x, y, r, 2, th.HUD, true)
+	}
+	g.drawHover(screen)
+	if s := &g.selection; s.band {
+		x0, y0 := g.cam.toScreen(s.from.X, s.from.Y)
+		cx, cy := g.cursor()
+		x1, y1 := float32(cx), float32(cy)
+		rx, ry := min(x0, x1), min(y0, y1)
+		rw, rh := max(x0, x1)-rx, max(y0, y1)-ry
+		vector.DrawFilledRect(screen, rx, ry, rw, rh, withAlpha(th.HUD, 30), false)
+		vector.StrokeRect(screen, rx, ry, rw, rh, 1, th.HUD, false)
+	}
+}
+
+// drawHover обводит пунктиром заряд под курсором. Пока тянут рамку или
+// группу, а также поверх меню и панели настроек подсветка не нужна.
+func (g *Game) drawHover(screen *ebiten.Image) {
+	s := &g.selection
+	if s.band || s.dragging || g.menu.open || g.seeding.tool || g.ruler.tool || g.protractor.tool || g.notes.tool || g.cut.active || g.surfaceView || g.view3D.active {
+		return
+	}
+	if x, y := g.cursor(); g.settings.open && image.Pt(x, y).In(g.settings.rect()) {
+		return
+	}
+	i := g.hovered()
+	if i < 0 {
+		return
+	}
+	c := g.charges[i]
+	x, y := g.cam.toScreen(c.X, c.Y)
+	r := g.cam.px(float32(chargeGlyphRadius(c.Q))) + hoverRingGap
+	col := withAlpha(g.theme().HUD, 160)
+	// пунктир, чтобы не путать со сплошной рамкой выделения
+	for k := range hoverDashes {
+		a0 := 2 * math.Pi * float64(k) / hoverDashes
+		a1 := a0 + math.Pi/hoverDashes
+		s0, c0 := math.Sincos(a0)
+		s1, c1 := math.Sincos(a1)
+		vector.StrokeLine(screen, x+r*float32(c0), y+r*float32(s0), x+r*float32(c1), y+r*float32(s1), 1.5, col, true)
+	}
+}
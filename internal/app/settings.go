@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Боковая панель настроек: то же, что и горячие клавиши, но мышью —
+// режимы отображения, физические константы и тема. Разделы сворачиваются.
+// Пока панель открыта, строки подсказок слева скрыты: она встаёт на их место.
+
+const (
+	settingsX   = 10
+	settingsY   = 30
+	settingsW   = 300
+	settingsPad = 8
+)
+
+type settingsPanel struct {
+	open    bool
+	view    bool // развёрнутые разделы
+	physics bool
+	look    bool
+	ui      widgetState
+	h       int // высота панели в прошлом кадре
+}
+
+func defaultSettings() settingsPanel {
+	return settingsPanel{view: true, physics: true, look: true}
+}
+
+func (s *settingsPanel) rect() image.Rectangle {
+	x, y := ui(settingsX), ui(settingsY)
+	return image.Rect(x, y, x+ui(settingsW), y+s.h)
+}
+
+// updateSettings обрабатывает мышь над панелью и сообщает, забрала ли
+// панель левую кнопку.
+func (g *Game) updateSettings(leftNow bool) bool {
+	s := &g.settings
+	if keyJustPressed(keySettings) {
+		s.open = !s.open
+	}
+	if !leftNow {
+		s.ui.active = nil
+	}
+	if !s.open {
+		return false
+	}
+	x, y := g.cursor()
+	u := g.settingsWidgets(nil)
+	u.mx, u.my = x, y
+	u.click = leftNow && !g.lastLeft
+	g.settingsLayout(u)
+	return s.ui.active != nil || image.Pt(x, y).In(s.rect())
+}
+
+func (g *Game) drawSettings(screen *ebiten.Image) {
+	s := &g.settings
+	if !s.open {
+		return
+	}
+	th := g.theme()
+	r := s.rect()
+	vector.DrawFilledRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), withAlpha(th.Panel, 240), false)
+	vector.StrokeRect(screen, float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), 1, th.Border, false)
+	g.settingsLayout(g.settingsWidgets(screen))
+}
+
+func (g *Game) settingsWidgets(dst *ebiten.Image) *widgets {
+	return &widgets{
+		dst: dst, th: g.theme(), st: &g.settings.ui,
+		x: ui(settingsX + settingsPad), y: ui(settingsY + settingsPad), w: ui(settingsW - 2*settingsPad),
+	}
+}
+
+// settingsLayout описывает содержимое панели; вызывается и для ввода,
+// и для отрисовки.
+func (g *Game) settingsLayout(u *widgets) {
+	s := &g.settings
+	u.label(fmt.Sprintf(tr("Settings (%s closes)"), keyLabel(keySettings)))
+
+	if u.header(tr("View"), &s.view) {
+		if u.button(fmt.Sprintf(tr("Background: %s"), g.bgMode)) {
+			g.bgMode = (g.bgMode + 1) % backgroundModeCount
+			g.dirty = true
+		}
+		if u.button(fmt.Sprintf(tr("Colormap: %s"), tr(g.palette().Name))) {
+			g.cycleColormap()
+		}
+		if u.button(fmt.Sprintf(tr("Heatmap scale: %s"), g.heat.transfer)) {
+			g.cycleTransfer()
+		}
+		if u.button(fmt.Sprintf(tr("Units: %s"), g.units)) {
+			g.cycleUnits()
+		}
+		if u.button(fmt.Sprintf(tr("Arrows: %s"), g.arrowStyle)) {
+			g.arrowStyle = (g.arrowStyle + 1) % arrowStyleCount
+		}
+		if u.sliderInt(fmt.Sprintf(tr("Lines per charge %d"), g.seeding.perUnitQ), &g.seeding.perUnitQ, 1, seedMaxPerQ) {
+			g.dirty = true
+		}
+		if u.checkbox(tr("Equipotentials"), &g.contours) {
+			g.dirty = true
+		}
+		if u.checkbox(tr("Flowing dashes"), &g.dashes) {
+			g.dirty = true
+		}
+		if u.checkbox(tr("Glow"), &g.glow) {
+			g.dirty = true
+		}
+		u.checkbox(tr("Coordinate grid"), &g.grid)
+		u.checkbox(tr("Snap to grid"), &g.snap.on)
+		u.checkbox(tr("Charge labels"), &g.labels)
+		u.checkbox(tr("Minimap"), &g.minimap.on)
+	}
+
+	if u.header(tr("Physics"), &s.physics) {
+		dyn := g.dynamics
+		if u.checkbox(tr("Dynamics"), &dyn) {
+			g.toggleDynamics()
+		}
+		u.slider(fmt.Sprintf(tr("Damping %.2f"), g.damping), &g.damping, 0, maxDamping)
+		u.slider(g.bhLabel(), &g.bhTheta, 0, bhThetaMax)
+		if u.slider(fmt.Sprintf(tr("Coulomb k %.0f"), kConst), &kConst, kConstMin, kConstMax) {
+			g.dirty = true
+			g.equilibrium = false
+			g.conservation.resetSystem()
+		}
+		u.sliderInt(fmt.Sprintf(tr("Time x%g"), g.pause.timeScale()), &g.pause.scale, 0, len(timeScales)-1)
+		u.checkbox(tr("Paused"), &g.pause.paused)
+	}
+
+	if u.header(tr("Appearance"), &s.look) {
+		if u.button(fmt.Sprintf(tr("Theme: %s"), tr(g.theme().Name))) {
+			g.cycleTheme()
+		}
+		if u.button(fmt.Sprintf(tr("Field line width %.1f px"), g.lines.FieldWidth())) {
+			g.lines.cycleField()
+		}
+		if u.button(fmt.Sprintf(tr("Arrow line width %.1f px"), g.lines.ArrowWidth())) {
+			g.lines.cycleArrow()
+		}
+		u.checkbox(tr("Antialiasing"), &g.lines.Antialias)
+		if u.checkbox(tr("Heatmap on the GPU"), &g.gpuHeat.on) {
+			g.dirty = true
+		}
+		u.checkbox(tr("Colorblind-safe colors and shapes"), &g.colorblind)
+		if u.button(fmt.Sprintf(tr("UI scale x%g"), uiScale)) {
+			cycleUIScale()
+		}
+	}
+	s.h = u.y - ui(settingsY) + ui(settingsPad)
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Привязка к сетке: новые заряды и вставка из буфера ставятся в ближайший
+// узел сетки размещения. Шаг задаётся в мировых единицах и не зависит от
+// масштаба, в отличие от координатной сетки; узлы рисуются точками.
+
+var snapSteps = []float64{10, 20, 25, 50, 100}
+
+const snapMinGap = 6.0 // мельче узлы не рисуются, пикс экрана
+
+type snapGrid struct {
+	on   bool
+	step int // индекс в snapSteps
+}
+
+func defaultSnap() snapGrid { return snapGrid{step: 3} }
+
+func (s snapGrid) spacing() float64 { return snapSteps[s.step] }
+
+// at возвращает ближайший узел, если привязка включена.
+func (s snapGrid) at(x, y float64) (float64, float64) {
+	if !s.on {
+		return x, y
+	}
+	d := s.spacing()
+	return math.Round(x/d) * d, math.Round(y/d) * d
+}
+
+func (s snapGrid) String() string {
+	if !s.on {
+		return tr("snap off")
+	}
+	return fmt.Sprintf(tr("snap %g"), s.spacing())
+}
+
+func (g *Game) drawSnapGrid(screen *ebiten.Image) {
+	s := g.snap
+	d := s.spacing()
+	if !s.on || d*g.cam.Zoom < snapMinGap {
+		return
+	}
+	x0, y0 := g.cam.toWorld(0, 0)
+	x1, y1 := g.cam.toWorld(float64(g.cam.W), float64(g.cam.H))
+	col := withAlpha(g.theme().HUD, 90)
+	for y := math.Ceil(y0/d) * d; y <= y1; y += d {
+		for x := math.Ceil(x0/d) * d; x <= x1; x += d {
+			sx, sy := g.cam.toScreen(x, y)
+			vector.DrawFilledRect(screen, sx-1, sy-1, 2, 2, col, false)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Сглаженные линии: точки трассировки прореживаются в экранных координатах
+// и соединяются сплайном Катмулла — Рома (в виде кубических кривых Безье).
+// Все линии кадра собираются в один vector.Path и рисуются одним вызовом.
+
+const splineMinGap = 4.0 // минимальное расстояние между узлами сплайна, пикс
+
+type splinePath struct {
+	path vector.Path
+	run  [][2]float32 // узлы текущего куска линии
+
+	tail    [2]float32 // последняя точка, пропущенная прореживанием
+	hasTail bool
+}
+
+// add продолжает текущий кусок линии точкой экрана.
+func (s *splinePath) add(x, y float32) {
+	p := [2]float32{x, y}
+	if n := len(s.run); n > 0 {
+		last := s.run[n-1]
+		if math.Hypot(float64(p[0]-last[0]), float64(p[1]-last[1])) < splineMinGap {
+			s.tail, s.hasTail = p, true
+			return
+		}
+	}
+	s.run = append(s.run, p)
+	s.hasTail = false
+}
+
+// end завершает кусок: переводит узлы в кривые Безье и начинает новый.
+func (s *splinePath) end() {
+	if s.hasTail {
+		s.run = append(s.run, s.tail)
+		s.hasTail = false
+	}
+	pts := s.run
+	s.run = s.run[:0]
+	if len(pts) < 2 {
+		return
+	}
+
+	s.path.MoveTo(pts[0][0], pts[0][1])
+	for i := 0; i+1 < len(pts); i++ {
+		p0 := pts[max(i-1, 0)]
+		p1, p2 := pts[i], pts[i+1]
+		p3 := pts[min(i+2, len(pts)-1)]
+		s.path.CubicTo(
+			p1[0]+(p2[0]-p0[0])/6, p1[1]+(p2[1]-p0[1])/6,
+			p2[0]-(p3[0]-p1[0])/6, p2[1]-(p3[1]-p1[1])/6,
+			p2[0], p2[1],
+		)
+	}
+}
+
+func (s *splinePath) stroke(screen *ebiten.Image, width float32, col color.Color, antialias bool) {
+	s.end()
+	op := &vector.DrawPathOptions{AntiAlias: antialias}
+	op.ColorScale.ScaleWithColor(col)
+	vector.StrokePath(screen, &s.path, &vector.StrokeOptions{
+		Width:    width,
+		LineJoin: vector.LineJoinRound,
+	}, op)
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Разделённый экран: две независимые сцены рядом, каждая со своей камерой
+// и областью вида. Мышь и клавиатура достаются половине под курсором,
+// вторая продолжает считать динамику. Правая сцена при включении —
+// копия левой, чтобы сравнивать её с изменённым вариантом.
+
+func splitW() int { return screenWidth / 2 }
+
+type splitScreen struct {
+	left  *Game
+	right *Game // nil — одна сцена на весь экран
+	views [2]*ebiten.Image
+}
+
+func newSplitScreen() *splitScreen {
+	return &splitScreen{left: NewGame()}
+}
+
+func (s *splitScreen) toggle() {
+	if s.right != nil {
+		s.right = nil
+		s.left.hideHUD = false
+		s.relayout()
+		return
+	}
+
+	r := NewGame()
+	r.charges = slices.Clone(s.left.charges)
+	r.themeIndex, r.colorblind = s.left.themeIndex, s.left.colorblind
+	s.right = r
+	for _, g := range []*Game{s.left, r} {
+		g.hideHUD = true // подсказки во всю ширину в половину не помещаются
+	}
+	s.relayout()
+}
+
+// relayout раздаёт сценам области вида под текущий размер окна.
+func (s *splitScreen) relayout() {
+	for i, v := range s.views {
+		if v != nil {
+			v.Deallocate()
+			s.views[i] = nil
+		}
+	}
+	if s.right == nil {
+		s.left.resize(screenWidth, screenHeight)
+		return
+	}
+	s.left.resize(splitW(), screenHeight)
+	s.right.viewX = splitW()
+	s.right.resize(splitW(), screenHeight)
+}
+
+func (s *splitScreen) focused() *Game {
+	if x, _ := cursorPosition(); s.right != nil && x >= splitW() {
+		return s.right
+	}
+	return s.left
+}
+
+func (s *splitScreen) Update() error {
+	pollInput()
+	typing := s.focused().notes.editing || s.focused().editor.open || s.focused().macro.naming
+	if keyJustPressed(keySplit) && s.left.scenario == nil && !typing {
+		s.toggle()
+	}
+	if keyJustPressed(keyFullscreen) && !typing {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	if s.right == nil {
+		return s.left.Update()
+	}
+	f := s.focused()
+	s.left.update(f == s.left)
+	s.right.update(f == s.right)
+	return nil
+}
+
+func (s *splitScreen) Draw(screen *ebiten.Image) {
+	if s.right == nil {
+		s.left.Draw(screen)
+		return
+	}
+
+	f := s.focused()
+	face := uiFace
+	for i, g := range []*Game{s.left, s.right} {
+		if s.views[i] == nil {
+			s.views[i] = ebiten.NewImage(splitW(), screenHeight)
+		}
+		v := s.views[i]
+		v.Clear()
+		g.Draw(v)
+
+		th := g.theme()
+		if g == f {
+			vector.StrokeRect(v, 1, 1, float32(splitW()-2), float32(screenHeight-2), 2, th.Border, false)
+		}
+		text.Draw(v, fmt.Sprintf(tr("%d charges, %s: single view"), len(g.charges), keyLabel(keySplit)), face, ui(10), ui(20), th.HUD)
+		g.drawNotice(v)
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(g.viewX), 0)
+		screen.DrawImage(v, op)
+	}
+}
+
+// Layout следует за размером окна: при изменении сцены получают новые
+// области вида и пересчитывают фон, сетки и стрелки.
+func (s *splitScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if resizeScreen(outsideWidth, outsideHeight) {
+		s.relayout()
+	}
+	return screenWidth, screenHeight
+}
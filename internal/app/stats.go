@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Отладочная панель: частота кадров и тиков, размер сцены, время
+// последнего пересчёта по частям и память. Нужна, чтобы оценивать
+// оптимизации прямо в работающем приложении, без -perf.
+
+const (
+	statsW          = 250
+	statsMemRefresh = time.Second // ReadMemStats останавливает мир, не чаще раза в секунду
+)
+
+type recomputeTimes struct {
+	lines, background, total time.Duration
+}
+
+type statsHUD struct {
+	on    bool
+	last  recomputeTimes
+	mem   runtime.MemStats
+	memAt time.Time
+}
+
+func (g *Game) drawStats(screen *ebiten.Image) {
+	s := &g.stats
+	if !s.on {
+		return
+	}
+	if time.Since(s.memAt) > statsMemRefresh {
+		runtime.ReadMemStats(&s.mem)
+		s.memAt = time.Now()
+	}
+
+	points := 0
+	for _, l := range g.fieldLines {
+		points += len(l)
+	}
+	const mb = 1 << 20
+	lines := []string{
+		fmt.Sprintf("FPS %.1f  TPS %.1f", ebiten.ActualFPS(), ebiten.ActualTPS()),
+		fmt.Sprintf(tr("charges %d  wires %d  plasma %d"), len(g.charges), len(g.wires), g.plasma.len()),
+		fmt.Sprintf(tr("field lines %d  points %d"), len(g.fieldLines), points),
+		fmt.Sprintf(tr("recompute %.1f ms"), ms(s.last.total)),
+		fmt.Sprintf(tr("  lines %.1f ms  bg %.1f ms"), ms(s.last.lines), ms(s.last.background)),
+		fmt.Sprintf(tr("heap %.1f MB  sys %.1f MB"), float64(s.mem.HeapAlloc)/mb, float64(s.mem.Sys)/mb),
+		fmt.Sprintf(tr("GC runs %d  goroutines %d"), s.mem.NumGC, runtime.NumGoroutine()),
+	}
+
+	th := g.theme()
+	lh, w := lineHeight(), 0
+	for _, l := range lines {
+		w = max(w, textWidth(l))
+	}
+	w = max(ui(statsW), w+ui(12))
+	x, y := g.cam.W-w-ui(10), ui(10)
+	if len(g.pins) > 0 {
+		y += g.pinListH() + ui(10) // под таблицей закреплённых зондов
+	}
+	h := lh*len(lines) + ui(8)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), th.Panel, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, th.Border, false)
+	for i, l := range lines {
+		text.Draw(screen, l, uiFace, x+ui(6), baseline(y+ui(4)+lh*i, lh), th.HUD)
+	}
+}
+
+func ms(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
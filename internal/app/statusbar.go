@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Строка состояния внизу вида: инструмент левой кнопки, число зарядов и
+// частиц, ход времени и масштаб. Под ней — подсказка к тому, что сделает
+// мышь сейчас; она следует за clickTool, так что меняется вместе со
+// значком у курсора.
+
+// statusBarLineH — строка шрифта с просветом; в строке состояния две строки.
+func statusBarLineH() int { return lineHeight() + ui(2) }
+
+func statusBarH() int { return 2*statusBarLineH() + ui(4) }
+
+func (t cursorTool) String() string {
+	switch t {
+	case toolAddPositive:
+		return tr("add + charge")
+	case toolAddNegative:
+		return tr("add - charge")
+	case toolProbe:
+		return tr("pin")
+	case toolDelete:
+		return tr("delete")
+	case toolSeed:
+		return tr("seed lines")
+	case toolRuler:
+		return tr("ruler")
+	case toolAngle:
+		return tr("angle")
+	case toolNote:
+		return tr("notes")
+	case toolDrag:
+		return tr("move")
+	case toolSelect:
+		return tr("select")
+	default:
+		return "—"
+	}
+}
+
+// statusHint — подсказка к инструменту tool.
+func (g *Game) statusHint(tool cursorTool) string {
+	switch tool {
+	case toolAddPositive:
+		return fmt.Sprintf(tr("Click: + charge, Shift+click: - charge, Ctrl+click: pin, right click: charge menu, Alt+drag: select, %s: test charge, %s: keys"),
+			keyLabel(keyTestParticle), keyLabel(keyHelp))
+	case toolAddNegative:
+		return tr("Shift+click: - charge")
+	case toolProbe:
+		return tr("Ctrl+click: pin a probe on empty space, pin or release the charge under the cursor")
+	case toolDelete:
+		return tr("Ctrl+Shift+click: delete the charge under the cursor")
+	case toolDrag:
+		if g.selection.dragging {
+			return tr("Release to drop")
+		}
+		return tr("Drag: move, double-click: exact X, Y and Q, wheel: Q, right click: charge menu")
+	case toolSelect:
+		return tr("Alt+drag: select charges in a box, Shift: add to the selection")
+	case toolSeed:
+		return fmt.Sprintf(tr("Seed tool: click seeds a line, drag seeds along a segment, right click removes, %s/%s: density, Shift: radius"),
+			keyLabel(keyRangeDown), keyLabel(keyRangeUp))
+	case toolRuler:
+		r := keyLabel(keyRuler)
+		return fmt.Sprintf(tr("Ruler: drag measures distance, ΔV and Δ|E|, right click removes, %s: done, Shift+%s: clear rulers"), r, r)
+	case toolAngle:
+		p := keyLabel(keyProtractor)
+		return fmt.Sprintf(tr("Angle: click the vertex, then a point on each ray; right click removes, %s: done, Shift+%s: clear angles"), p, p)
+	case toolNote:
+		t := keyLabel(keyNote)
+		return fmt.Sprintf(tr("Notes: click places or edits a note, Enter: done, Esc: cancel, right click removes, %s: done, Shift+%s: clear notes"), t, t)
+	}
+	return ""
+}
+
+func (g *Game) statusLine(tool cursorTool) string {
+	state := tr("static")
+	switch {
+	case g.pause.paused:
+		state = tr("paused")
+	case g.dynamics:
+		state = tr("running")
+	}
+	particles := g.plasma.len()
+	if g.testParticle.Live {
+		particles++
+	}
+	return fmt.Sprintf(tr("Tool: %s | charges %d | particles %d | %s | time x%g | zoom x%.2f"),
+		tool, len(g.charges), particles, state, g.pause.timeScale(), g.cam.Zoom)
+}
+
+func (g *Game) drawStatusBar(screen *ebiten.Image) {
+	th := g.theme()
+	tool := g.clickTool()
+	y, lh := g.cam.H-statusBarH(), statusBarLineH()
+	vector.DrawFilledRect(screen, 0, float32(y), float32(g.cam.W), float32(statusBarH()), withAlpha(th.Panel, 220), false)
+	vector.StrokeLine(screen, 0, float32(y), float32(g.cam.W), float32(y), 1, th.Border, false)
+
+	left := g.statusLine(tool)
+	pad := ui(10)
+	text.Draw(screen, left, uiFace, pad, baseline(y+ui(2), lh), th.HUD)
+	// справа — файл сцены и общие клавиши, если помещаются
+	right := fmt.Sprintf(tr("Ctrl+%s/%s: undo/redo, Ctrl+%s/%s: save/open %s"),
+		keyLabel(keyUndo), keyLabel(keyRedo), keyLabel(keySaveScene), keyLabel(keyLoadScene), g.scenePath)
+	if rw := textWidth(right); pad+textWidth(left)+2*pad+rw <= g.cam.W-pad {
+		text.Draw(screen, right, uiFace, g.cam.W-pad-rw, baseline(y+ui(2), lh), th.HUD)
+	}
+	text.Draw(screen, g.statusHint(tool), uiFace, pad, baseline(y+ui(2)+lh, lh), th.HUD)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Стереовывод для трёхмерных видов: сцена рисуется дважды со смещением
+// камеры на ±stereoEyeSep/2 и сводится в анаглиф (красный — левый глаз,
+// голубой — правый) или в сжатую стереопару «бок о бок» для 3D-проекторов.
+
+const stereoEyeSep = 0.06 // межглазное расстояние в долях дистанции камеры
+
+type StereoMode int
+
+const (
+	StereoOff StereoMode = iota
+	StereoAnaglyph
+	StereoSideBySide
+	stereoModeCount
+)
+
+func (m StereoMode) String() string {
+	switch m {
+	case StereoAnaglyph:
+		return tr("anaglyph (red-cyan)")
+	case StereoSideBySide:
+		return tr("side-by-side")
+	default:
+		return tr("mono")
+	}
+}
+
+func (m StereoMode) Next() StereoMode { return (m + 1) % stereoModeCount }
+
+// stereoRenderer держит внеэкранные буферы глаз между кадрами.
+type stereoRenderer struct {
+	left, right *ebiten.Image
+}
+
+func (s *stereoRenderer) buffers(w, h int) (*ebiten.Image, *ebiten.Image) {
+	if s.left == nil || s.left.Bounds().Dx() != w || s.left.Bounds().Dy() != h {
+		s.left = ebiten.NewImage(w, h)
+		s.right = ebiten.NewImage(w, h)
+	}
+	s.left.Clear()
+	s.right.Clear()
+	return s.left, s.right
+}
+
+// Draw вызывает drawEye для каждого глаза: eye = -1 левый, +1 правый,
+// 0 — обычный моно-кадр. Смещение камеры делает сам drawEye.
+func (s *stereoRenderer) Draw(screen *ebiten.Image, mode StereoMode, drawEye func(dst *ebiten.Image, eye float64)) {
+	if mode == StereoOff {
+		drawEye(screen, 0)
+		return
+	}
+
+	b := screen.Bounds()
+	left, right := s.buffers(b.Dx(), b.Dy())
+	drawEye(left, -1)
+	drawEye(right, +1)
+
+	switch mode {
+	case StereoAnaglyph:
+		screen.Clear()
+		op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter}
+		op.ColorScale.Scale(1, 0, 0, 1)
+		screen.DrawImage(left, op)
+
+		op = &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter}
+		op.ColorScale.Scale(0, 1, 1, 1)
+		screen.DrawImage(right, op)
+
+	case StereoSideBySide:
+		half := float64(b.Dx()) / 2
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(0.5, 1)
+		screen.DrawImage(left, op)
+
+		op = &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(0.5, 1)
+		op.GeoM.Translate(half, 0)
+		screen.DrawImage(right, op)
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Ленточный график пробной частицы: кинетическая энергия и скорость за
+// последние stripLen шагов динамики. Видно, как частица обменивается
+// энергией с полем, разгоняясь у одних зарядов и тормозя у других.
+
+const stripLen = 300
+
+type stripChart struct {
+	on        bool
+	t         []float64
+	ke, speed []float64
+	now       float64 // время с запуска частицы
+}
+
+func (s *stripChart) reset() {
+	s.t, s.ke, s.speed = s.t[:0], s.ke[:0], s.speed[:0]
+	s.now = 0
+}
+
+// recordStrip добавляет отсчёт после шага динамики, старые уходят влево.
+func (g *Game) recordStrip() {
+	s := &g.strip
+	if !s.on || !g.dynamics || !g.testParticle.Live {
+		return
+	}
+	p := g.testParticle
+	s.now += dynDt
+	s.t = append(s.t, s.now)
+	s.ke = append(s.ke, g.testParticleKinetic())
+	s.speed = append(s.speed, math.Hypot(p.VX, p.VY))
+	if n := len(s.t); n > stripLen {
+		s.t, s.ke, s.speed = s.t[n-stripLen:], s.ke[n-stripLen:], s.speed[n-stripLen:]
+	}
+}
+
+func (g *Game) drawStrip(screen *ebiten.Image) {
+	s := &g.strip
+	if !s.on || g.hideHUD {
+		return
+	}
+	th := g.theme()
+	p := g.hud.strip
+	p.Title = tr("Test particle")
+	if !g.dynamics {
+		p.Title = fmt.Sprintf(tr("Needs %s: dynamics"), keyLabel(keyDynamics))
+	}
+	p.draw(screen, th, s.t, []plotSeries{
+		{Name: "KE", Color: th.Positive, Y: s.ke},
+		{Name: "|v|", Color: th.Test, Y: s.speed},
+	})
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"electric-field/internal/colormap"
+)
+
+// Вид «резиновой плёнки»: V(x, y) как поверхность высот в изометрии.
+// Положительные заряды дают пики, отрицательные — ямы. Ячейки рисуются
+// от дальних к ближним, так что перекрытие решается порядком отрисовки.
+
+const (
+	surfaceCell   = 15.0
+	surfaceClampV = 100.0 // потенциал обрезается, иначе пики уходят за экран
+	surfaceHeight = 120.0 // высота пика при V = surfaceClampV, пикс
+	surfaceScale  = 0.65
+	surfaceShiftY = 30.0
+)
+
+var surfaceLight = [3]float64{-0.4, -0.5, 0.77} // направление на источник света
+
+type potentialSurface struct {
+	w, h  int
+	v     []float64 // обрезанный потенциал в узлах
+	verts []ebiten.Vertex
+	idx   []uint16
+	white *ebiten.Image
+}
+
+// project переводит точку плоскости и высоту в экранные координаты.
+func (s *potentialSurface) project(x, y, v float64) (float32, float32) {
+	const c30, s30 = 0.8660254, 0.5
+	sx := halfW + (x-y)*c30*surfaceScale
+	sy := halfH + surfaceShiftY + (x+y)*s30*surfaceScale - v/surfaceClampV*surfaceHeight
+	return float32(sx), float32(sy)
+}
+
+func clampPotential(V float64) float64 {
+	return math.Max(-surfaceClampV, math.Min(surfaceClampV, V))
+}
+
+func (g *Game) recomputeSurface() {
+	s := &g.surface
+	s.w = int(screenWidth/surfaceCell) + 1
+	s.h = int(screenHeight/surfaceCell) + 1
+	s.v = s.v[:0]
+	for j := 0; j < s.h; j++ {
+		for i := 0; i < s.w; i++ {
+			V := g.potentialAt(float64(i)*surfaceCell-halfW, float64(j)*surfaceCell-halfH)
+			s.v = append(s.v, clampPotential(V))
+		}
+	}
+
+	s.verts = s.verts[:0]
+	for j := 0; j < s.h; j++ {
+		for i := 0; i < s.w; i++ {
+			x, y := float64(i)*surfaceCell-halfW, float64(j)*surfaceCell-halfH
+			v := s.v[j*s.w+i]
+			dx, dy := s.project(x, y, v)
+
+			c := colormap.Coolwarm.Signed(v / surfaceClampV)
+			l := 0.35 + 0.65*s.shade(i, j)
+			s.verts = append(s.verts, ebiten.Vertex{
+				DstX: dx, DstY: dy,
+				ColorR: float32(float64(c.R) / 255 * l),
+				ColorG: float32(float64(c.G) / 255 * l),
+				ColorB: float32(float64(c.B) / 255 * l),
+				ColorA: 1,
+			})
+		}
+	}
+
+	// ближе к зрителю ячейки с большим i + j: рисуем по диагоналям
+	s.idx = s.idx[:0]
+	for d := 0; d <= s.w+s.h-4; d++ {
+		for i := max(0, d-(s.h-2)); i <= min(d, s.w-2); i++ {
+			j := d - i
+			a := uint16(j*s.w + i)
+			b, c, e := a+1, a+uint16(s.w)+1, a+uint16(s.w)
+			s.idx = append(s.idx, a, b, c, a, c, e)
+		}
+	}
+}
+
+// shade — ламбертово освещение по нормали из разностей высот.
+func (s *potentialSurface) shade(i, j int) float64 {
+	at := func(i, j int) float64 {
+		i = max(0, min(s.w-1, i))
+		j = max(0, min(s.h-1, j))
+		return s.v[j*s.w+i] / surfaceClampV * surfaceHeight / surfaceScale
+	}
+	nx := -(at(i+1, j) - at(i-1, j)) / (2 * surfaceCell)
+	ny := -(at(i, j+1) - at(i, j-1)) / (2 * surfaceCell)
+	n := math.Sqrt(nx*nx + ny*ny + 1)
+	dot := (nx*surfaceLight[0] + ny*surfaceLight[1] + surfaceLight[2]) / n
+	return math.Max(0, dot)
+}
+
+func (g *Game) drawSurface(screen *ebiten.Image) {
+	s := &g.surface
+	screen.Fill(color.RGBA{8, 8, 14, 255})
+	if s.white == nil {
+		s.white = ebiten.NewImage(1, 1)
+		s.white.Fill(color.White)
+	}
+	screen.DrawTriangles(s.verts, s.idx, s.white, nil)
+
+	for _, c := range g.charges {
+		x, y := s.project(c.X, c.Y, clampPotential(g.potentialAt(c.X, c.Y)))
+		col := color.RGBA{255, 80, 80, 255}
+		if c.Q < 0 {
+			col = color.RGBA{80, 80, 255, 255}
+		}
+		vector.DrawFilledCircle(screen, x, y, 4, col, true)
+	}
+
+	face := uiFace
+	text.Draw(screen, fmt.Sprintf(tr("Potential surface V(x, y), clamped to +/-%.0f. %s: back to field view"),
+		surfaceClampV, keyLabel(keySurface)), face, ui(10), ui(20), color.White)
+}
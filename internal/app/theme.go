@@ -0,0 +1,160 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Цветовые темы: тёмная для экрана, светлая для распечаток и ярких
+// аудиторий. Пользовательская тема читается из theme.json рядом с
+// макросами; незаданные в файле цвета берутся из тёмной темы.
+
+const themeFile = "theme.json"
+
+type Theme struct {
+	Name       string
+	Background color.RGBA // под теплокартой и там, где её нет
+	InvertHeat bool       // слабое поле светлое, сильное тёмное
+	FieldLines color.RGBA
+	Arrows     color.RGBA // стрелки сетки в стиле plain
+	Positive   color.RGBA
+	Negative   color.RGBA
+	Test       color.RGBA
+	HUD        color.RGBA
+	Panel      color.RGBA // подложка подсказок и меню
+	Border     color.RGBA
+}
+
+var darkTheme = Theme{
+	Name:       "dark",
+	Background: color.RGBA{0, 0, 0, 255},
+	FieldLines: color.RGBA{255, 255, 255, 180},
+	Arrows:     color.RGBA{0, 255, 0, 200},
+	Positive:   color.RGBA{255, 80, 80, 255},
+	Negative:   color.RGBA{80, 80, 255, 255},
+	Test:       color.RGBA{255, 255, 0, 255},
+	HUD:        color.RGBA{255, 255, 255, 255},
+	Panel:      color.RGBA{0, 0, 0, 190},
+	Border:     color.RGBA{180, 180, 180, 255},
+}
+
+var lightTheme = Theme{
+	Name:       "light",
+	Background: color.RGBA{255, 255, 255, 255},
+	InvertHeat: true,
+	FieldLines: color.RGBA{20, 20, 20, 200},
+	Arrows:     color.RGBA{0, 130, 0, 220},
+	Positive:   color.RGBA{210, 30, 30, 255},
+	Negative:   color.RGBA{30, 60, 210, 255},
+	Test:       color.RGBA{200, 130, 0, 255},
+	HUD:        color.RGBA{10, 10, 10, 255},
+	Panel:      color.RGBA{255, 255, 255, 210},
+	Border:     color.RGBA{90, 90, 90, 255},
+}
+
+func withAlpha(c color.RGBA, a uint8) color.RGBA {
+	c.A = a
+	return c
+}
+
+// themeJSON — формат theme.json: цвета строками "#rrggbb" или "#rrggbbaa".
+type themeJSON struct {
+	Name       string `json:"name"`
+	Background string `json:"background"`
+	InvertHeat bool   `json:"invert_heatmap"`
+	FieldLines string `json:"field_lines"`
+	Arrows     string `json:"arrows"`
+	Positive   string `json:"positive"`
+	Negative   string `json:"negative"`
+	Test       string `json:"test_charge"`
+	HUD        string `json:"hud"`
+	Panel      string `json:"panel"`
+	Border     string `json:"border"`
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 255}
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = errors.New("want #rrggbb or #rrggbbaa")
+	}
+	if err != nil {
+		return c, fmt.Errorf("color %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// loadUserTheme читает пользовательскую тему; ok = false, если файла нет.
+func loadUserTheme() (t Theme, ok bool, err error) {
+	dir, err := prefsDir()
+	if err != nil {
+		return t, false, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, themeFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, false, nil
+	}
+	if err != nil {
+		return t, false, err
+	}
+
+	var j themeJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return t, false, fmt.Errorf("parse %s: %w", themeFile, err)
+	}
+
+	t = darkTheme
+	t.Name = "custom"
+	if j.Name != "" {
+		t.Name = j.Name
+	}
+	t.InvertHeat = j.InvertHeat
+	fields := []struct {
+		s   string
+		dst *color.RGBA
+	}{
+		{j.Background, &t.Background},
+		{j.FieldLines, &t.FieldLines},
+		{j.Arrows, &t.Arrows},
+		{j.Positive, &t.Positive},
+		{j.Negative, &t.Negative},
+		{j.Test, &t.Test},
+		{j.HUD, &t.HUD},
+		{j.Panel, &t.Panel},
+		{j.Border, &t.Border},
+	}
+	for _, f := range fields {
+		if f.s == "" {
+			continue
+		}
+		if *f.dst, err = parseHexColor(f.s); err != nil {
+			return t, false, fmt.Errorf("%s: %w", themeFile, err)
+		}
+	}
+	return t, true, nil
+}
+
+func (g *Game) cycleTheme() {
+	g.themeIndex = (g.themeIndex + 1) % len(g.themes)
+	g.dirty = true // теплокарта зависит от InvertHeat
+}
+
+func (g *Game) theme() *Theme {
+	th := &g.themes[g.themeIndex]
+	if !g.colorblind {
+		return th
+	}
+	g.cbTheme = colorblindTheme(*th)
+	return &g.cbTheme
+}
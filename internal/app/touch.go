@@ -0,0 +1,111 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Сенсорный ввод для планшетов и интерактивных досок: касание ставит
+// положительный заряд, долгое нажатие — отрицательный, протяжка тащит
+// заряд под пальцем или двигает вид, два пальца масштабируют и двигают
+// вид. Жесты обрабатываются вместе с камерой, чтобы пересчёт поля, как и
+// при панорамировании мышью, откладывался до конца движения.
+
+const (
+	touchSlop      = 12.0 // сдвиг пальца, после которого касание считается протяжкой, пикс
+	touchLongPress = 30   // кадров удержания до отрицательного заряда
+)
+
+type touchInput struct {
+	ids    []ebiten.TouchID
+	active bool // один палец на экране
+	x0, y0 int  // точка касания
+	x, y   int  // позиция в прошлом кадре
+	moved  bool
+	long   bool // долгое нажатие уже сработало
+	charge int  // перетаскиваемый заряд или -1
+
+	pinch  bool
+	dist   float64 // расстояние между пальцами в прошлом кадре
+	mx, my float64 // середина между пальцами в прошлом кадре
+}
+
+func (g *Game) touchPos(id ebiten.TouchID) (int, int) {
+	x, y := ebiten.TouchPosition(id)
+	return x - g.viewX, y
+}
+
+func (g *Game) touchWorld(x, y int) (float64, float64) {
+	return g.snap.at(g.cam.toWorld(float64(x), float64(y)))
+}
+
+func (g *Game) updateTouch() {
+	t := &g.touch
+	t.ids = ebiten.AppendTouchIDs(t.ids[:0])
+	// в срезе и на поверхности касания только двигают вид
+	edit := !g.cut.active && !g.surfaceView
+
+	switch len(t.ids) {
+	case 0:
+		if t.active && !t.moved && !t.long && edit {
+			g.checkpoint()
+			wx, wy := g.touchWorld(t.x, t.y)
+			g.addCharge(wx, wy, +1)
+		}
+		t.active, t.pinch = false, false
+	case 1:
+		if t.pinch {
+			return // жест двумя пальцами кончается, когда подняты оба
+		}
+		id := t.ids[0]
+		x, y := g.touchPos(id)
+		if !t.active {
+			t.active, t.moved, t.long, t.charge = true, false, false, -1
+			t.x0, t.y0, t.x, t.y = x, y, x, y
+			if edit {
+				t.charge = g.chargeAt(g.cam.toWorld(float64(x), float64(y)))
+			}
+			return
+		}
+		if !t.moved && math.Hypot(float64(x-t.x0), float64(y-t.y0)) > touchSlop {
+			t.moved = true
+			if t.charge >= 0 && !t.long {
+				g.checkpoint()
+			}
+		}
+		switch {
+		case !t.moved:
+			if !t.long && edit && inpututil.TouchPressDuration(id) >= touchLongPress {
+				t.long = true
+				g.checkpoint()
+				wx, wy := g.touchWorld(x, y)
+				g.addCharge(wx, wy, -1)
+				t.charge = len(g.charges) - 1 // новый заряд можно сразу утащить
+			}
+		case t.charge >= 0 && t.charge < len(g.charges):
+			c := &g.charges[t.charge]
+			c.X, c.Y = g.touchWorld(x, y)
+			c.VX, c.VY = 0, 0
+			g.dirty = true
+			g.conservation.resetSystem()
+		default:
+			g.cam.X -= float64(x-t.x) / g.cam.Zoom
+			g.cam.Y -= float64(y-t.y) / g.cam.Zoom
+		}
+		t.x, t.y = x, y
+	default:
+		ax, ay := g.touchPos(t.ids[0])
+		bx, by := g.touchPos(t.ids[1])
+		mx, my := float64(ax+bx)/2, float64(ay+by)/2
+		d := math.Hypot(float64(bx-ax), float64(by-ay))
+		if t.pinch && t.dist > 0 && d > 0 {
+			g.cam.X -= (mx - t.mx) / g.cam.Zoom
+			g.cam.Y -= (my - t.my) / g.cam.Zoom
+			g.cam.zoomAt(mx, my, d/t.dist)
+		}
+		t.pinch, t.active = true, false
+		t.dist, t.mx, t.my = d, mx, my
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Направляющие: заряд-бусина на кольце или отрезке. В динамике учитывается
+// только касательная составляющая силы, нормальную компенсирует реакция.
+
+type TrackKind int
+
+const (
+	TrackRing TrackKind = iota
+	TrackSegment
+)
+
+const trackHalfLen = 150.0 // полудлина отрезка, создаваемого с клавиатуры
+
+type Track struct {
+	Kind TrackKind `json:"kind"`
+	X1   float64   `json:"x1"` // центр кольца или начало отрезка
+	Y1   float64   `json:"y1"`
+	X2   float64   `json:"x2,omitempty"` // конец отрезка
+	Y2   float64   `json:"y2,omitempty"`
+	R    float64   `json:"r,omitempty"`
+}
+
+func ringTrack(cx, cy, r float64) *Track {
+	return &Track{Kind: TrackRing, X1: cx, Y1: cy, R: r}
+}
+
+func segmentTrack(x1, y1, x2, y2 float64) *Track {
+	return &Track{Kind: TrackSegment, X1: x1, Y1: y1, X2: x2, Y2: y2}
+}
+
+// tangent возвращает единичный касательный вектор в ближайшей к (x, y) точке.
+func (t *Track) tangent(x, y float64) (float64, float64) {
+	if t.Kind == TrackRing {
+		a := math.Atan2(y-t.Y1, x-t.X1)
+		return -math.Sin(a), math.Cos(a)
+	}
+	l := math.Hypot(t.X2-t.X1, t.Y2-t.Y1)
+	return (t.X2 - t.X1) / l, (t.Y2 - t.Y1) / l
+}
+
+// project возвращает ближайшую точку направляющей.
+func (t *Track) project(x, y float64) (float64, float64) {
+	if t.Kind == TrackRing {
+		a := math.Atan2(y-t.Y1, x-t.X1)
+		return t.X1 + t.R*math.Cos(a), t.Y1 + t.R*math.Sin(a)
+	}
+	dx, dy := t.tangent(x, y)
+	l := math.Hypot(t.X2-t.X1, t.Y2-t.Y1)
+	s := math.Max(0, math.Min(l, (x-t.X1)*dx+(y-t.Y1)*dy))
+	return t.X1 + s*dx, t.Y1 + s*dy
+}
+
+// integrateOnTrack делает шаг для координаты вдоль направляющей: по кольцу
+// заряд движется по дуге, на концах отрезка останавливается неупруго.
+func (g *Game) integrateOnTrack(c *Charge, ax, ay float64) bool {
+	t := c.Track
+	tx, ty := t.tangent(c.X, c.Y)
+	at := ax*tx + ay*ty
+	vt := c.VX*tx + c.VY*ty
+	vt += (at - g.damping*vt) * dynDt
+
+	if t.Kind == TrackRing {
+		a := math.Atan2(c.Y-t.Y1, c.X-t.X1) + vt*dynDt/t.R
+		c.X, c.Y = t.X1+t.R*math.Cos(a), t.Y1+t.R*math.Sin(a)
+		tx, ty = t.tangent(c.X, c.Y)
+	} else {
+		l := math.Hypot(t.X2-t.X1, t.Y2-t.Y1)
+		s := (c.X-t.X1)*tx + (c.Y-t.Y1)*ty + vt*dynDt
+		if s <= 0 || s >= l {
+			s = math.Max(0, math.Min(l, s))
+			vt = 0
+		}
+		c.X, c.Y = t.X1+s*tx, t.Y1+s*ty
+	}
+	c.VX, c.VY = vt*tx, vt*ty
+
+	return math.Abs(vt) < equilibriumSpeed && math.Abs(at) < equilibriumAccel
+}
+
+// cycleTrackAtMouse переключает заряд под курсором: свободный → кольцо
+// вокруг центра экрана → горизонтальный отрезок → свободный.
+func (g *Game) cycleTrackAtMouse() {
+	i := g.hovered()
+	if i < 0 {
+		return
+	}
+	g.checkpoint()
+	c := &g.charges[i]
+	switch {
+	case c.Track == nil:
+		if r := math.Hypot(c.X, c.Y); r > chargeRadius {
+			c.Track = ringTrack(0, 0, r)
+		} else {
+			c.Track = segmentTrack(c.X-trackHalfLen, c.Y, c.X+trackHalfLen, c.Y)
+		}
+	case c.Track.Kind == TrackRing:
+		c.Track = segmentTrack(c.X-trackHalfLen, c.Y, c.X+trackHalfLen, c.Y)
+	default:
+		c.Track = nil
+	}
+	c.Pinned = false
+	c.VX, c.VY = 0, 0
+	g.equilibrium = false
+	g.conservation.resetSystem()
+}
+
+func (g *Game) drawTracks(screen *ebiten.Image) {
+	col := color.RGBA{200, 170, 90, 200}
+	for _, c := range g.charges {
+		t := c.Track
+		if t == nil {
+			continue
+		}
+		if t.Kind == TrackRing {
+			x, y := g.cam.toScreen(t.X1, t.Y1)
+			vector.StrokeCircle(screen, x, y, float32(t.R*g.cam.Zoom), g.cam.px(2), col, true)
+			continue
+		}
+		x1, y1 := g.cam.toScreen(t.X1, t.Y1)
+		x2, y2 := g.cam.toScreen(t.X2, t.Y2)
+		vector.StrokeLine(screen, x1, y1, x2, y2, g.cam.px(2), col, true)
+	}
+}
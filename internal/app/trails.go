@@ -0,0 +1,58 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Следы пробных частиц: каждая новая частица начинает свой след, старые
+// следы остаются до очистки. Хвост следа бледнее головы.
+
+const (
+	trailMaxPoints = 2000 // точек в одном следе, старые отбрасываются
+	trailMaxTrails = 16
+	trailMinStep   = 1.0 // не записываем точку, пока частица не сдвинулась
+)
+
+func (g *Game) startTrail() {
+	g.trails = append(g.trails, nil)
+	if len(g.trails) > trailMaxTrails {
+		g.trails = g.trails[1:]
+	}
+}
+
+func (g *Game) recordTrail() {
+	p := g.testParticle
+	if !p.Live || len(g.trails) == 0 {
+		return
+	}
+	t := &g.trails[len(g.trails)-1]
+	if n := len(*t); n > 0 && math.Hypot(p.X-(*t)[n-1].X, p.Y-(*t)[n-1].Y) < trailMinStep {
+		return
+	}
+	*t = append(*t, Vec2{X: p.X, Y: p.Y})
+	if len(*t) > trailMaxPoints {
+		*t = (*t)[len(*t)-trailMaxPoints:]
+	}
+}
+
+func (g *Game) clearTrails() {
+	g.trails = nil
+	if g.testParticle.Live {
+		g.startTrail()
+	}
+}
+
+func (g *Game) drawTrails(screen *ebiten.Image) {
+	for _, t := range g.trails {
+		for i := 1; i < len(t); i++ {
+			a := uint8(30 + 200*i/len(t))
+			x1, y1 := g.cam.toScreen(t[i-1].X, t[i-1].Y)
+			x2, y2 := g.cam.toScreen(t[i].X, t[i].Y)
+			vector.StrokeLine(screen, x1, y1, x2, y2, 1.5, color.RGBA{255, 220, 0, a}, true)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Обучение при первом запуске: несколько шагов с подсказкой вверху экрана
+// и пульсирующим кольцом там, где стоит действовать. Шаг засчитывается,
+// когда пользователь сам делает нужное действие — мышью, касанием или
+// геймпадом. Пройденное (или пропущенное) обучение отмечается файлом в
+// каталоге настроек; Shift+F1 запускает его заново.
+
+const (
+	tutorialFile       = "tutorial-done"
+	tutorialDoneFrames = 300
+	tutorialSpotOffset = 120 // пикс от центра экрана до мест первых зарядов
+	tutorialRing       = 16
+)
+
+type tutorialStep int
+
+const (
+	tutPositive tutorialStep = iota
+	tutNegative
+	tutTestParticle
+	tutProbe
+	tutDone
+)
+
+type tutorial struct {
+	active bool
+	step   tutorialStep
+	spots  [2]Vec2 // где поставлены заряды первых двух шагов
+	frame  int
+	left   int // кадров до закрытия после последнего шага
+}
+
+func (g *Game) startTutorial() {
+	g.tutorial = tutorial{active: true}
+}
+
+// tutorialDid сообщает о действии пользователя в мировой точке (x, y).
+func (g *Game) tutorialDid(s tutorialStep, x, y float64) {
+	t := &g.tutorial
+	if !t.active || t.step != s {
+		return
+	}
+	if s <= tutNegative {
+		t.spots[s] = Vec2{X: x, Y: y}
+	}
+	t.step++
+	if t.step == tutDone {
+		t.left = tutorialDoneFrames
+		markTutorialSeen()
+	}
+}
+
+func (g *Game) updateTutorial() {
+	t := &g.tutorial
+	if !t.active {
+		return
+	}
+	t.frame++
+	if t.step == tutDone {
+		t.left--
+		t.active = t.left > 0
+		return
+	}
+	if keyJustPressed(ebiten.KeyEnter) {
+		t.active = false
+		markTutorialSeen()
+	}
+}
+
+func (g *Game) tutorialText() string {
+	switch g.tutorial.step {
+	case tutPositive:
+		return tr("Click anywhere to place a positive charge")
+	case tutNegative:
+		return tr("Shift+click to place a negative charge")
+	case tutTestParticle:
+		return fmt.Sprintf(tr("Point between the charges and press %s to release a test charge"), keyLabel(keyTestParticle))
+	case tutProbe:
+		return fmt.Sprintf(tr("The box at the cursor shows E and V there; Shift+%s pins a probe"), keyLabel(keyProbe))
+	}
+	return fmt.Sprintf(tr("Done! %s lists all keys"), keyLabel(keyHelp))
+}
+
+// tutorialTarget — экранная точка, которую подсвечивает текущий шаг.
+func (g *Game) tutorialTarget() (float32, float32, bool) {
+	t := &g.tutorial
+	cx, cy := float32(g.cam.W)/2, float32(g.cam.H)/2
+	switch t.step {
+	case tutPositive:
+		return cx, cy - tutorialSpotOffset, true
+	case tutNegative:
+		return cx, cy + tutorialSpotOffset, true
+	case tutTestParticle, tutProbe:
+		a, b := t.spots[0], t.spots[1]
+		x, y := g.cam.toScreen((a.X+b.X)/2, (a.Y+b.Y)/2)
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+func (g *Game) drawTutorial(screen *ebiten.Image) {
+	t := &g.tutorial
+	if !t.active {
+		return
+	}
+	th := g.theme()
+
+	if x, y, ok := g.tutorialTarget(); ok {
+		pulse := float32(math.Sin(float64(t.frame) * 0.1))
+		r := g.cam.px(tutorialRing + 4*pulse)
+		col := th.HUD
+		switch t.step {
+		case tutPositive:
+			col = th.Positive
+		case tutNegative:
+			col = th.Negative
+		}
+		vector.StrokeCircle(screen, x, y, r, 2, col, true)
+		vector.StrokeCircle(screen, x, y, r+g.cam.px(6), 1, withAlpha(col, 90), true)
+	}
+
+	lines := []string{g.tutorialText()}
+	if t.step < tutDone {
+		lines = []string{
+			fmt.Sprintf(tr("Tutorial %d/%d"), t.step+1, tutDone),
+			lines[0],
+			tr("Enter: skip tutorial"),
+		}
+	}
+	w := 0
+	for _, l := range lines {
+		w = max(w, textWidth(l))
+	}
+	lh := lineHeight()
+	w += ui(20)
+	h := lh*len(lines) + ui(10)
+	x, y := (g.cam.W-w)/2, ui(10)
+	a := uint8(255)
+	if t.step == tutDone {
+		a = uint8(255 * min(1, float64(t.left)/60))
+	}
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), withAlpha(th.Panel, min(a, th.Panel.A)), false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, withAlpha(th.Border, a), false)
+	for i, l := range lines {
+		text.Draw(screen, l, uiFace, x+ui(10), baseline(y+ui(5)+lh*i, lh), withAlpha(th.HUD, a))
+	}
+}
+
+func tutorialSeen() bool {
+	dir, err := prefsDir()
+	if err != nil {
+		return true // без каталога настроек обучение показывалось бы каждый раз
+	}
+	_, err = os.Stat(filepath.Join(dir, tutorialFile))
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func markTutorialSeen() {
+	dir, err := prefsDir()
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, tutorialFile), nil, 0o644)
+	}
+	if err != nil {
+		log.Printf("save tutorial state: %v", err)
+	}
+}
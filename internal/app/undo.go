@@ -0,0 +1,112 @@
+package app
+
+import (
+	"slices"
+)
+
+// Отмена и повтор правок сцены. Перед каждой правкой (добавление,
+// закрепление, направляющая, провода, пресет, случайная сцена) снимается
+// копия зарядов и проводов; Ctrl+Z возвращает предыдущую, Ctrl+Y —
+// отменённую. Направляющие не меняются на месте, поэтому указатели
+// Track в копиях можно делить. Очистка сцены (C дважды) дополнительно
+// сохраняет пробную частицу и облако плазмы.
+
+const undoLimit = 100
+
+type sceneState struct {
+	charges   []Charge
+	wires     []Wire
+	particles *particleState // только у снимков перед очисткой сцены
+}
+
+type particleState struct {
+	test            Particle
+	x, y, vx, vy, q []float64
+}
+
+type history struct {
+	undo, redo []sceneState
+}
+
+func (g *Game) snapshot() sceneState {
+	return sceneState{charges: slices.Clone(g.charges), wires: slices.Clone(g.wires)}
+}
+
+func (g *Game) particleSnapshot() *particleState {
+	p := &g.plasma
+	return &particleState{
+		test: g.testParticle,
+		x:    slices.Clone(p.x),
+		y:    slices.Clone(p.y),
+		vx:   slices.Clone(p.vx),
+		vy:   slices.Clone(p.vy),
+		q:    slices.Clone(p.q),
+	}
+}
+
+// checkpoint вызывается перед правкой сцены.
+func (g *Game) checkpoint() {
+	g.pushUndo(g.snapshot())
+}
+
+func (g *Game) pushUndo(s sceneState) {
+	h := &g.history
+	h.undo = append(h.undo, s)
+	if len(h.undo) > undoLimit {
+		h.undo = slices.Delete(h.undo, 0, 1)
+	}
+	h.redo = nil
+}
+
+func (g *Game) undo() {
+	h := &g.history
+	if len(h.undo) == 0 {
+		g.notify(tr("Nothing to undo"))
+		return
+	}
+	s := h.undo[len(h.undo)-1]
+	h.redo = append(h.redo, g.snapshotFor(s))
+	g.restore(s)
+	h.undo = h.undo[:len(h.undo)-1]
+}
+
+func (g *Game) redo() {
+	h := &g.history
+	if len(h.redo) == 0 {
+		g.notify(tr("Nothing to redo"))
+		return
+	}
+	s := h.redo[len(h.redo)-1]
+	h.undo = append(h.undo, g.snapshotFor(s))
+	g.restore(s)
+	h.redo = h.redo[:len(h.redo)-1]
+}
+
+// snapshotFor снимает текущее состояние для обратного перехода к s:
+// если s несёт частицы, их нужно запомнить и сейчас.
+func (g *Game) snapshotFor(s sceneState) sceneState {
+	cur := g.snapshot()
+	if s.particles != nil {
+		cur.particles = g.particleSnapshot()
+	}
+	return cur
+}
+
+func (g *Game) restore(s sceneState) {
+	g.charges = slices.Clone(s.charges)
+	g.wires = slices.Clone(s.wires)
+	if p := s.particles; p != nil {
+		g.testParticle = p.test
+		c := &g.plasma
+		c.x, c.y = slices.Clone(p.x), slices.Clone(p.y)
+		c.vx, c.vy = slices.Clone(p.vx), slices.Clone(p.vy)
+		c.q = slices.Clone(p.q)
+		g.conservation.resetTest()
+		g.strip.reset()
+		g.clearTrails()
+	}
+	g.flashes = nil
+	g.equilibrium = false
+	g.dirty = true
+	g.conservation.resetSystem()
+}
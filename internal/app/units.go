@@ -0,0 +1,83 @@
+package app
+
+import "fmt"
+
+// Единицы показаний. В модели длина меряется в мировых единицах (пикселях
+// при масштабе 1), заряд — в безразмерных единицах, поле считается с
+// кулоновской константой kConst. Для СИ принято pxPerMeter мировых единиц
+// на метр и chargeUnit кулон на единицу заряда; поле переводится с
+// настоящей 1/(4πε0), так что показания в СИ не зависят от kConst.
+// N/C и V/m численно совпадают и отличаются только подписью.
+
+const (
+	pxPerMeter = 100
+	chargeUnit = 1e-9 // Кл, 1 нКл
+)
+
+type UnitMode int
+
+const (
+	UnitsModel UnitMode = iota
+	UnitsNC
+	UnitsVm
+	unitModeCount
+)
+
+func (m UnitMode) String() string {
+	switch m {
+	case UnitsNC:
+		return "N/C"
+	case UnitsVm:
+		return "V/m"
+	default:
+		return tr("dimensionless")
+	}
+}
+
+func (m UnitMode) si() bool { return m != UnitsModel }
+
+// field переводит модуль или проекцию поля из модели в выбранные единицы.
+func (m UnitMode) field(E float64) float64 {
+	if !m.si() {
+		return E
+	}
+	return E / kConst * coulombK * chargeUnit * pxPerMeter * pxPerMeter
+}
+
+// potential переводит потенциал из модели в выбранные единицы.
+func (m UnitMode) potential(V float64) float64 {
+	if !m.si() {
+		return V
+	}
+	return V / kConst * coulombK * chargeUnit * pxPerMeter
+}
+
+// fieldUnit и potentialUnit — подписи единиц с пробелом впереди;
+// в безразмерном режиме пусты.
+func (m UnitMode) fieldUnit() string {
+	if !m.si() {
+		return ""
+	}
+	return " " + m.String()
+}
+
+func (m UnitMode) potentialUnit() string {
+	if !m.si() {
+		return ""
+	}
+	return " V"
+}
+
+// convertAll переводит ряд значений для графика.
+func convertAll(v []float64, conv func(float64) float64) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = conv(x)
+	}
+	return out
+}
+
+func (g *Game) cycleUnits() {
+	g.units = (g.units + 1) % unitModeCount
+	g.notify(fmt.Sprintf(tr("Units: %s"), g.units))
+}
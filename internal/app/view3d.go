@@ -0,0 +1,234 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Трёхмерный вид: заряды в плоскости z = 0, силовые линии из fieldLines3D
+// и орбитальная камера с перспективной проекцией. Всё рисуется обычными
+// двумерными примитивами; глубина передаётся яркостью линий и размером
+// зарядов. Стереопара — через stereoRenderer.
+
+const (
+	orbitFocal     = 800.0 // фокусное расстояние, пикс
+	orbitNear      = 20.0
+	orbitMinDist   = 200.0
+	orbitMaxDist   = 5000.0
+	orbitMaxPitch  = 1.45
+	orbitDragSpeed = 0.01 // рад на пиксель перетаскивания
+	orbitGridStep  = 100.0
+	view3DBands    = 4 // полос глубины для яркости линий
+)
+
+type orbitCamera struct {
+	Yaw, Pitch float64 // азимут вокруг оси z и высота над плоскостью
+	Dist       float64
+}
+
+func defaultOrbit() orbitCamera {
+	return orbitCamera{Yaw: math.Pi / 2, Pitch: 0.6, Dist: 1200}
+}
+
+// orbitFrame — положение камеры и её базис: вправо, вверх, вперёд.
+// eye сдвигает камеру вбок для стереопары.
+type orbitFrame struct {
+	pos, right, up, fwd Vec3
+}
+
+func (o orbitCamera) frame(eye float64) orbitFrame {
+	sy, cy := math.Sincos(o.Yaw)
+	sp, cp := math.Sincos(o.Pitch)
+	fwd := Vec3{-cp * cy, -cp * sy, -sp}
+	right := normalize3(Vec3{-fwd.Y, fwd.X, 0}) // z × fwd
+	up := cross3(fwd, right)
+	shift := eye * stereoEyeSep * o.Dist / 2
+	pos := Vec3{
+		X: -fwd.X*o.Dist + right.X*shift,
+		Y: -fwd.Y*o.Dist + right.Y*shift,
+		Z: -fwd.Z*o.Dist + right.Z*shift,
+	}
+	return orbitFrame{pos: pos, right: right, up: up, fwd: fwd}
+}
+
+// project возвращает экранную точку и глубину; ok = false за камерой.
+func (f orbitFrame) project(p Vec3, w, h int) (sx, sy float32, depth float64, ok bool) {
+	d := Vec3{p.X - f.pos.X, p.Y - f.pos.Y, p.Z - f.pos.Z}
+	z := dot3(d, f.fwd)
+	if z < orbitNear {
+		return 0, 0, z, false
+	}
+	sx = float32(float64(w)/2 + orbitFocal*dot3(d, f.right)/z)
+	sy = float32(float64(h)/2 - orbitFocal*dot3(d, f.up)/z)
+	return sx, sy, z, true
+}
+
+func dot3(a, b Vec3) float64 { return a.X*b.X + a.Y*b.Y + a.Z*b.Z }
+
+func cross3(a, b Vec3) Vec3 {
+	return Vec3{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+func normalize3(a Vec3) Vec3 {
+	n := math.Sqrt(dot3(a, a))
+	return Vec3{a.X / n, a.Y / n, a.Z / n}
+}
+
+type view3D struct {
+	active   bool
+	orbit    orbitCamera
+	lines    [][]Vec3
+	stereo   StereoMode
+	renderer stereoRenderer
+
+	dragging     bool
+	dragX, dragY int
+}
+
+func (g *Game) toggleView3D() {
+	v := &g.view3D
+	v.active = !v.active
+	if v.active && v.orbit.Dist == 0 {
+		v.orbit = defaultOrbit()
+	}
+	g.dirty = true
+}
+
+func (g *Game) updateView3D() {
+	v := &g.view3D
+	o := &v.orbit
+
+	x, y := g.cursor()
+	if mousePressed(ebiten.MouseButtonLeft) {
+		if v.dragging {
+			o.Yaw -= float64(x-v.dragX) * orbitDragSpeed
+			o.Pitch += float64(y-v.dragY) * orbitDragSpeed
+		}
+		v.dragging = true
+	} else {
+		v.dragging = false
+	}
+	v.dragX, v.dragY = x, y
+
+	if keyPressed(ebiten.KeyArrowLeft) {
+		o.Yaw += 2 * orbitDragSpeed
+	}
+	if keyPressed(ebiten.KeyArrowRight) {
+		o.Yaw -= 2 * orbitDragSpeed
+	}
+	if keyPressed(ebiten.KeyArrowUp) {
+		o.Pitch += 2 * orbitDragSpeed
+	}
+	if keyPressed(ebiten.KeyArrowDown) {
+		o.Pitch -= 2 * orbitDragSpeed
+	}
+	o.Pitch = math.Max(-orbitMaxPitch, math.Min(orbitMaxPitch, o.Pitch))
+
+	if _, wy := wheel(); wy != 0 {
+		o.Dist = math.Max(orbitMinDist, math.Min(orbitMaxDist, o.Dist*math.Pow(camZoomStep, -wy)))
+	}
+	if keyJustPressed(keyResetView) {
+		*o = defaultOrbit()
+	}
+}
+
+func (g *Game) drawView3D(screen *ebiten.Image) {
+	v := &g.view3D
+	v.renderer.Draw(screen, v.stereo, func(dst *ebiten.Image, eye float64) {
+		g.drawView3DEye(dst, v.orbit.frame(eye))
+	})
+
+	if g.hideHUD {
+		return
+	}
+	o := keyLabel(keyView3D)
+	text.Draw(screen, fmt.Sprintf(tr("3D view: %d field lines. Drag or arrows: orbit, wheel: distance, %s: reset, Shift+%s: stereo (%s), %s: back"),
+		len(v.lines), keyLabel(keyResetView), o, v.stereo, o), uiFace, ui(10), ui(20), g.theme().HUD)
+}
+
+func (g *Game) drawView3DEye(dst *ebiten.Image, f orbitFrame) {
+	th := g.theme()
+	b := dst.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst.Fill(th.Background)
+
+	// сетка плоскости зарядов
+	grid := withAlpha(th.HUD, 40)
+	for t := -halfW; t <= halfW; t += orbitGridStep {
+		g.strokeLine3D(dst, f, Vec3{X: t, Y: -halfH}, Vec3{X: t, Y: halfH}, grid)
+	}
+	for t := -halfH; t <= halfH; t += orbitGridStep {
+		g.strokeLine3D(dst, f, Vec3{X: -halfW, Y: t}, Vec3{X: halfW, Y: t}, grid)
+	}
+
+	// ближние линии ярче: точки раскладываются по полосам глубины
+	near, far := f.depthRange()
+	var bands [view3DBands]splinePath
+	for _, line := range g.view3D.lines {
+		cur := -1
+		for _, p := range line {
+			x, y, z, ok := f.project(p, w, h)
+			band := -1
+			if ok {
+				band = min(view3DBands-1, max(0, int((z-near)/(far-near)*view3DBands)))
+			}
+			if band != cur && cur >= 0 {
+				if ok {
+					bands[cur].add(x, y) // стык полос без разрыва
+				}
+				bands[cur].end()
+			}
+			if band >= 0 {
+				bands[band].add(x, y)
+			}
+			cur = band
+		}
+		if cur >= 0 {
+			bands[cur].end()
+		}
+	}
+	for i := range bands {
+		a := float64(th.FieldLines.A) * (1 - 0.7*float64(i)/view3DBands)
+		bands[i].stroke(dst, g.lines.FieldWidth(), withAlpha(th.FieldLines, uint8(a)), g.lines.Antialias)
+	}
+
+	// заряды от дальних к ближним
+	type disc struct {
+		x, y, r float32
+		z       float64
+		q       float64
+	}
+	var discs []disc
+	for _, c := range g.charges {
+		x, y, z, ok := f.project(Vec3{X: c.X, Y: c.Y}, w, h)
+		if ok {
+			discs = append(discs, disc{x, y, float32(chargeGlyphRadius(c.Q) * orbitFocal / z), z, c.Q})
+		}
+	}
+	sort.Slice(discs, func(i, j int) bool { return discs[i].z > discs[j].z })
+	for _, d := range discs {
+		g.drawChargeGlyph(dst, d.x, d.y, d.r, d.q, 255)
+	}
+}
+
+// depthRange — глубины, между которыми делятся полосы яркости.
+func (f orbitFrame) depthRange() (float64, float64) {
+	d := math.Sqrt(dot3(f.pos, f.pos))
+	r := math.Hypot(halfW, halfH)
+	return math.Max(orbitNear, d-r), d + r
+}
+
+func (g *Game) strokeLine3D(dst *ebiten.Image, f orbitFrame, a, b Vec3, col color.RGBA) {
+	bw, bh := dst.Bounds().Dx(), dst.Bounds().Dy()
+	x1, y1, _, ok1 := f.project(a, bw, bh)
+	x2, y2, _, ok2 := f.project(b, bw, bh)
+	if ok1 && ok2 {
+		vector.StrokeLine(dst, x1, y1, x2, y2, 1, col, false)
+	}
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Виджеты в немедленном режиме: панель описывается одной функцией, которая
+// за кадр вызывается дважды — из Update с мышью (dst == nil) и из Draw с
+// картинкой. Виджеты раскладываются сверху вниз по строкам одной колонки.
+// Между кадрами помнится только перетаскиваемый ползунок (widgetState).
+
+const (
+	widgetRowH   = 18
+	widgetBox    = 11 // сторона квадрата флажка
+	widgetLabelW = 150
+)
+
+type widgetState struct {
+	active any // указатель на значение ползунка, который тянут
+}
+
+type widgets struct {
+	dst    *ebiten.Image
+	th     *Theme
+	st     *widgetState
+	x, y   int // левый верхний угол следующей строки
+	w      int
+	mx, my int
+	click  bool // левая кнопка нажата в этом кадре
+}
+
+// row отдаёт прямоугольник следующей строки.
+func (u *widgets) row() image.Rectangle {
+	h := ui(widgetRowH)
+	r := image.Rect(u.x, u.y, u.x+u.w, u.y+h)
+	u.y += h
+	return r
+}
+
+func (u *widgets) hover(r image.Rectangle) bool {
+	return u.dst == nil && image.Pt(u.mx, u.my).In(r)
+}
+
+func (u *widgets) drawText(s string, x, y int, hot bool) {
+	col := u.th.HUD
+	if hot {
+		col = u.th.Positive
+	}
+	text.Draw(u.dst, s, uiFace, x, baseline(y, ui(widgetRowH)), col)
+}
+
+// label — строка текста без ввода.
+func (u *widgets) label(s string) {
+	r := u.row()
+	if u.dst != nil {
+		u.drawText(s, r.Min.X, r.Min.Y, false)
+	}
+}
+
+// header — заголовок раздела, клик сворачивает и разворачивает его.
+func (u *widgets) header(title string, open *bool) bool {
+	r := u.row()
+	if u.hover(r) && u.click {
+		*open = !*open
+	}
+	if u.dst != nil {
+		mark := "+"
+		if *open {
+			mark = "-"
+		}
+		vector.DrawFilledRect(u.dst, float32(r.Min.X), float32(r.Min.Y)+1, float32(r.Dx()), float32(r.Dy()-2), withAlpha(u.th.HUD, 30), false)
+		u.drawText(mark+" "+title, r.Min.X+ui(2), r.Min.Y, false)
+	}
+	return *open
+}
+
+// button сообщает, нажата ли кнопка в этом кадре.
+func (u *widgets) button(label string) bool {
+	r := u.row()
+	pressed := u.hover(r) && u.click
+	if u.dst != nil {
+		vector.StrokeRect(u.dst, float32(r.Min.X)+0.5, float32(r.Min.Y)+1.5, float32(r.Dx())-1, float32(r.Dy()-3), 1, u.th.Border, false)
+		u.drawText(label, r.Min.X+ui(5), r.Min.Y, false)
+	}
+	return pressed
+}
+
+// checkbox переключает *v по клику и сообщает об изменении.
+func (u *widgets) checkbox(label string, v *bool) bool {
+	r := u.row()
+	changed := u.hover(r) && u.click
+	if changed {
+		*v = !*v
+	}
+	if u.dst != nil {
+		box, in := float32(ui(widgetBox)), uif(3)
+		bx, by := float32(r.Min.X)+1, float32(r.Min.Y)+(float32(r.Dy())-box)/2
+		vector.StrokeRect(u.dst, bx, by, box, box, 1, u.th.HUD, false)
+		if *v {
+			vector.DrawFilledRect(u.dst, bx+in, by+in, box-2*in, box-2*in, u.th.Positive, false)
+		}
+		u.drawText(label, r.Min.X+ui(widgetBox+8), r.Min.Y, false)
+	}
+	return changed
+}
+
+// drag ведёт ползунок id по дорожке track и отдаёт положение 0..1,
+// пока его тянут.
+func (u *widgets) drag(id any, track image.Rectangle) (float64, bool) {
+	if u.hover(track) && u.click {
+		u.st.active = id
+	}
+	if u.dst != nil || u.st.active != id {
+		return 0, false
+	}
+	t := float64(u.mx-track.Min.X) / float64(track.Dx())
+	return math.Max(0, math.Min(1, t)), true
+}
+
+func (u *widgets) drawSlider(s string, track image.Rectangle, t float64, hot bool) {
+	u.drawText(s, track.Min.X-ui(widgetLabelW), track.Min.Y, hot)
+	cy := float32(track.Min.Y) + float32(track.Dy())/2
+	vector.StrokeLine(u.dst, float32(track.Min.X), cy, float32(track.Max.X), cy, uif(2), u.th.Border, false)
+	kx := float32(track.Min.X) + float32(math.Max(0, math.Min(1, t)))*float32(track.Dx())
+	vector.DrawFilledCircle(u.dst, kx, cy, uif(5), u.th.HUD, true)
+}
+
+func (u *widgets) track() image.Rectangle {
+	r := u.row()
+	return image.Rect(r.Min.X+ui(widgetLabelW), r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// slider тянет *v в пределах [lo, hi]. Подпись с текущим значением
+// собирает вызывающий.
+func (u *widgets) slider(label string, v *float64, lo, hi float64) bool {
+	track := u.track()
+	if u.dst != nil {
+		u.drawSlider(label, track, (*v-lo)/(hi-lo), u.st.active == v)
+		return false
+	}
+	t, ok := u.drag(v, track)
+	if !ok {
+		return false
+	}
+	nv := lo + t*(hi-lo)
+	changed := nv != *v
+	*v = nv
+	return changed
+}
+
+// field — поле ввода с подписью; сам текст и фокус ведёт вызывающий.
+// Сообщает о щелчке по полю.
+func (u *widgets) field(label, value string, focus, bad bool) bool {
+	r := u.track()
+	clicked := u.hover(r) && u.click
+	if u.dst != nil {
+		u.drawText(label, r.Min.X-ui(widgetLabelW), r.Min.Y, false)
+		col := u.th.Border
+		switch {
+		case bad:
+			col = u.th.Positive
+		case focus:
+			col = u.th.HUD
+		}
+		vector.StrokeRect(u.dst, float32(r.Min.X)+0.5, float32(r.Min.Y)+1.5, float32(r.Dx())-1, float32(r.Dy()-3), 1, col, false)
+		u.drawText(value, r.Min.X+ui(5), r.Min.Y, false)
+	}
+	return clicked
+}
+
+// sliderInt — ползунок для целого значения.
+func (u *widgets) sliderInt(label string, v *int, lo, hi int) bool {
+	track := u.track()
+	if u.dst != nil {
+		u.drawSlider(label, track, float64(*v-lo)/float64(hi-lo), u.st.active == v)
+		return false
+	}
+	t, ok := u.drag(v, track)
+	if !ok {
+		return false
+	}
+	n := lo + int(math.Round(t*float64(hi-lo)))
+	changed := n != *v
+	*v = n
+	return changed
+}
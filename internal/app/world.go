@@ -0,0 +1,31 @@
+package app
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Область моделирования не зависит от окна: квадрат 2·worldHalf с
+// центром в начале координат. Линии поля трассируются до её края, а не
+// до края экрана, так что за экраном они тоже есть; частицы, покинувшие
+// мир, гаснут. Камера показывает часть мира и не уводит центр вида за
+// его границу.
+
+const worldHalf = 2000.0
+
+func inWorld(x, y float64) bool {
+	return math.Abs(x) <= worldHalf && math.Abs(y) <= worldHalf
+}
+
+func clampToWorld(x, y float64) (float64, float64) {
+	return math.Max(-worldHalf, math.Min(worldHalf, x)), math.Max(-worldHalf, math.Min(worldHalf, y))
+}
+
+// drawWorldBounds обводит границу мира.
+func (g *Game) drawWorldBounds(screen *ebiten.Image) {
+	x0, y0 := g.cam.toScreen(-worldHalf, -worldHalf)
+	x1, y1 := g.cam.toScreen(worldHalf, worldHalf)
+	vector.StrokeRect(screen, x0, y0, x1-x0, y1-y0, 1, withAlpha(g.theme().Border, 200), false)
+}
@@ -0,0 +1,89 @@
+// Package colormap — перцептивно равномерные палитры для тепловых карт.
+// Палитры заданы опорными точками (выборка matplotlib через 1/8) и
+// разворачиваются в таблицу из 256 цветов.
+package colormap
+
+import "image/color"
+
+const lutSize = 256
+
+type Map struct {
+	Name      string
+	Diverging bool // центр шкалы нейтральный, для знакопеременных величин
+	lut       [lutSize]color.RGBA
+}
+
+func newMap(name string, diverging bool, stops ...color.RGBA) *Map {
+	m := &Map{Name: name, Diverging: diverging}
+	n := len(stops) - 1
+	for i := range m.lut {
+		t := float64(i) / (lutSize - 1) * float64(n)
+		k := min(int(t), n-1)
+		f := t - float64(k)
+		a, b := stops[k], stops[k+1]
+		m.lut[i] = color.RGBA{
+			R: lerp(a.R, b.R, f),
+			G: lerp(a.G, b.G, f),
+			B: lerp(a.B, b.B, f),
+			A: 255,
+		}
+	}
+	return m
+}
+
+func lerp(a, b uint8, f float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5)
+}
+
+// At возвращает цвет для t из [0, 1]; значения вне диапазона обрезаются.
+func (m *Map) At(t float64) color.RGBA {
+	switch {
+	case t <= 0:
+		return m.lut[0]
+	case t >= 1:
+		return m.lut[lutSize-1]
+	}
+	return m.lut[int(t*(lutSize-1)+0.5)]
+}
+
+// Signed отображает s из [-1, 1] так, что ноль попадает в центр шкалы.
+func (m *Map) Signed(s float64) color.RGBA {
+	return m.At((s + 1) / 2)
+}
+
+var (
+	Gray = newMap("gray", false,
+		color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255})
+
+	Viridis = newMap("viridis", false,
+		color.RGBA{68, 1, 84, 255}, color.RGBA{72, 40, 120, 255},
+		color.RGBA{62, 73, 137, 255}, color.RGBA{49, 104, 142, 255},
+		color.RGBA{38, 130, 142, 255}, color.RGBA{31, 158, 137, 255},
+		color.RGBA{53, 183, 121, 255}, color.RGBA{110, 206, 88, 255},
+		color.RGBA{253, 231, 37, 255})
+
+	Plasma = newMap("plasma", false,
+		color.RGBA{13, 8, 135, 255}, color.RGBA{75, 3, 161, 255},
+		color.RGBA{125, 3, 168, 255}, color.RGBA{168, 34, 150, 255},
+		color.RGBA{203, 70, 121, 255}, color.RGBA{229, 107, 93, 255},
+		color.RGBA{248, 148, 65, 255}, color.RGBA{253, 195, 40, 255},
+		color.RGBA{240, 249, 33, 255})
+
+	Inferno = newMap("inferno", false,
+		color.RGBA{0, 0, 4, 255}, color.RGBA{27, 12, 65, 255},
+		color.RGBA{74, 12, 107, 255}, color.RGBA{120, 28, 109, 255},
+		color.RGBA{165, 44, 96, 255}, color.RGBA{207, 68, 70, 255},
+		color.RGBA{237, 105, 37, 255}, color.RGBA{251, 155, 6, 255},
+		color.RGBA{252, 255, 164, 255})
+
+	// Coolwarm — расходящаяся палитра Морленда для потенциала со знаком.
+	Coolwarm = newMap("coolwarm", true,
+		color.RGBA{59, 76, 192, 255}, color.RGBA{98, 130, 234, 255},
+		color.RGBA{141, 176, 254, 255}, color.RGBA{184, 208, 249, 255},
+		color.RGBA{221, 221, 221, 255}, color.RGBA{245, 196, 173, 255},
+		color.RGBA{244, 154, 123, 255}, color.RGBA{222, 96, 77, 255},
+		color.RGBA{180, 4, 38, 255})
+)
+
+// All — палитры в порядке переключения.
+var All = []*Map{Gray, Viridis, Plasma, Inferno, Coolwarm}
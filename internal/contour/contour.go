@@ -0,0 +1,100 @@
+// Package contour — изолинии скалярного поля на регулярной сетке
+// методом marching squares.
+package contour
+
+type Point struct {
+	X, Y float64
+}
+
+type Segment struct {
+	A, B Point
+}
+
+// Grid хранит значения в узлах построчно: узел (i, j) лежит в точке
+// (X0 + i·Step, Y0 + j·Step).
+type Grid struct {
+	W, H   int
+	X0, Y0 float64
+	Step   float64
+	V      []float64
+}
+
+func New(w, h int, x0, y0, step float64) *Grid {
+	return &Grid{W: w, H: h, X0: x0, Y0: y0, Step: step, V: make([]float64, w*h)}
+}
+
+func (g *Grid) At(i, j int) float64 { return g.V[j*g.W+i] }
+
+// Fill вычисляет f во всех узлах.
+func (g *Grid) Fill(f func(x, y float64) float64) {
+	for j := 0; j < g.H; j++ {
+		y := g.Y0 + float64(j)*g.Step
+		for i := 0; i < g.W; i++ {
+			g.V[j*g.W+i] = f(g.X0+float64(i)*g.Step, y)
+		}
+	}
+}
+
+// Range возвращает минимум и максимум по сетке.
+func (g *Grid) Range() (lo, hi float64) {
+	lo, hi = g.V[0], g.V[0]
+	for _, v := range g.V {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
+
+// Segments возвращает отрезки изолинии уровня level. Седловые ячейки
+// разрешаются по среднему значению в центре ячейки.
+func (g *Grid) Segments(level float64) []Segment {
+	var segs []Segment
+	for j := 0; j+1 < g.H; j++ {
+		for i := 0; i+1 < g.W; i++ {
+			segs = g.cell(segs, i, j, level)
+		}
+	}
+	return segs
+}
+
+func (g *Grid) cell(segs []Segment, i, j int, level float64) []Segment {
+	// углы по часовой стрелке от левого верхнего, рёбра e_k между углами k и k+1
+	v := [4]float64{g.At(i, j), g.At(i+1, j), g.At(i+1, j+1), g.At(i, j+1)}
+	x := [4]float64{0, 1, 1, 0}
+	y := [4]float64{0, 0, 1, 1}
+
+	var cross [4]Point
+	var has [4]bool
+	n := 0
+	for k := 0; k < 4; k++ {
+		a, b := v[k], v[(k+1)%4]
+		if (a > level) == (b > level) {
+			continue
+		}
+		t := (level - a) / (b - a)
+		kx := x[k] + t*(x[(k+1)%4]-x[k])
+		ky := y[k] + t*(y[(k+1)%4]-y[k])
+		cross[k] = Point{X: g.X0 + (float64(i)+kx)*g.Step, Y: g.Y0 + (float64(j)+ky)*g.Step}
+		has[k] = true
+		n++
+	}
+
+	switch n {
+	case 2:
+		var p []Point
+		for k := 0; k < 4; k++ {
+			if has[k] {
+				p = append(p, cross[k])
+			}
+		}
+		segs = append(segs, Segment{p[0], p[1]})
+	case 4:
+		center := (v[0] + v[1] + v[2] + v[3]) / 4
+		if (v[0] > level) == (center > level) {
+			// область угла 0 связана через центр, отсекаются углы 1 и 3
+			segs = append(segs, Segment{cross[0], cross[1]}, Segment{cross[2], cross[3]})
+		} else {
+			segs = append(segs, Segment{cross[3], cross[0]}, Segment{cross[1], cross[2]})
+		}
+	}
+	return segs
+}
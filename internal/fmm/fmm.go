@@ -0,0 +1,566 @@
+// Package fmm — быстрый метод мультиполей для кулоновского поля точечных
+// зарядов в плоскости: E = K·q·r/|r|³, потенциал K·q/|r|. Ядро
+// трёхмерное (заряды — точки, а не нити), поэтому комплексные разложения
+// двумерного FMM не подходят, и используются декартовы ряды Тейлора
+// порядка Order по производным 1/r.
+//
+// Квадрат, охватывающий заряды, делится на равномерные уровни 2^l×2^l.
+// Подъём: моменты листьев и их перенос к родителям (M2M). На каждом
+// уровне ячейка получает локальное разложение от ячеек своего списка
+// взаимодействия — детей соседей родителя, не соседних с ней (M2L), и
+// передаёт его детям (L2L). В точке внутри квадрата поле — локальное
+// разложение её листа плюс прямая сумма по 3×3 соседним листьям; вне
+// квадрата — спуск по моментам, как в дереве Барнса — Хата.
+package fmm
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+const (
+	maxLevel   = 9
+	maxOrder   = 12
+	firstLevel = 2 // на уровнях 0 и 1 нет разделённых ячеек
+)
+
+// Solver строит разложения по зарядам (Build) и отвечает на запросы поля
+// и потенциала. Сглаживание ближней зоны то же, что у прямой суммы:
+// при r² < MinR2 поле растёт линейно, как у однородного шара.
+type Solver struct {
+	Order    int     // порядок разложений, 1…12
+	LeafSize int     // средних зарядов на лист, по нему выбирается глубина
+	K        float64 // кулоновская постоянная
+	MinR2    float64
+
+	levels     int // номер уровня листьев; < firstLevel — только прямая сумма
+	x0, y0     float64
+	side       float64
+	xs, ys, qs []float64 // заряды, отсортированные по листьям
+	start      []int     // заряды листа k: start[k]…start[k+1]-1
+	count      [][]int   // зарядов в ячейке по уровням
+	m, l       [][]float64
+
+	terms  []term      // мультииндексы |n| ≤ Order
+	terms2 []term      // мультииндексы |n| ≤ Order+1
+	index  [][]int     // index[a][b] — номер мультииндекса (a, b) в terms2
+	binom  [][]float64 // биномиальные коэффициенты
+}
+
+type term struct{ a, b int }
+
+// N — число зарядов последнего Build.
+func (s *Solver) N() int { return len(s.qs) }
+
+// Levels — номер уровня листьев.
+func (s *Solver) Levels() int { return s.levels }
+
+func (s *Solver) prepareTerms() {
+	s.Order = min(max(s.Order, 1), maxOrder)
+	p := s.Order
+	if len(s.terms) == (p+1)*(p+2)/2 {
+		return
+	}
+	q := p + 1 // градиенту моментов нужен порядок на единицу больше
+	s.terms, s.terms2 = nil, nil
+	s.index = make([][]int, q+1)
+	for a := range s.index {
+		s.index[a] = make([]int, q+1-a)
+	}
+	// сначала младшие степени: terms — начало terms2
+	for n := 0; n <= q; n++ {
+		for a := n; a >= 0; a-- {
+			t := term{a, n - a}
+			s.index[t.a][t.b] = len(s.terms2)
+			s.terms2 = append(s.terms2, t)
+			if n <= p {
+				s.terms = append(s.terms, t)
+			}
+		}
+	}
+	s.binom = make([][]float64, q+1)
+	for n := range s.binom {
+		s.binom[n] = make([]float64, n+1)
+		s.binom[n][0], s.binom[n][n] = 1, 1
+		for k := 1; k < n; k++ {
+			s.binom[n][k] = s.binom[n-1][k-1] + s.binom[n-1][k]
+		}
+	}
+}
+
+// derivs заполняет d коэффициентами Тейлора a_n = ∂ⁿ(1/r)/n! в точке
+// (x, y) для |n| ≤ maxN по рекуррентной формуле
+// |n|·r²·a_n = −(2|n|−1)·Σ xᵢ·a_{n−eᵢ} − (|n|−1)·Σ a_{n−2eᵢ}.
+func (s *Solver) derivs(d []float64, x, y float64, maxN int) {
+	r2 := x*x + y*y
+	d[0] = 1 / math.Sqrt(r2)
+	for i, t := range s.terms2[1:] {
+		n := t.a + t.b
+		if n > maxN {
+			break
+		}
+		v := 0.0
+		if t.a > 0 {
+			v -= float64(2*n-1) * x * d[s.index[t.a-1][t.b]]
+			if t.a > 1 {
+				v -= float64(n-1) * d[s.index[t.a-2][t.b]]
+			}
+		}
+		if t.b > 0 {
+			v -= float64(2*n-1) * y * d[s.index[t.a][t.b-1]]
+			if t.b > 1 {
+				v -= float64(n-1) * d[s.index[t.a][t.b-2]]
+			}
+		}
+		d[i+1] = v / (float64(n) * r2)
+	}
+}
+
+func (s *Solver) cellSize(level int) float64 { return s.side / float64(int(1)<<level) }
+
+// center — центр ячейки (i, j) уровня level.
+func (s *Solver) center(level, i, j int) (float64, float64) {
+	h := s.cellSize(level)
+	return s.x0 + (float64(i)+0.5)*h, s.y0 + (float64(j)+0.5)*h
+}
+
+// Build раскладывает заряды (xs[i], ys[i], qs[i]) по дереву и считает
+// разложения. Срезы копируются.
+func (s *Solver) Build(xs, ys, qs []float64) {
+	s.prepareTerms()
+	n := len(qs)
+	if n == 0 {
+		s.xs, s.ys, s.qs, s.levels = nil, nil, nil, 0
+		return
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i := range qs {
+		minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+		minY, maxY = math.Min(minY, ys[i]), math.Max(maxY, ys[i])
+	}
+	// небольшой запас, чтобы крайние заряды не ложились на границу
+	s.side = math.Max(math.Max(maxX-minX, maxY-minY), 1) * 1.001
+	s.x0 = (minX+maxX)/2 - s.side/2
+	s.y0 = (minY+maxY)/2 - s.side/2
+
+	// лист не мельче диаметра сглаживания: дальние ячейки должны видеть
+	// заряды без сглаживания
+	s.levels = 0
+	for s.levels < maxLevel && n > max(s.LeafSize, 1)<<(2*s.levels) && s.cellSize(s.levels+1) >= 2*math.Sqrt(s.MinR2) {
+		s.levels++
+	}
+
+	s.sortIntoLeaves(xs, ys, qs)
+	if s.levels < firstLevel {
+		return
+	}
+	s.upward()
+	s.downward()
+}
+
+func (s *Solver) leafOf(x, y float64) (int, int) {
+	side := 1 << s.levels
+	h := s.cellSize(s.levels)
+	i := min(max(int((x-s.x0)/h), 0), side-1)
+	j := min(max(int((y-s.y0)/h), 0), side-1)
+	return i, j
+}
+
+func (s *Solver) sortIntoLeaves(xs, ys, qs []float64) {
+	side := 1 << s.levels
+	cells := side * side
+	leaf := make([]int, len(qs))
+	s.start = make([]int, cells+1)
+	for i := range qs {
+		ci, cj := s.leafOf(xs[i], ys[i])
+		leaf[i] = cj*side + ci
+		s.start[leaf[i]+1]++
+	}
+	for k := 0; k < cells; k++ {
+		s.start[k+1] += s.start[k]
+	}
+	s.xs, s.ys, s.qs = make([]float64, len(qs)), make([]float64, len(qs)), make([]float64, len(qs))
+	next := append([]int(nil), s.start[:cells]...)
+	for i, k := range leaf {
+		p := next[k]
+		next[k]++
+		s.xs[p], s.ys[p], s.qs[p] = xs[i], ys[i], qs[i]
+	}
+
+	s.count = make([][]int, s.levels+1)
+	s.count[s.levels] = make([]int, cells)
+	for k := range cells {
+		s.count[s.levels][k] = s.start[k+1] - s.start[k]
+	}
+	for lv := s.levels - 1; lv >= 0; lv-- {
+		side := 1 << lv
+		s.count[lv] = make([]int, side*side)
+		for j := 0; j < 2*side; j++ {
+			for i := 0; i < 2*side; i++ {
+				s.count[lv][(j/2)*side+i/2] += s.count[lv+1][j*2*side+i]
+			}
+		}
+	}
+}
+
+// upward: моменты M_n = Σ q·tⁿ относительно центров ячеек, t = заряд − центр.
+func (s *Solver) upward() {
+	nt := len(s.terms)
+	s.m = make([][]float64, s.levels+1)
+	for lv := firstLevel; lv <= s.levels; lv++ {
+		side := 1 << lv
+		s.m[lv] = make([]float64, side*side*nt)
+	}
+
+	side := 1 << s.levels
+	px, py := make([]float64, s.Order+1), make([]float64, s.Order+1)
+	for j := range side {
+		for i := range side {
+			k := j*side + i
+			cx, cy := s.center(s.levels, i, j)
+			m := s.m[s.levels][k*nt : (k+1)*nt]
+			for p := s.start[k]; p < s.start[k+1]; p++ {
+				powers(px, s.xs[p]-cx)
+				powers(py, s.ys[p]-cy)
+				for t, n := range s.terms {
+					m[t] += s.qs[p] * px[n.a] * py[n.b]
+				}
+			}
+		}
+	}
+
+	// M2M: M'_n = Σ_{k≤n} C(n,k)·h^{n−k}·M_k, h = центр ребёнка − центр родителя
+	for lv := s.levels - 1; lv >= firstLevel; lv-- {
+		side := 1 << lv
+		h := s.cellSize(lv + 1)
+		for j := range side {
+			for i := range side {
+				if s.count[lv][j*side+i] == 0 {
+					continue
+				}
+				parent := s.m[lv][(j*side+i)*nt : (j*side+i+1)*nt]
+				for dj := range 2 {
+					for di := range 2 {
+						ci, cj := 2*i+di, 2*j+dj
+						if s.count[lv+1][cj*2*side+ci] == 0 {
+							continue
+						}
+						child := s.m[lv+1][(cj*2*side+ci)*nt : (cj*2*side+ci+1)*nt]
+						powers(px, (float64(di)-0.5)*h)
+						powers(py, (float64(dj)-0.5)*h)
+						for t, n := range s.terms {
+							v := 0.0
+							for ka := 0; ka <= n.a; ka++ {
+								for kb := 0; kb <= n.b; kb++ {
+									v += s.binom[n.a][ka] * s.binom[n.b][kb] * px[n.a-ka] * py[n.b-kb] * child[s.index[ka][kb]]
+								}
+							}
+							parent[t] += v
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// downward: M2L по спискам взаимодействия и L2L к детям. Локальное
+// разложение — φ(центр + u) = Σ L_n·uⁿ без множителя K.
+func (s *Solver) downward() {
+	nt := len(s.terms)
+	s.l = make([][]float64, s.levels+1)
+	for lv := firstLevel; lv <= s.levels; lv++ {
+		side := 1 << lv
+		s.l[lv] = make([]float64, side*side*nt)
+	}
+
+	for lv := firstLevel; lv <= s.levels; lv++ {
+		side := 1 << lv
+		if lv > firstLevel {
+			s.l2l(lv)
+		}
+
+		// производные 1/r для всех сдвигов −3…3 ячеек
+		var shifts [7][7][]float64
+		h := s.cellSize(lv)
+		for dj := -3; dj <= 3; dj++ {
+			for di := -3; di <= 3; di++ {
+				if max(abs(di), abs(dj)) > 1 {
+					d := make([]float64, len(s.terms2))
+					s.derivs(d, float64(di)*h, float64(dj)*h, s.Order)
+					shifts[dj+3][di+3] = d
+				}
+			}
+		}
+
+		parallel(side, func(j int) {
+			for i := range side {
+				loc := s.l[lv][(j*side+i)*nt : (j*side+i+1)*nt]
+				pi, pj := i/2, j/2
+				for sj := max(2*(pj-1), 0); sj < min(2*(pj+2), side); sj++ {
+					for si := max(2*(pi-1), 0); si < min(2*(pi+2), side); si++ {
+						if max(abs(si-i), abs(sj-j)) <= 1 || s.count[lv][sj*side+si] == 0 {
+							continue
+						}
+						s.m2l(loc, s.m[lv][(sj*side+si)*nt:(sj*side+si+1)*nt], shifts[j-sj+3][i-si+3])
+					}
+				}
+			}
+		})
+	}
+}
+
+// m2l: L_n += Σ_k (−1)^|k|·C(n+k, n)·a_{n+k}(R)·M_k, |n|+|k| ≤ Order.
+func (s *Solver) m2l(loc, m, d []float64) {
+	for t, n := range s.terms {
+		v := 0.0
+		for u, k := range s.terms {
+			if n.a+n.b+k.a+k.b > s.Order {
+				break
+			}
+			c := s.binom[n.a+k.a][n.a] * s.binom[n.b+k.b][n.b] * d[s.index[n.a+k.a][n.b+k.b]] * m[u]
+			if (k.a+k.b)%2 == 1 {
+				c = -c
+			}
+			v += c
+		}
+		loc[t] += v
+	}
+}
+
+// l2l переносит локальные разложения уровня lv−1 в центры детей:
+// L'_k = Σ_{n≥k} C(n,k)·h^{n−k}·L_n.
+func (s *Solver) l2l(lv int) {
+	nt := len(s.terms)
+	side := 1 << lv
+	h := s.cellSize(lv)
+	parallel(side, func(j int) {
+		px, py := make([]float64, s.Order+1), make([]float64, s.Order+1)
+		for i := range side {
+			parent := s.l[lv-1][((j/2)*(side/2)+i/2)*nt : ((j/2)*(side/2)+i/2+1)*nt]
+			child := s.l[lv][(j*side+i)*nt : (j*side+i+1)*nt]
+			powers(px, (float64(i%2)-0.5)*h)
+			powers(py, (float64(j%2)-0.5)*h)
+			for t, k := range s.terms {
+				v := 0.0
+				for u, n := range s.terms {
+					if n.a < k.a || n.b < k.b {
+						continue
+					}
+					v += s.binom[n.a][k.a] * s.binom[n.b][k.b] * px[n.a-k.a] * py[n.b-k.b] * parent[u]
+				}
+				child[t] += v
+			}
+		}
+	})
+}
+
+func (s *Solver) inside(x, y float64) bool {
+	return x >= s.x0 && y >= s.y0 && x <= s.x0+s.side && y <= s.y0+s.side
+}
+
+// Field — поле в точке (x, y).
+func (s *Solver) Field(x, y float64) (float64, float64) {
+	if len(s.qs) == 0 {
+		return 0, 0
+	}
+	if s.levels < firstLevel {
+		return s.directField(0, len(s.qs), x, y)
+	}
+	if !s.inside(x, y) {
+		var ex, ey float64
+		for j := range 1 << firstLevel {
+			for i := range 1 << firstLevel {
+				fx, fy, _ := s.farFrom(firstLevel, i, j, x, y, true)
+				ex += fx
+				ey += fy
+			}
+		}
+		return ex, ey
+	}
+
+	ex, ey, _ := s.local(x, y, true)
+	s.neighbours(x, y, func(lo, hi int) {
+		fx, fy := s.directField(lo, hi, x, y)
+		ex += fx
+		ey += fy
+	})
+	return ex, ey
+}
+
+// Potential — потенциал в точке (x, y).
+func (s *Solver) Potential(x, y float64) float64 {
+	if len(s.qs) == 0 {
+		return 0
+	}
+	if s.levels < firstLevel {
+		return s.directPotential(0, len(s.qs), x, y)
+	}
+	if !s.inside(x, y) {
+		var v float64
+		for j := range 1 << firstLevel {
+			for i := range 1 << firstLevel {
+				_, _, p := s.farFrom(firstLevel, i, j, x, y, false)
+				v += p
+			}
+		}
+		return v
+	}
+
+	_, _, v := s.local(x, y, false)
+	s.neighbours(x, y, func(lo, hi int) {
+		v += s.directPotential(lo, hi, x, y)
+	})
+	return v
+}
+
+// neighbours вызывает near для зарядов листьев 3×3 вокруг листа точки.
+func (s *Solver) neighbours(x, y float64, near func(lo, hi int)) {
+	side := 1 << s.levels
+	i, j := s.leafOf(x, y)
+	for nj := max(j-1, 0); nj <= min(j+1, side-1); nj++ {
+		for ni := max(i-1, 0); ni <= min(i+1, side-1); ni++ {
+			if k := nj*side + ni; s.start[k] < s.start[k+1] {
+				near(s.start[k], s.start[k+1])
+			}
+		}
+	}
+}
+
+// local вычисляет локальное разложение листа точки: −K·∇φ или K·φ.
+func (s *Solver) local(x, y float64, grad bool) (ex, ey, v float64) {
+	nt := len(s.terms)
+	side := 1 << s.levels
+	i, j := s.leafOf(x, y)
+	cx, cy := s.center(s.levels, i, j)
+	loc := s.l[s.levels][(j*side+i)*nt : (j*side+i+1)*nt]
+	var px, py [maxOrder + 1]float64
+	powers(px[:s.Order+1], x-cx)
+	powers(py[:s.Order+1], y-cy)
+	for t, n := range s.terms {
+		if !grad {
+			v += loc[t] * px[n.a] * py[n.b]
+			continue
+		}
+		if n.a > 0 {
+			ex -= loc[t] * float64(n.a) * px[n.a-1] * py[n.b]
+		}
+		if n.b > 0 {
+			ey -= loc[t] * float64(n.b) * px[n.a] * py[n.b-1]
+		}
+	}
+	return s.K * ex, s.K * ey, s.K * v
+}
+
+// farFrom — вклад ячейки (i, j) уровня lv в точке вне квадрата: моменты,
+// если ячейка достаточно далеко, иначе дети; у листьев — прямая сумма.
+func (s *Solver) farFrom(lv, i, j int, x, y float64, grad bool) (ex, ey, v float64) {
+	side := 1 << lv
+	if s.count[lv][j*side+i] == 0 {
+		return 0, 0, 0
+	}
+	cx, cy := s.center(lv, i, j)
+	rx, ry := x-cx, y-cy
+	if h := s.cellSize(lv); rx*rx+ry*ry < 4*h*h {
+		if lv == s.levels {
+			k := j*side + i
+			if grad {
+				ex, ey = s.directField(s.start[k], s.start[k+1], x, y)
+				return ex, ey, 0
+			}
+			return 0, 0, s.directPotential(s.start[k], s.start[k+1], x, y)
+		}
+		for dj := range 2 {
+			for di := range 2 {
+				fx, fy, p := s.farFrom(lv+1, 2*i+di, 2*j+dj, x, y, grad)
+				ex, ey, v = ex+fx, ey+fy, v+p
+			}
+		}
+		return ex, ey, v
+	}
+
+	// φ = Σ (−1)^|k|·M_k·a_k(R); ∂φ/∂x = Σ (−1)^|k|·M_k·(k_x+1)·a_{k+e_x}(R)
+	nt := len(s.terms)
+	m := s.m[lv][(j*side+i)*nt : (j*side+i+1)*nt]
+	var d [(maxOrder + 2) * (maxOrder + 3) / 2]float64
+	s.derivs(d[:len(s.terms2)], rx, ry, s.Order+1)
+	for u, k := range s.terms {
+		c := m[u]
+		if (k.a+k.b)%2 == 1 {
+			c = -c
+		}
+		if !grad {
+			v += c * d[u]
+			continue
+		}
+		ex -= c * float64(k.a+1) * d[s.index[k.a+1][k.b]]
+		ey -= c * float64(k.b+1) * d[s.index[k.a][k.b+1]]
+	}
+	return s.K * ex, s.K * ey, s.K * v
+}
+
+func (s *Solver) directField(lo, hi int, x, y float64) (float64, float64) {
+	var ex, ey float64
+	for p := lo; p < hi; p++ {
+		dx, dy := x-s.xs[p], y-s.ys[p]
+		r2 := dx*dx + dy*dy
+		if r2 < s.MinR2 {
+			r2 = s.MinR2
+		}
+		f := s.K * s.qs[p] / (r2 * math.Sqrt(r2))
+		ex += f * dx
+		ey += f * dy
+	}
+	return ex, ey
+}
+
+func (s *Solver) directPotential(lo, hi int, x, y float64) float64 {
+	var v float64
+	r0 := math.Sqrt(s.MinR2)
+	for p := lo; p < hi; p++ {
+		dx, dy := x-s.xs[p], y-s.ys[p]
+		if r2 := dx*dx + dy*dy; r2 >= s.MinR2 {
+			v += s.K * s.qs[p] / math.Sqrt(r2)
+		} else {
+			v += s.K * s.qs[p] / (2 * r0) * (3 - r2/s.MinR2)
+		}
+	}
+	return v
+}
+
+// powers заполняет p[k] = x^k.
+func powers(p []float64, x float64) {
+	p[0] = 1
+	for k := 1; k < len(p); k++ {
+		p[k] = p[k-1] * x
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// parallel раздаёт строки 0…n-1 горутинам по числу процессоров.
+func parallel(n int, row func(j int)) {
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range rows {
+				row(j)
+			}
+		}()
+	}
+	for j := range n {
+		rows <- j
+	}
+	close(rows)
+	wg.Wait()
+}
@@ -0,0 +1,167 @@
+package fmm
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+const (
+	testK     = 2000.0
+	testMinR2 = 16.0
+)
+
+// charges — n случайных зарядов в прямоугольнике 2000×1500; mixed —
+// разных знаков, иначе только положительные.
+func charges(n int, mixed bool, seed uint64) (xs, ys, qs []float64) {
+	r := rand.New(rand.NewPCG(seed, 1))
+	xs, ys, qs = make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range n {
+		xs[i], ys[i] = r.Float64()*2000-1000, r.Float64()*1500
+		qs[i] = 0.5 + r.Float64()
+		if mixed && r.IntN(2) == 0 {
+			qs[i] = -qs[i]
+		}
+	}
+	return xs, ys, qs
+}
+
+func directField(xs, ys, qs []float64, x, y float64) (float64, float64) {
+	var ex, ey float64
+	for i := range qs {
+		dx, dy := x-xs[i], y-ys[i]
+		r2 := max(dx*dx+dy*dy, testMinR2)
+		f := testK * qs[i] / (r2 * math.Sqrt(r2))
+		ex += f * dx
+		ey += f * dy
+	}
+	return ex, ey
+}
+
+func directPotential(xs, ys, qs []float64, x, y float64) float64 {
+	var v float64
+	r0 := math.Sqrt(testMinR2)
+	for i := range qs {
+		dx, dy := x-xs[i], y-ys[i]
+		if r2 := dx*dx + dy*dy; r2 >= testMinR2 {
+			v += testK * qs[i] / math.Sqrt(r2)
+		} else {
+			v += testK * qs[i] / (2 * r0) * (3 - r2/testMinR2)
+		}
+	}
+	return v
+}
+
+// rmsError — среднеквадратичные относительные погрешности поля и
+// потенциала по точкам (px[k], py[k]): √(Σ|ошибка|² / Σ|точное|²).
+// Нормировка на сумму, а не поточечно: у разноимённых зарядов потенциал
+// проходит через ноль, и поточечная относительная ошибка там бесконечна.
+func rmsError(s *Solver, xs, ys, qs, px, py []float64) (field, potential float64) {
+	var fe, fn, pe, pn float64
+	for k := range px {
+		ex, ey := s.Field(px[k], py[k])
+		dx, dy := directField(xs, ys, qs, px[k], py[k])
+		fe += (ex-dx)*(ex-dx) + (ey-dy)*(ey-dy)
+		fn += dx*dx + dy*dy
+
+		v := s.Potential(px[k], py[k])
+		dv := directPotential(xs, ys, qs, px[k], py[k])
+		pe += (v - dv) * (v - dv)
+		pn += dv * dv
+	}
+	return math.Sqrt(fe / fn), math.Sqrt(pe / pn)
+}
+
+// queries — точки на зарядах и внутри прямоугольника зарядов, а также
+// точки вне квадрата дерева s, в том числе у самой его границы.
+func queries(s *Solver, xs, ys []float64, seed uint64) (inX, inY, outX, outY []float64) {
+	r := rand.New(rand.NewPCG(seed, 2))
+	for i := range min(len(xs), 100) {
+		inX, inY = append(inX, xs[i]), append(inY, ys[i])
+	}
+	for range 300 {
+		inX, inY = append(inX, r.Float64()*2000-1000), append(inY, r.Float64()*1500)
+	}
+	for len(outX) < 200 {
+		x, y := r.Float64()*6000-3000, r.Float64()*4500-1500
+		if len(outX)%2 == 0 {
+			x, y = r.Float64()*2400-1200, r.Float64()*1900-200 // узкая рамка у края
+		}
+		if !s.inside(x, y) {
+			outX, outY = append(outX, x), append(outY, y)
+		}
+	}
+	return inX, inY, outX, outY
+}
+
+func TestAgainstDirectSum(t *testing.T) {
+	for _, n := range []int{10, 200, 3000, 20000} {
+		for _, mixed := range []bool{true, false} {
+			xs, ys, qs := charges(n, mixed, uint64(n))
+			s := &Solver{Order: 8, LeafSize: 32, K: testK, MinR2: testMinR2}
+			s.Build(xs, ys, qs)
+			if n >= 3000 && s.Levels() < firstLevel {
+				t.Fatalf("n=%d: %d уровней, дерево не построилось", n, s.Levels())
+			}
+
+			inX, inY, outX, outY := queries(s, xs, ys, uint64(n))
+			eIn, vIn := rmsError(s, xs, ys, qs, inX, inY)
+			eOut, vOut := rmsError(s, xs, ys, qs, outX, outY)
+			t.Logf("n=%d mixed=%v levels=%d: внутри E %.1e V %.1e, снаружи E %.1e V %.1e",
+				n, mixed, s.Levels(), eIn, vIn, eOut, vOut)
+
+			limit := 5e-4
+			if s.Levels() < firstLevel {
+				limit = 1e-12 // прямая сумма
+			}
+			if eIn > limit || vIn > limit || eOut > limit || vOut > limit {
+				t.Errorf("n=%d mixed=%v: погрешность выше %.0e", n, mixed, limit)
+			}
+		}
+	}
+}
+
+// При малом числе зарядов дерево мельче firstLevel, и Field с Potential
+// обязаны совпасть с прямой суммой.
+func TestShallowTreeIsDirect(t *testing.T) {
+	xs, ys, qs := charges(10, true, 7)
+	s := &Solver{Order: 6, LeafSize: 32, K: testK, MinR2: testMinR2}
+	s.Build(xs, ys, qs)
+	if s.Levels() >= firstLevel {
+		t.Fatalf("10 зарядов дали %d уровней", s.Levels())
+	}
+	for _, p := range [][2]float64{{0, 0}, {xs[3], ys[3]}, {5000, -4000}, {xs[0] + 1, ys[0]}} {
+		ex, ey := s.Field(p[0], p[1])
+		dx, dy := directField(xs, ys, qs, p[0], p[1])
+		if ex != dx || ey != dy {
+			t.Errorf("Field%v = (%v, %v), прямая сумма (%v, %v)", p, ex, ey, dx, dy)
+		}
+		if v, dv := s.Potential(p[0], p[1]), directPotential(xs, ys, qs, p[0], p[1]); v != dv {
+			t.Errorf("Potential%v = %v, прямая сумма %v", p, v, dv)
+		}
+	}
+}
+
+// Погрешность падает с ростом порядка: проверка рядов, а не только допуска.
+func TestErrorDecreasesWithOrder(t *testing.T) {
+	xs, ys, qs := charges(3000, true, 11)
+	prev := math.Inf(1)
+	for _, order := range []int{2, 4, 6, 8, 10} {
+		s := &Solver{Order: order, LeafSize: 32, K: testK, MinR2: testMinR2}
+		s.Build(xs, ys, qs)
+		inX, inY, _, _ := queries(s, xs, ys, 11)
+		e, _ := rmsError(s, xs, ys, qs, inX, inY)
+		if e >= prev {
+			t.Errorf("порядок %d: погрешность %.1e не меньше прежней %.1e", order, e, prev)
+		}
+		prev = e
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	s := &Solver{Order: 6, LeafSize: 32, K: testK, MinR2: testMinR2}
+	s.Build(nil, nil, nil)
+	if ex, ey := s.Field(1, 2); ex != 0 || ey != 0 || s.Potential(1, 2) != 0 {
+		t.Error("поле без зарядов не нулевое")
+	}
+}
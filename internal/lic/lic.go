@@ -0,0 +1,110 @@
+// Package lic — свёртка по линиям тока (line integral convolution):
+// белый шум усредняется вдоль линий векторного поля, получается плотная
+// «волокнистая» текстура, показывающая направление поля в каждой точке.
+package lic
+
+import (
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+)
+
+// Field — направления поля в центрах пикселей, построчно. Векторы не
+// обязаны быть единичными: при интегрировании они нормируются.
+type Field struct {
+	W, H int
+	U, V []float32
+}
+
+func NewField(w, h int) *Field {
+	return &Field{W: w, H: h, U: make([]float32, w*h), V: make([]float32, w*h)}
+}
+
+// Noise — белый шум в [0, 1] с воспроизводимым зерном.
+func Noise(w, h int, seed uint64) []float32 {
+	r := rand.New(rand.NewPCG(seed, 0))
+	n := make([]float32, w*h)
+	for i := range n {
+		n[i] = r.Float32()
+	}
+	return n
+}
+
+// dir возвращает единичное направление в точке (x, y) с билинейной
+// интерполяцией; ok = false вне сетки и в нулях поля.
+func (f *Field) dir(x, y float64) (float64, float64, bool) {
+	if x < 0 || y < 0 || x > float64(f.W-1) || y > float64(f.H-1) {
+		return 0, 0, false
+	}
+	i, j := min(int(x), f.W-2), min(int(y), f.H-2)
+	tx, ty := x-float64(i), y-float64(j)
+	k := j*f.W + i
+	lerp := func(a []float32) float64 {
+		top := float64(a[k])*(1-tx) + float64(a[k+1])*tx
+		bot := float64(a[k+f.W])*(1-tx) + float64(a[k+f.W+1])*tx
+		return top*(1-ty) + bot*ty
+	}
+	u, v := lerp(f.U), lerp(f.V)
+	n := math.Hypot(u, v)
+	if n < 1e-12 {
+		return 0, 0, false
+	}
+	return u / n, v / n, true
+}
+
+// Convolve усредняет шум по length шагов в пиксель вперёд и назад вдоль
+// линии поля (RK2) через каждый пиксель. Строки считаются параллельно.
+func Convolve(f *Field, noise []float32, length int) []float32 {
+	out := make([]float32, f.W*f.H)
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range rows {
+				for i := 0; i < f.W; i++ {
+					sum := float64(noise[j*f.W+i])
+					n := 1
+					for _, sign := range [2]float64{1, -1} {
+						s, c := f.trace(float64(i), float64(j), sign, length, noise)
+						sum += s
+						n += c
+					}
+					out[j*f.W+i] = float32(sum / float64(n))
+				}
+			}
+		}()
+	}
+	for j := 0; j < f.H; j++ {
+		rows <- j
+	}
+	close(rows)
+	wg.Wait()
+	return out
+}
+
+func (f *Field) trace(x, y, sign float64, length int, noise []float32) (float64, int) {
+	var sum float64
+	n := 0
+	for s := 0; s < length; s++ {
+		u, v, ok := f.dir(x, y)
+		if !ok {
+			break
+		}
+		mu, mv, ok := f.dir(x+sign*u/2, y+sign*v/2)
+		if !ok {
+			break
+		}
+		x += sign * mu
+		y += sign * mv
+		i, j := int(x+0.5), int(y+0.5)
+		if i < 0 || j < 0 || i >= f.W || j >= f.H {
+			break
+		}
+		sum += float64(noise[j*f.W+i])
+		n++
+	}
+	return sum, n
+}
@@ -0,0 +1,108 @@
+package numdiff
+
+import (
+	"math"
+	"testing"
+)
+
+// order оценивает порядок погрешности по двум шагам h и h/2.
+func order(errH, errH2 float64) float64 {
+	return math.Log2(math.Abs(errH) / math.Abs(errH2))
+}
+
+func TestCentralOrderSin(t *testing.T) {
+	x, want := 0.7, math.Cos(0.7)
+	for _, h := range []float64{0.2, 0.1, 0.05} {
+		e1 := Central(math.Sin, x, h) - want
+		e2 := Central(math.Sin, x, h/2) - want
+		if p := order(e1, e2); math.Abs(p-2) > 0.05 {
+			t.Errorf("h=%g: порядок %.3f, ожидался 2", h, p)
+		}
+	}
+}
+
+func TestRichardsonOrderSin(t *testing.T) {
+	x, want := 0.7, math.Cos(0.7)
+	for levels := 1; levels <= 3; levels++ {
+		h := 0.8
+		e1 := Richardson(math.Sin, x, h, levels) - want
+		e2 := Richardson(math.Sin, x, h/2, levels) - want
+		if p := order(e1, e2); math.Abs(p-float64(2*levels)) > 0.2 {
+			t.Errorf("levels=%d: порядок %.3f, ожидался %d", levels, p, 2*levels)
+		}
+	}
+}
+
+func TestCentralPolynomials(t *testing.T) {
+	// квадратичный многочлен дифференцируется точно
+	quad := func(x float64) float64 { return 3*x*x - 2*x + 5 }
+	if d := Central(quad, 1.5, 0.3); math.Abs(d-7) > 1e-12 {
+		t.Errorf("Central(3x²-2x+5)'(1.5) = %v, ожидалось 7", d)
+	}
+
+	// у x³ погрешность ровно h²: при делении шага пополам она падает в 4 раза
+	cube := func(x float64) float64 { return x * x * x }
+	for _, h := range []float64{0.4, 0.2, 0.1} {
+		if e := Central(cube, 2, h) - 12; math.Abs(e-h*h) > 1e-12 {
+			t.Errorf("h=%g: погрешность %v, ожидалось %v", h, e, h*h)
+		}
+	}
+}
+
+func TestRichardsonPolynomials(t *testing.T) {
+	// levels уровней точны для многочленов степени до 2·levels
+	for levels := 1; levels <= 3; levels++ {
+		deg := 2 * levels
+		f := func(x float64) float64 { return math.Pow(x, float64(deg)) }
+		want := float64(deg) * math.Pow(1.3, float64(deg-1))
+		if d := Richardson(f, 1.3, 0.25, levels); math.Abs(d-want) > 1e-9*want {
+			t.Errorf("levels=%d: (x^%d)' = %v, ожидалось %v", levels, deg, d, want)
+		}
+
+		// на следующей степени порядок ровно 2·levels
+		g := func(x float64) float64 { return math.Pow(x, float64(deg+1)) }
+		want = float64(deg+1) * math.Pow(1.3, float64(deg))
+		e1 := Richardson(g, 1.3, 0.4, levels) - want
+		e2 := Richardson(g, 1.3, 0.2, levels) - want
+		if p := order(e1, e2); math.Abs(p-float64(deg)) > 0.05 {
+			t.Errorf("levels=%d: порядок на x^%d %.3f, ожидался %d", levels, deg+1, p, deg)
+		}
+	}
+}
+
+func TestGridGradientExact(t *testing.T) {
+	const w, h, step = 7, 5, 0.5
+	fields := []struct {
+		name   string
+		f      func(x, y float64) float64
+		fx, fy func(x, y float64) float64
+	}{
+		{"линейное",
+			func(x, y float64) float64 { return 2*x - 3*y + 1 },
+			func(x, y float64) float64 { return 2 },
+			func(x, y float64) float64 { return -3 }},
+		{"квадратичное",
+			func(x, y float64) float64 { return x*x - 2*x*y + 0.5*y*y + x },
+			func(x, y float64) float64 { return 2*x - 2*y + 1 },
+			func(x, y float64) float64 { return -2*x + y }},
+	}
+	for _, fc := range fields {
+		values := make([]float64, w*h)
+		for j := range h {
+			for i := range w {
+				values[j*w+i] = fc.f(float64(i)*step, float64(j)*step)
+			}
+		}
+		ddx, ddy := GridGradient(values, w, h, step)
+		for j := range h {
+			for i := range w {
+				x, y := float64(i)*step, float64(j)*step
+				k := j*w + i
+				if math.Abs(ddx[k]-fc.fx(x, y)) > 1e-9 || math.Abs(ddy[k]-fc.fy(x, y)) > 1e-9 {
+					t.Errorf("%s поле, узел (%d, %d): (%v, %v), ожидалось (%v, %v)",
+						fc.name, i, j, ddx[k], ddy[k], fc.fx(x, y), fc.fy(x, y))
+				}
+			}
+		}
+	}
+}
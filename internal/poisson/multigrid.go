@@ -0,0 +1,214 @@
+package poisson
+
+import "math"
+
+// Многосеточный метод: SOR быстро гасит мелкомасштабную ошибку, а плавную
+// — за сотни итераций, число которых растёт с размером сетки. V-цикл
+// сглаживает ошибку несколькими проходами Гаусса — Зейделя, переносит
+// невязку на вдвое более грубую сетку, где плавная ошибка становится
+// мелкомасштабной, решает там уравнение для поправки и интерполирует её
+// обратно. Цикл стоит несколько проходов SOR и уменьшает невязку на
+// порядок независимо от размера сетки.
+//
+// Огрубление требует нечётного числа узлов: узел (I, J) грубой сетки
+// совпадает с узлом (2I, 2J) мелкой. На 2^k + 1 узлах иерархия доходит
+// до 5×5.
+
+const (
+	mgPreSmooth    = 2
+	mgPostSmooth   = 2
+	mgCoarsest     = 5  // сторону такой сетки не огрубляем
+	mgCoarseSweeps = 30 // проходов SOR на самой грубой сетке
+)
+
+// level — одна сетка иерархии: потенциал или поправка v, правая часть f
+// уравнения -∇²v = f и невязка r.
+type level struct {
+	w, h    int
+	h2      float64
+	v, f, r []float64
+}
+
+func newLevel(w, h int, step float64) *level {
+	n := w * h
+	return &level{w: w, h: h, h2: step * step, v: make([]float64, n), f: make([]float64, n), r: make([]float64, n)}
+}
+
+// coarsenable — сетку можно огрубить вдвое с совпадением узлов.
+func coarsenable(w, h int) bool {
+	return w%2 == 1 && h%2 == 1 && min(w, h) > mgCoarsest
+}
+
+// hierarchy возвращает уровни от сетки g (level 0 разделяет с ней V)
+// до самой грубой, переиспользуя их между решениями.
+func (g *Grid) hierarchy() []*level {
+	if len(g.levels) > 0 {
+		g.levels[0].v = g.V
+		return g.levels
+	}
+	l := newLevel(g.W, g.H, g.Step)
+	l.v = g.V
+	step := g.Step
+	g.levels = []*level{l}
+	for coarsenable(l.w, l.h) {
+		step *= 2
+		l = newLevel((l.w+1)/2, (l.h+1)/2, step)
+		g.levels = append(g.levels, l)
+	}
+	return g.levels
+}
+
+// Multigrid решает уравнение V-циклами, начиная с текущего V (тёплый
+// старт), пока невязка, отнесённая к max|ρ/ε0|, не станет меньше tol.
+// Если сетку нельзя огрубить, сводится к проходам SOR.
+func (g *Grid) Multigrid(invEps0, tol float64, maxCycles int) {
+	levels := g.hierarchy()
+	top := levels[0]
+	scale := g.source(top.f, invEps0)
+	if scale == 0 {
+		clear(g.V)
+		g.Iterations, g.Residual = 0, 0
+		return
+	}
+
+	for g.Iterations = 0; ; g.Iterations++ {
+		g.Residual = top.residual(g.Boundary) / scale
+		if g.Residual < tol || g.Iterations == maxCycles {
+			break
+		}
+		g.vcycle(levels)
+	}
+	g.removeMean()
+}
+
+func (g *Grid) vcycle(levels []*level) {
+	l := levels[0]
+	if len(levels) == 1 {
+		omega := OptimalOmega(max(l.w, l.h))
+		for range mgCoarseSweeps {
+			l.smooth(g.Boundary, omega)
+		}
+		return
+	}
+	for range mgPreSmooth {
+		l.smooth(g.Boundary, 1)
+	}
+	l.residual(g.Boundary)
+
+	c := levels[1]
+	c.restrict(l)
+	if g.Boundary == Neumann {
+		compatible(c.f, c.w, c.h)
+	}
+	clear(c.v)
+	g.vcycle(levels[1:])
+	l.prolongAdd(c)
+
+	for range mgPostSmooth {
+		l.smooth(g.Boundary, 1)
+	}
+}
+
+// at — значение v в узле (i, j) с учётом границы: вне сетки при условии
+// Дирихле ноль, при условии Неймана — отражение от края.
+func (l *level) at(i, j int, b Boundary) float64 {
+	if b == Neumann {
+		i, j = mirror(i, l.w), mirror(j, l.h)
+	} else if i < 0 || i >= l.w || j < 0 || j >= l.h {
+		return 0
+	}
+	return l.v[j*l.w+i]
+}
+
+// boundary — узел лежит на краю сетки, где у соседей нужна проверка.
+func (l *level) boundary(i, j int) bool {
+	return i == 0 || j == 0 || i == l.w-1 || j == l.h-1
+}
+
+// smooth — один красно-чёрный проход с параметром релаксации omega
+// (1 — Гаусс — Зейдель).
+func (l *level) smooth(b Boundary, omega float64) {
+	w := l.w
+	for color := range 2 {
+		for j := range l.h {
+			for i := (j + color) % 2; i < w; i += 2 {
+				k := j*w + i
+				var sum float64
+				if l.boundary(i, j) {
+					if b == Dirichlet {
+						l.v[k] = 0
+						continue
+					}
+					sum = l.at(i-1, j, b) + l.at(i+1, j, b) + l.at(i, j-1, b) + l.at(i, j+1, b)
+				} else {
+					sum = l.v[k-1] + l.v[k+1] + l.v[k-w] + l.v[k+w]
+				}
+				l.v[k] += omega * ((sum-4*l.v[k])/l.h2 + l.f[k]) * l.h2 / 4
+			}
+		}
+	}
+}
+
+// residual заполняет r = f + ∇²v и возвращает max|r|.
+func (l *level) residual(b Boundary) float64 {
+	w := l.w
+	maxRes := 0.0
+	for j := range l.h {
+		for i := range w {
+			k := j*w + i
+			var sum float64
+			if l.boundary(i, j) {
+				if b == Dirichlet {
+					l.r[k] = 0
+					continue
+				}
+				sum = l.at(i-1, j, b) + l.at(i+1, j, b) + l.at(i, j-1, b) + l.at(i, j+1, b)
+			} else {
+				sum = l.v[k-1] + l.v[k+1] + l.v[k-w] + l.v[k+w]
+			}
+			l.r[k] = (sum-4*l.v[k])/l.h2 + l.f[k]
+			maxRes = math.Max(maxRes, math.Abs(l.r[k]))
+		}
+	}
+	return maxRes
+}
+
+// restrict переносит невязку мелкой сетки fine в правую часть l полным
+// взвешиванием (1/4 узлу, 1/8 соседям по сторонам, 1/16 по диагонали):
+// в отличие от простой выборки, заряд в узлах, которых нет на грубой
+// сетке, не теряется.
+func (l *level) restrict(fine *level) {
+	at := func(i, j int) float64 {
+		return fine.r[mirror(j, fine.h)*fine.w+mirror(i, fine.w)]
+	}
+	for J := range l.h {
+		for I := range l.w {
+			i, j := 2*I, 2*J
+			l.f[J*l.w+I] = (4*at(i, j) +
+				2*(at(i-1, j)+at(i+1, j)+at(i, j-1)+at(i, j+1)) +
+				at(i-1, j-1) + at(i+1, j-1) + at(i-1, j+1) + at(i+1, j+1)) / 16
+		}
+	}
+}
+
+// prolongAdd добавляет к v билинейно интерполированную поправку
+// с грубой сетки coarse.
+func (l *level) prolongAdd(coarse *level) {
+	for j := range l.h {
+		J, oy := j/2, j%2
+		for i := range l.w {
+			I, ox := i/2, i%2
+			k := J*coarse.w + I
+			e := coarse.v[k]
+			switch {
+			case ox == 1 && oy == 1:
+				e = (e + coarse.v[k+1] + coarse.v[k+coarse.w] + coarse.v[k+coarse.w+1]) / 4
+			case ox == 1:
+				e = (e + coarse.v[k+1]) / 2
+			case oy == 1:
+				e = (e + coarse.v[k+coarse.w]) / 2
+			}
+			l.v[j*l.w+i] += e
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package poisson решает уравнение Пуассона ∇²V = -ρ/ε0 на регулярной
+// двумерной сетке методом последовательной верхней релаксации (SOR,
+// красно-чёрное упорядочивание) или V-циклами многосеточного метода и
+// восстанавливает E = -∇V разностями.
+package poisson
+
+import (
+	"math"
+
+	"electric-field/internal/numdiff"
+)
+
+type Boundary int
+
+const (
+	// Dirichlet — заземлённая граница, V = 0.
+	Dirichlet Boundary = iota
+	// Neumann — изолированная граница, ∂V/∂n = 0. Потенциал определён
+	// с точностью до константы; средний заряд вычитается для совместности.
+	Neumann
+)
+
+func (b Boundary) String() string {
+	if b == Neumann {
+		return "Neumann (dV/dn = 0)"
+	}
+	return "Dirichlet (V = 0)"
+}
+
+// Grid хранит сетку W×H узлов с шагом Step; узел (i, j) находится в
+// точке (X0 + i·Step, Y0 + j·Step). Массивы построчные: k = j·W + i.
+type Grid struct {
+	W, H     int
+	Step     float64
+	X0, Y0   float64
+	Boundary Boundary
+
+	Rho    []float64
+	V      []float64
+	Ex, Ey []float64
+
+	Iterations int // итераций SOR или V-циклов последнего решения
+	Residual   float64
+
+	levels []*level // иерархия многосеточного метода, создаётся при первом решении
+}
+
+func New(w, h int, step, x0, y0 float64) *Grid {
+	n := w * h
+	return &Grid{
+		W: w, H: h, Step: step, X0: x0, Y0: y0,
+		Rho: make([]float64, n),
+		V:   make([]float64, n),
+		Ex:  make([]float64, n),
+		Ey:  make([]float64, n),
+	}
+}
+
+func (g *Grid) ClearCharge() {
+	clear(g.Rho)
+}
+
+// Deposit распределяет заряд q по четырём соседним узлам (cloud-in-cell);
+// плотность в узле равна заряду, делённому на площадь ячейки.
+func (g *Grid) Deposit(x, y, q float64) {
+	fx := (x - g.X0) / g.Step
+	fy := (y - g.Y0) / g.Step
+	i, j := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(i), fy-float64(j)
+
+	area := g.Step * g.Step
+	add := func(i, j int, w float64) {
+		if i >= 0 && i < g.W && j >= 0 && j < g.H {
+			g.Rho[j*g.W+i] += q * w / area
+		}
+	}
+	add(i, j, (1-tx)*(1-ty))
+	add(i+1, j, tx*(1-ty))
+	add(i, j+1, (1-tx)*ty)
+	add(i+1, j+1, tx*ty)
+}
+
+// OptimalOmega — параметр релаксации, близкий к оптимальному для
+// модельной задачи на квадрате со стороной n узлов.
+func OptimalOmega(n int) float64 {
+	return 2 / (1 + math.Sin(math.Pi/float64(n)))
+}
+
+// Solve выполняет итерации SOR, начиная с текущего V (тёплый старт),
+// пока максимальная невязка, отнесённая к max|ρ/ε0|, не станет меньше tol.
+func (g *Grid) Solve(invEps0, omega, tol float64, maxIter int) {
+	h2 := g.Step * g.Step
+	W, H := g.W, g.H
+
+	rhs := make([]float64, len(g.Rho))
+	scale := g.source(rhs, invEps0)
+	if scale == 0 {
+		clear(g.V)
+		g.Iterations, g.Residual = 0, 0
+		return
+	}
+
+	at := func(i, j int) float64 {
+		if g.Boundary == Neumann {
+			i, j = mirror(i, W), mirror(j, H)
+		} else if i < 0 || i >= W || j < 0 || j >= H {
+			return 0
+		}
+		return g.V[j*W+i]
+	}
+
+	g.Iterations = 0
+	for it := 0; it < maxIter; it++ {
+		maxRes := 0.0
+		for color := 0; color < 2; color++ {
+			for j := 0; j < H; j++ {
+				for i := (j + color) % 2; i < W; i += 2 {
+					k := j*W + i
+					if g.Boundary == Dirichlet && (i == 0 || j == 0 || i == W-1 || j == H-1) {
+						g.V[k] = 0
+						continue
+					}
+					sum := at(i-1, j) + at(i+1, j) + at(i, j-1) + at(i, j+1)
+					// невязка дискретного лапласиана: (Σ - 4V)/h² + rhs
+					res := (sum-4*g.V[k])/h2 + rhs[k]
+					maxRes = math.Max(maxRes, math.Abs(res))
+					g.V[k] += omega * res * h2 / 4
+				}
+			}
+		}
+		g.Iterations = it + 1
+		g.Residual = maxRes / scale
+		if g.Residual < tol {
+			break
+		}
+	}
+	g.removeMean()
+}
+
+// source заполняет rhs = ρ/ε0 (при условии Неймана — без среднего, иначе
+// задача несовместна) и возвращает max|rhs|.
+func (g *Grid) source(rhs []float64, invEps0 float64) float64 {
+	for k, r := range g.Rho {
+		rhs[k] = r * invEps0
+	}
+	if g.Boundary == Neumann {
+		compatible(rhs, g.W, g.H)
+	}
+	scale := 0.0
+	for _, r := range rhs {
+		scale = math.Max(scale, math.Abs(r))
+	}
+	return scale
+}
+
+// mirror отражает индекс за краем сетки из n узлов относительно
+// граничного узла: условие Неймана второго порядка, V(-1) = V(1).
+func mirror(i, n int) int {
+	switch {
+	case i < 0:
+		return -i
+	case i >= n:
+		return 2*(n-1) - i
+	}
+	return i
+}
+
+// compatible вычитает из a среднее с весами правила трапеций (½ на краю,
+// ¼ в углу): при отражённой границе именно такая сумма правой части
+// должна быть нулевой.
+func compatible(a []float64, w, h int) {
+	var sum, norm float64
+	for j := range h {
+		for i := range w {
+			k := 1.0
+			if i == 0 || i == w-1 {
+				k /= 2
+			}
+			if j == 0 || j == h-1 {
+				k /= 2
+			}
+			sum += k * a[j*w+i]
+			norm += k
+		}
+	}
+	mean := sum / norm
+	for k := range a {
+		a[k] -= mean
+	}
+}
+
+// removeMean фиксирует произвольную константу потенциала при условии
+// Неймана: среднее V по сетке равно нулю.
+func (g *Grid) removeMean() {
+	if g.Boundary != Neumann {
+		return
+	}
+	avg := 0.0
+	for _, v := range g.V {
+		avg += v
+	}
+	avg /= float64(len(g.V))
+	for k := range g.V {
+		g.V[k] -= avg
+	}
+}
+
+// ComputeField восстанавливает E = -∇V на узлах сетки.
+func (g *Grid) ComputeField() {
+	dx, dy := numdiff.GridGradient(g.V, g.W, g.H, g.Step)
+	for k := range dx {
+		g.Ex[k] = -dx[k]
+		g.Ey[k] = -dy[k]
+	}
+}
+
+// interp — билинейная интерполяция узловой величины; вне сетки ok = false.
+func (g *Grid) interp(a []float64, x, y float64) (float64, bool) {
+	fx := (x - g.X0) / g.Step
+	fy := (y - g.Y0) / g.Step
+	if fx < 0 || fy < 0 || fx > float64(g.W-1) || fy > float64(g.H-1) {
+		return 0, false
+	}
+	i, j := min(int(fx), g.W-2), min(int(fy), g.H-2)
+	tx, ty := fx-float64(i), fy-float64(j)
+	k := j*g.W + i
+	return a[k]*(1-tx)*(1-ty) + a[k+1]*tx*(1-ty) + a[k+g.W]*(1-tx)*ty + a[k+g.W+1]*tx*ty, true
+}
+
+func (g *Grid) FieldAt(x, y float64) (float64, float64) {
+	ex, ok := g.interp(g.Ex, x, y)
+	if !ok {
+		return 0, 0
+	}
+	ey, _ := g.interp(g.Ey, x, y)
+	return ex, ey
+}
+
+func (g *Grid) PotentialAt(x, y float64) float64 {
+	v, _ := g.interp(g.V, x, y)
+	return v
+}
@@ -0,0 +1,86 @@
+package poisson
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const testInvEps0 = 4 * math.Pi * 2000
+
+// dipole — сетка n×n с шагом 8 и диполем в центре, как у решателя
+// в приложении.
+func dipole(n int, b Boundary) *Grid {
+	half := float64(n-1) * 8 / 2
+	g := New(n, n, 8, -half, -half)
+	g.Boundary = b
+	g.Deposit(-60, 3, 1.0/200)
+	g.Deposit(60, -3, -1.0/200)
+	return g
+}
+
+// maxDiff — max|a - b|, отнесённый к max|b|.
+func maxDiff(a, b []float64) float64 {
+	var d, m float64
+	for k := range a {
+		d = math.Max(d, math.Abs(a[k]-b[k]))
+		m = math.Max(m, math.Abs(b[k]))
+	}
+	return d / m
+}
+
+func TestMultigridMatchesSOR(t *testing.T) {
+	for _, b := range []Boundary{Dirichlet, Neumann} {
+		ref := dipole(129, b)
+		ref.Solve(testInvEps0, OptimalOmega(129), 1e-9, 20000)
+		g := dipole(129, b)
+		g.Multigrid(testInvEps0, 1e-9, 50)
+		if g.Residual >= 1e-9 {
+			t.Fatalf("%v: невязка %.1e после %d циклов", b, g.Residual, g.Iterations)
+		}
+		if d := maxDiff(g.V, ref.V); d > 1e-6 {
+			t.Errorf("%v: отличие от SOR %.1e", b, d)
+		}
+	}
+}
+
+// Число V-циклов почти не зависит от размера сетки, в отличие от
+// итераций SOR.
+func TestMultigridCycles(t *testing.T) {
+	for _, b := range []Boundary{Dirichlet, Neumann} {
+		for _, n := range []int{65, 129, 257, 513} {
+			g := dipole(n, b)
+			start := time.Now()
+			g.Multigrid(testInvEps0, 1e-4, 50)
+			t.Logf("%v, %d×%d: %d циклов, невязка %.1e, %v", b, n, n, g.Iterations, g.Residual, time.Since(start))
+			if g.Iterations > 6 {
+				t.Errorf("%v, %d×%d: %d циклов", b, n, n, g.Iterations)
+			}
+		}
+	}
+}
+
+// После сдвига зарядов тёплый старт с прежнего решения сходится быстрее
+// холодного.
+func TestMultigridWarmStart(t *testing.T) {
+	g := dipole(513, Dirichlet)
+	g.Multigrid(testInvEps0, 1e-4, 50)
+	cold := g.Iterations
+
+	g.ClearCharge()
+	g.Deposit(-58, 3, 1.0/200)
+	g.Deposit(61, -4, -1.0/200)
+	g.Multigrid(testInvEps0, 1e-4, 50)
+	t.Logf("холодный старт %d циклов, тёплый %d", cold, g.Iterations)
+	if g.Iterations >= cold {
+		t.Errorf("тёплый старт: %d циклов, холодный %d", g.Iterations, cold)
+	}
+}
+
+func TestOddSizeFallsBack(t *testing.T) {
+	g := dipole(64, Dirichlet) // чётная сторона не огрубляется
+	g.Multigrid(testInvEps0, 1e-4, 2000)
+	if len(g.levels) != 1 || g.Residual >= 1e-4 {
+		t.Errorf("%d уровней, невязка %.1e", len(g.levels), g.Residual)
+	}
+}